@@ -0,0 +1,423 @@
+package bicep
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// formatPlaceholderRegex matches a placeholder of the format() function (e.g. "{0}"), without format specifiers.
+var formatPlaceholderRegex = regexp.MustCompile(`\{(\d+)\}`)
+
+// binaryOperators maps the ARM template functions emitted for Bicep binary operators to the operators.
+var binaryOperators = map[string]string{
+	"equals":          "==",
+	"less":            "<",
+	"lessorequals":    "<=",
+	"greater":         ">",
+	"greaterorequals": ">=",
+	"add":             "+",
+	"sub":             "-",
+	"mul":             "*",
+	"div":             "/",
+	"mod":             "%",
+}
+
+// variadicOperators maps the ARM template functions emitted for Bicep logical operators to the operators.
+var variadicOperators = map[string]string{
+	"and": "&&",
+	"or":  "||",
+}
+
+// ConvertExpression converts an ARM template expression, without the enclosing brackets
+// (e.g. "resourceGroup().location"), to a Bicep expression.
+//
+// References to parameters and variables become identifiers, format() becomes string interpolation,
+// the functions emitted for Bicep operators (e.g. equals(), not(), if()) become operators,
+// createObject() and createArray() become object and array literals, and user-defined functions
+// lose their "__bicep." namespace. Other function calls, property accesses and indexes are kept as they are.
+//
+// An error wrapping ErrUnsupportedExpression is returned for expressions that have no Bicep equivalent
+// (e.g. lambda functions) or cannot be parsed.
+func ConvertExpression(expression string) (string, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return "", err
+	}
+	p := &expressionParser{tokens: tokens}
+	node, err := p.parseExpression()
+	if err != nil {
+		return "", err
+	}
+	if p.pos != len(p.tokens) {
+		return "", fmt.Errorf("%w: unexpected %q in %q", ErrUnsupportedExpression, p.tokens[p.pos].text, expression)
+	}
+	return node.bicep()
+}
+
+// tokenKind is the kind of a token of an ARM template expression.
+type tokenKind int
+
+const (
+	identifierToken tokenKind = iota // identifierToken is a function name or a property name (e.g. "__bicep.f")
+	stringToken                      // stringToken is a string literal; its text is unescaped
+	numberToken                      // numberToken is an integer literal
+	symbolToken                      // symbolToken is one of ( ) , . [ ]
+)
+
+// token is a token of an ARM template expression.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits an ARM template expression into tokens.
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("(),.[]", r):
+			tokens = append(tokens, token{kind: symbolToken, text: string(r)})
+			i++
+		case r == '\'':
+			// String literal; a quote is escaped by doubling it
+			var builder strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("%w: unterminated string in %q", ErrUnsupportedExpression, expression)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						builder.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: stringToken, text: builder.String()})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: identifierToken, text: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q in %q", ErrUnsupportedExpression, r, expression)
+		}
+	}
+	return tokens, nil
+}
+
+// expressionParser is a recursive descent parser of ARM template expressions:
+//
+//	expression = primary { "." identifier | "[" expression "]" }
+//	primary    = string | number | identifier [ "." identifier ] "(" [ expression { "," expression } ] ")"
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token, or a zero token at the end of the expression.
+func (p *expressionParser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{}
+}
+
+// accept consumes the current token if it is the given symbol.
+func (p *expressionParser) accept(symbol string) bool {
+	if t := p.peek(); t.kind == symbolToken && t.text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the current token, which must be the given symbol.
+func (p *expressionParser) expect(symbol string) error {
+	if !p.accept(symbol) {
+		return fmt.Errorf("%w: expected %q", ErrUnsupportedExpression, symbol)
+	}
+	return nil
+}
+
+func (p *expressionParser) parseExpression() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.peek()
+			if t.kind != identifierToken {
+				return nil, fmt.Errorf("%w: expected a property name after '.'", ErrUnsupportedExpression)
+			}
+			p.pos++
+			n = &propertyNode{object: n, name: t.text}
+		case p.accept("["):
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{object: n, index: index}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *expressionParser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case stringToken:
+		p.pos++
+		return &stringNode{value: t.text}, nil
+	case numberToken:
+		p.pos++
+		return &numberNode{value: t.text}, nil
+	case identifierToken:
+		p.pos++
+		name := t.text
+		// Namespaced function (e.g. __bicep.myFunction or sys.concat)
+		if next := p.peek(); next.kind == symbolToken && next.text == "." &&
+			p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == identifierToken &&
+			p.pos+2 < len(p.tokens) && p.tokens[p.pos+2].text == "(" {
+			name += "." + p.tokens[p.pos+1].text
+			p.pos += 2
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var args []node
+		if !p.accept(")") {
+			for {
+				arg, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.accept(")") {
+					break
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return &callNode{name: name, args: args}, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrUnsupportedExpression)
+	}
+}
+
+// node is a node of the syntax tree of an ARM template expression.
+type node interface {
+	bicep() (string, error)
+}
+
+// stringNode is a string literal.
+type stringNode struct {
+	value string
+}
+
+func (n *stringNode) bicep() (string, error) {
+	return FormatString(n.value), nil
+}
+
+// numberNode is an integer literal.
+type numberNode struct {
+	value string
+}
+
+func (n *numberNode) bicep() (string, error) {
+	return n.value, nil
+}
+
+// propertyNode is a property access (e.g. resourceGroup().location).
+type propertyNode struct {
+	object node
+	name   string
+}
+
+func (n *propertyNode) bicep() (string, error) {
+	object, err := n.object.bicep()
+	if err != nil {
+		return "", err
+	}
+	return object + "." + n.name, nil
+}
+
+// indexNode is an index access (e.g. parameters('items')[0]).
+type indexNode struct {
+	object node
+	index  node
+}
+
+func (n *indexNode) bicep() (string, error) {
+	object, err := n.object.bicep()
+	if err != nil {
+		return "", err
+	}
+	index, err := n.index.bicep()
+	if err != nil {
+		return "", err
+	}
+	return object + "[" + index + "]", nil
+}
+
+// callNode is a function call.
+type callNode struct {
+	name string
+	args []node
+}
+
+//nolint:gocyclo // One case per ARM template function with a dedicated Bicep syntax.
+func (n *callNode) bicep() (string, error) {
+	name := strings.ToLower(n.name)
+	switch {
+	case name == "parameters" || name == "variables":
+		if len(n.args) == 1 {
+			if s, ok := n.args[0].(*stringNode); ok && identifierRegex.MatchString(s.value) {
+				return s.value, nil
+			}
+		}
+		return "", fmt.Errorf("%w: %s() with a computed name", ErrUnsupportedExpression, n.name)
+	case name == "true" || name == "false" || name == "null":
+		if len(n.args) == 0 {
+			return name, nil
+		}
+	case name == "format":
+		return n.interpolation()
+	case name == "not" && len(n.args) == 1:
+		operand, err := n.args[0].bicep()
+		if err != nil {
+			return "", err
+		}
+		return "!" + operand, nil
+	case name == "if" && len(n.args) == 3:
+		operands, err := convertAll(n.args)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s ? %s : %s)", operands[0], operands[1], operands[2]), nil
+	case binaryOperators[name] != "" && len(n.args) == 2:
+		operands, err := convertAll(n.args)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s %s %s)", operands[0], binaryOperators[name], operands[1]), nil
+	case variadicOperators[name] != "" && len(n.args) >= 2:
+		operands, err := convertAll(n.args)
+		if err != nil {
+			return "", err
+		}
+		return "(" + strings.Join(operands, " "+variadicOperators[name]+" ") + ")", nil
+	case name == "createarray":
+		items, err := convertAll(n.args)
+		if err != nil {
+			return "", err
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case name == "createobject" && len(n.args)%2 == 0:
+		properties := make([]string, 0, len(n.args)/2)
+		for i := 0; i < len(n.args); i += 2 {
+			key, ok := n.args[i].(*stringNode)
+			if !ok {
+				return "", fmt.Errorf("%w: createObject() with a computed key", ErrUnsupportedExpression)
+			}
+			value, err := n.args[i+1].bicep()
+			if err != nil {
+				return "", err
+			}
+			properties = append(properties, formatKey(key.value)+": "+value)
+		}
+		if len(properties) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(properties, ", ") + " }", nil
+	case name == "lambda" || name == "lambdavariables":
+		return "", fmt.Errorf("%w: %s()", ErrUnsupportedExpression, n.name)
+	}
+
+	functionName := n.name
+	if namespace, function, found := strings.Cut(n.name, "."); found && strings.EqualFold(namespace, "__bicep") {
+		functionName = function
+	}
+	args, err := convertAll(n.args)
+	if err != nil {
+		return "", err
+	}
+	return functionName + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+// interpolation converts a format() call with a literal format string to an interpolated Bicep string
+// (e.g. format('{0}-app', parameters('prefix')) to '${prefix}-app').
+func (n *callNode) interpolation() (string, error) {
+	if len(n.args) == 0 {
+		return "", fmt.Errorf("%w: format() without arguments", ErrUnsupportedExpression)
+	}
+	format, ok := n.args[0].(*stringNode)
+	if !ok || strings.Count(format.value, "{") != len(formatPlaceholderRegex.FindAllString(format.value, -1)) {
+		// Computed format string or format specifiers (e.g. "{0:N2}"): keep the function call
+		args, err := convertAll(n.args)
+		if err != nil {
+			return "", err
+		}
+		return "format(" + strings.Join(args, ", ") + ")", nil
+	}
+
+	args, err := convertAll(n.args[1:])
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	last := 0
+	for _, match := range formatPlaceholderRegex.FindAllStringSubmatchIndex(format.value, -1) {
+		index, _ := strconv.Atoi(format.value[match[2]:match[3]]) //nolint:errcheck // The index is validated by the regular expression.
+		if index >= len(args) {
+			return "", fmt.Errorf("%w: format() placeholder {%d} without argument", ErrUnsupportedExpression, index)
+		}
+		builder.WriteString(escapeString(format.value[last:match[0]]))
+		builder.WriteString("${" + args[index] + "}")
+		last = match[1]
+	}
+	builder.WriteString(escapeString(format.value[last:]))
+	builder.WriteByte('\'')
+	return builder.String(), nil
+}
+
+// convertAll converts every node to a Bicep expression.
+func convertAll(nodes []node) ([]string, error) {
+	converted := make([]string, len(nodes))
+	for i, n := range nodes {
+		var err error
+		if converted[i], err = n.bicep(); err != nil {
+			return nil, err
+		}
+	}
+	return converted, nil
+}
@@ -0,0 +1,60 @@
+package bicep
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+		wantErr    bool
+	}{
+		{name: "function_property", expression: "resourceGroup().location", expected: "resourceGroup().location"},
+		{name: "parameter", expression: "parameters('location')", expected: "location"},
+		{name: "variable_index", expression: "variables('zones')[0]", expected: "zones[0]"},
+		{name: "literals", expression: "createArray(1, -2, 'it''s', true(), null())", expected: "[1, -2, 'it\\'s', true, null]"},
+		{
+			name:       "format",
+			expression: "format('{0}-{1}-${{x}}', parameters('prefix'), uniqueString(resourceGroup().id))",
+			expected:   "format('{0}-{1}-\\${{x}}', prefix, uniqueString(resourceGroup().id))",
+		},
+		{
+			name:       "interpolation",
+			expression: "format('st{0}{1}', parameters('prefix'), uniqueString(resourceGroup().id))",
+			expected:   "'st${prefix}${uniqueString(resourceGroup().id)}'",
+		},
+		{name: "format_specifier", expression: "format('{0:N2}', parameters('n'))", expected: "format('{0:N2}', n)"},
+		{
+			name:       "operators",
+			expression: "if(and(equals(parameters('env'), 'prod'), not(parameters('dev'))), add(parameters('n'), 1), 0)",
+			expected:   "(((env == 'prod') && !dev) ? (n + 1) : 0)",
+		},
+		{name: "create_object", expression: "createObject('name', parameters('name'), 'sku-name', 'S1')", expected: "{ name: name, 'sku-name': 'S1' }"},
+		{name: "user_defined_function", expression: "__bicep.buildName(parameters('prefix'))", expected: "buildName(prefix)"},
+		{name: "lambda", expression: "filter(parameters('items'), lambda('i', lambdaVariables('i')))", wantErr: true},
+		{name: "computed_parameter_name", expression: "parameters(variables('name'))", wantErr: true},
+		{name: "unterminated_string", expression: "concat('a", wantErr: true},
+		{name: "trailing_tokens", expression: "utcNow() utcNow()", wantErr: true},
+		{name: "invalid_character", expression: "a + b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertExpression(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrUnsupportedExpression) {
+					t.Errorf("ConvertExpression() error = %v, expected ErrUnsupportedExpression", err)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("ConvertExpression() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package bicep
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FormatLoop returns the Bicep for-expression of a variable compiled to an ARM template copy loop,
+// from the name of the variable, the count, and the input of the copy loop
+// (e.g. "[for item in storageConfigs: item.name]").
+//
+// A count of length(collection) is converted back to a loop over the collection, with the item
+// replacing collection[copyIndex()]; any other count becomes a loop over range(0, count).
+// The indent is the indentation of the line on which the loop starts.
+//
+// An error wrapping ErrUnsupportedExpression is returned if the count or the input contains an ARM template
+// expression that cannot be converted to Bicep.
+func FormatLoop(name string, count, input any, indent string) (string, error) {
+	countValue, err := FormatValue(count, indent)
+	if err != nil {
+		return "", err
+	}
+	body, err := FormatValue(input, indent)
+	if err != nil {
+		return "", err
+	}
+
+	copyIndex := regexp.MustCompile(`copyIndex\('` + regexp.QuoteMeta(name) + `'\)`)
+	if collection, ok := unwrapCall(countValue, "length"); ok {
+		item := "item"
+		if strings.HasPrefix(collection, "range(") {
+			item = "i"
+		}
+		body = strings.ReplaceAll(body, collection+"[copyIndex('"+name+"')]", item)
+		if !copyIndex.MatchString(body) {
+			return fmt.Sprintf("[for %s in %s: %s]", item, collection, body), nil
+		}
+		body = copyIndex.ReplaceAllString(body, "index")
+		return fmt.Sprintf("[for (%s, index) in %s: %s]", item, collection, body), nil
+	}
+
+	body = copyIndex.ReplaceAllString(body, "i")
+	return fmt.Sprintf("[for i in range(0, %s): %s]", countValue, body), nil
+}
+
+// unwrapCall returns the argument of an expression that is a single call of a function with one argument
+// (e.g. "x.y" for "length(x.y)"), and whether the expression is such a call.
+func unwrapCall(expression, function string) (string, bool) {
+	argument, ok := strings.CutPrefix(expression, function+"(")
+	if !ok || !strings.HasSuffix(argument, ")") {
+		return "", false
+	}
+	argument = strings.TrimSuffix(argument, ")")
+
+	// The parentheses of the argument must be balanced, e.g. not "length(a) + length(b)"
+	depth := 0
+	for _, r := range argument {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return "", false
+			}
+		case ',':
+			if depth == 0 {
+				return "", false
+			}
+		}
+	}
+	return argument, depth == 0
+}
@@ -0,0 +1,95 @@
+package bicep
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatLoop(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable string
+		count    any
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "collection",
+			variable: "storageNames",
+			count:    "[length(variables('storageConfigs'))]",
+			input:    "[variables('storageConfigs')[copyIndex('storageNames')].name]",
+			expected: "[for item in storageConfigs: item.name]",
+		},
+		{
+			name:     "collection_with_index",
+			variable: "names",
+			count:    "[length(parameters('locations'))]",
+			input:    "[format('{0}-{1}', parameters('locations')[copyIndex('names')], copyIndex('names'))]",
+			expected: "[for (item, index) in locations: '${item}-${index}']",
+		},
+		{
+			name:     "range",
+			variable: "indexes",
+			count:    float64(3),
+			input:    "[copyIndex('indexes')]",
+			expected: "[for i in range(0, 3): i]",
+		},
+		{
+			name:     "range_object",
+			variable: "storageConfigs",
+			count:    "[length(range(0, length(parameters('locations'))))]",
+			input: map[string]any{
+				"name":     "[format('{0}{1}', parameters('namePrefix'), range(0, length(parameters('locations')))[copyIndex('storageConfigs')])]",
+				"location": "[parameters('locations')[range(0, length(parameters('locations')))[copyIndex('storageConfigs')]]]",
+			},
+			expected: "[for i in range(0, length(locations)): {\n  location: locations[i]\n  name: '${namePrefix}${i}'\n}]",
+		},
+		{
+			name:     "unsupported_expression",
+			variable: "items",
+			count:    float64(2),
+			input:    "[lambdaVariables('item')]",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatLoop(tt.variable, tt.count, tt.input, "")
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedExpression) {
+					t.Errorf("FormatLoop() error = %v, want ErrUnsupportedExpression", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatLoop() unexpected error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FormatLoop() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_unwrapCall(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+		ok         bool
+	}{
+		{expression: "length(x.y)", expected: "x.y", ok: true},
+		{expression: "length(range(0, 3))", expected: "range(0, 3)", ok: true},
+		{expression: "length(a) + length(b)", ok: false},
+		{expression: "length(a, b)", ok: false},
+		{expression: "max(a)", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, ok := unwrapCall(tt.expression, "length")
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("unwrapCall() = (%q, %v), expected (%q, %v)", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package bicep
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// ParamsFileName returns the name of the example parameters file of a Bicep file,
+// e.g. "main.example.bicepparam" for "main.bicep".
+func ParamsFileName(bicepFile string) string {
+	return strings.TrimSuffix(filepath.Base(bicepFile), filepath.Ext(bicepFile)) + ".example.bicepparam"
+}
+
+// FormatParamsFile returns the content of an example parameters file (.bicepparam) of a template,
+// with a using statement that references the Bicep file by its base name (the parameters file
+// is meant to be written next to it) and a param statement for every parameter.
+//
+// Required parameters get placeholder values that satisfy their type and constraints (see Placeholder),
+// and optional parameters get their default values. Default values that use ARM template expressions are
+// commented out, since most functions (e.g. resourceGroup()) are not available in parameters files and the
+// parameter falls back to its default value when omitted. The description of each parameter is written as a comment.
+func FormatParamsFile(template *types.Template, bicepFile string) (string, error) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "using %s\n", FormatString("./"+filepath.Base(bicepFile)))
+
+	var required, optional []*types.Parameter
+	for i := range template.Parameters {
+		if template.Parameters[i].IsRequired() {
+			required = append(required, &template.Parameters[i])
+		} else {
+			optional = append(optional, &template.Parameters[i])
+		}
+	}
+
+	if len(required) > 0 {
+		builder.WriteString("\n// Required parameters\n")
+		for _, parameter := range required {
+			builder.WriteString("\n")
+			writeDescription(&builder, parameter.GetDescription())
+			fmt.Fprintf(&builder, "param %s = %s\n", parameter.Name, Placeholder(parameter, template.UserDefinedDataTypes, ""))
+		}
+	}
+
+	if len(optional) > 0 {
+		builder.WriteString("\n// Optional parameters\n")
+		for _, parameter := range optional {
+			builder.WriteString("\n")
+			writeDescription(&builder, parameter.GetDescription())
+			value, err := FormatValue(parameter.DefaultValue, "")
+			switch {
+			case errors.Is(err, ErrUnsupportedExpression):
+				fmt.Fprintf(&builder, "// param %s: the default value is an expression without a Bicep equivalent\n", parameter.Name)
+			case err != nil:
+				return "", fmt.Errorf("failed to format the default value of %s: %w", parameter.Name, err)
+			case containsExpression(parameter.DefaultValue):
+				builder.WriteString(commentLines(fmt.Sprintf("param %s = %s", parameter.Name, value)))
+			default:
+				fmt.Fprintf(&builder, "param %s = %s\n", parameter.Name, value)
+			}
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// writeDescription writes a description as comment lines, if it is not empty.
+func writeDescription(builder *strings.Builder, description string) {
+	description = strings.TrimSpace(strings.ReplaceAll(description, "\r\n", "\n"))
+	if description != "" {
+		builder.WriteString(commentLines(description))
+	}
+}
+
+// commentLines prefixes every line of a text with "// ".
+func commentLines(text string) string {
+	var builder strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		builder.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+	return builder.String()
+}
+
+// containsExpression reports whether a value decoded from an ARM template contains an ARM template expression.
+func containsExpression(value any) bool {
+	switch v := normalize(value).(type) {
+	case string:
+		_, ok := armExpression(v)
+		return ok
+	case []any:
+		for _, item := range v {
+			if containsExpression(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, property := range v {
+			if containsExpression(property) {
+				return true
+			}
+		}
+	}
+	return false
+}
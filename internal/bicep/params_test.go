@@ -0,0 +1,114 @@
+package bicep
+
+import (
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func TestParamsFileName(t *testing.T) {
+	tests := []struct {
+		bicepFile string
+		expected  string
+	}{
+		{bicepFile: "main.bicep", expected: "main.example.bicepparam"},
+		{bicepFile: "./modules/network/vnet.bicep", expected: "vnet.example.bicepparam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bicepFile, func(t *testing.T) {
+			if got := ParamsFileName(tt.bicepFile); got != tt.expected {
+				t.Errorf("ParamsFileName() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatParamsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *types.Template
+		expected string
+	}{
+		{
+			name:     "no_parameters",
+			template: &types.Template{},
+			expected: "using './main.bicep'\n",
+		},
+		{
+			name: "parameters",
+			template: &types.Template{
+				Parameters: []types.Parameter{
+					{
+						Name:     "name",
+						Type:     "string",
+						Metadata: &types.Metadata{Description: strPtr("The name of the app.\nMust be unique.")},
+					},
+					{
+						Name:         "location",
+						Type:         "string",
+						DefaultValue: "[resourceGroup().location]",
+						Metadata:     &types.Metadata{Description: strPtr("The location.")},
+					},
+					{
+						Name:         "names",
+						Type:         "array",
+						DefaultValue: "[map(createArray('a'), lambda('x', lambdaVariables('x')))]",
+					},
+					{
+						Name:     "sku",
+						Type:     "string",
+						Nullable: true,
+					},
+					{
+						Name:         "tags",
+						Type:         "object",
+						DefaultValue: map[string]any{"env": "dev"},
+					},
+				},
+			},
+			expected: "using './main.bicep'\n" +
+				"\n// Required parameters\n" +
+				"\n// The name of the app.\n// Must be unique.\nparam name = '<name>'\n" +
+				"\n// Optional parameters\n" +
+				"\n// The location.\n// param location = resourceGroup().location\n" +
+				"\n// param names: the default value is an expression without a Bicep equivalent\n" +
+				"\nparam sku = null\n" +
+				"\nparam tags = {\n  env: 'dev'\n}\n",
+		},
+		{
+			name: "user_defined_data_types",
+			template: &types.Template{
+				Parameters: []types.Parameter{
+					{Name: "environment", Type: "#/definitions/env"},
+					{
+						Name: "cfg",
+						Type: "object",
+						Properties: []types.UserDefinedDataTypeProperty{
+							{Name: "tier", Type: "string"},
+							{Name: "env", Type: "#/definitions/env"},
+						},
+						Sealed: true,
+					},
+				},
+				UserDefinedDataTypes: []types.UserDefinedDataType{
+					{Name: "env", Type: "string", AllowedValues: []any{"dev", "prod"}},
+				},
+			},
+			expected: "using './main.bicep'\n" +
+				"\n// Required parameters\n" +
+				"\nparam environment = 'dev'\n" +
+				"\nparam cfg = {\n  tier: '<tier>'\n  env: 'dev'\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatParamsFile(tt.template, "./modules/main.bicep")
+			if err != nil {
+				t.Fatalf("FormatParamsFile() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FormatParamsFile() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
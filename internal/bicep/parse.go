@@ -0,0 +1,450 @@
+package bicep
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errNotLiteral is returned by the parser of literal values when a value is an expression (e.g. a function call).
+var errNotLiteral = errors.New("not a literal value")
+
+// ParamsFile is the content of a parameters file (.bicepparam): the path of the Bicep file of its using statement
+// (empty for 'using none'), and its param statements in order.
+type ParamsFile struct {
+	Using  string
+	Params []ParamStatement
+}
+
+// ParamStatement is a param statement of a parameters file, with its 1-based line.
+// The value of a literal (a string, an integer, a boolean, null, or an array or object of literals) is decoded
+// as from JSON: numbers are float64, arrays are []any, and objects are map[string]any.
+// Any other value (e.g. readEnvironmentVariable('TOKEN') or an interpolated string) is an expression, whose value is nil.
+type ParamStatement struct {
+	Name       string
+	Line       int
+	Value      any
+	Expression bool
+}
+
+// ParseParamsFile parses the using and param statements of a parameters file (.bicepparam);
+// the other statements (e.g. var and import statements) are skipped.
+// An error is returned if the file is not valid Bicep syntax, e.g. for an unterminated string.
+func ParseParamsFile(content string) (*ParamsFile, error) {
+	p := &literalParser{src: strings.ReplaceAll(content, "\r\n", "\n"), line: 1}
+	file := &ParamsFile{}
+	for {
+		if err := p.skipSpace(true); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return file, nil
+		}
+
+		start := p.pos
+		switch keyword := p.identifier(); keyword {
+		case "using":
+			if err := p.skipSpace(false); err != nil {
+				return nil, err
+			}
+			if p.identifier() == "none" {
+				break
+			}
+			using, err := p.parseValue()
+			path, ok := using.(string)
+			if err != nil || !ok {
+				return nil, fmt.Errorf("line %d: the using statement must reference a Bicep file by a string literal", p.line)
+			}
+			file.Using = path
+		case "param":
+			if err := p.skipSpace(false); err != nil {
+				return nil, err
+			}
+			line := p.line
+			name := p.identifier()
+			if name == "" {
+				return nil, fmt.Errorf("line %d: expected the name of the parameter", p.line)
+			}
+			if err := p.skipSpace(false); err != nil {
+				return nil, err
+			}
+			if !p.consume('=') {
+				return nil, fmt.Errorf("line %d: expected '=' after the parameter %s", p.line, name)
+			}
+			statement, err := p.parseStatementValue()
+			if err != nil {
+				return nil, err
+			}
+			statement.Name, statement.Line = name, line
+			file.Params = append(file.Params, statement)
+			continue
+		default:
+			p.pos = start
+		}
+		if err := p.skipExpression(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// literalParser parses the literal values of Bicep syntax, counting the lines.
+type literalParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// parseStatementValue parses the value of a statement, which ends with its line.
+// A value that is not a literal is skipped and returned as an expression.
+func (p *literalParser) parseStatementValue() (ParamStatement, error) {
+	if err := p.skipSpace(false); err != nil {
+		return ParamStatement{}, err
+	}
+	start, line := p.pos, p.line
+	value, err := p.parseValue()
+	if err == nil {
+		err = p.skipSpace(false)
+		if err == nil && p.pos < len(p.src) && p.src[p.pos] != '\n' {
+			err = errNotLiteral
+		}
+	}
+	if err == nil {
+		return ParamStatement{Value: value}, nil
+	}
+	if !errors.Is(err, errNotLiteral) {
+		return ParamStatement{}, err
+	}
+	p.pos, p.line = start, line
+	if err := p.skipExpression(); err != nil {
+		return ParamStatement{}, err
+	}
+	return ParamStatement{Expression: true}, nil
+}
+
+// parseValue parses a literal value; errNotLiteral is returned at the first token that is not part of a literal.
+func (p *literalParser) parseValue() (any, error) {
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("line %d: unexpected end of file", p.line)
+	}
+	c := p.src[p.pos]
+	switch {
+	case c == '\'':
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseObject()
+	case c == '-' || isDigit(c):
+		return p.parseInteger()
+	}
+
+	switch p.identifier() {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return nil, errNotLiteral
+	}
+}
+
+// parseString parses a string literal, or a multi-line string delimited by three quotes, which has no escape sequences.
+func (p *literalParser) parseString() (string, error) {
+	line := p.line
+	if strings.HasPrefix(p.src[p.pos:], "'''") {
+		end := strings.Index(p.src[p.pos+3:], "'''")
+		if end < 0 {
+			return "", fmt.Errorf("line %d: unterminated multi-line string", line)
+		}
+		s := p.src[p.pos+3 : p.pos+3+end]
+		p.line += strings.Count(s, "\n")
+		p.pos += end + 6
+		// The line break that follows the opening quotes is not part of the string
+		return strings.TrimPrefix(s, "\n"), nil
+	}
+
+	var builder strings.Builder
+	p.pos++
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.pos++
+			return builder.String(), nil
+		case c == '\n':
+			return "", fmt.Errorf("line %d: unterminated string", line)
+		case c == '$' && strings.HasPrefix(p.src[p.pos:], "${"):
+			return "", errNotLiteral
+		case c == '\\' && p.pos+1 < len(p.src):
+			escaped, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(escaped)
+			continue
+		default:
+			builder.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", fmt.Errorf("line %d: unterminated string", line)
+}
+
+// parseEscape parses an escape sequence of a string literal.
+func (p *literalParser) parseEscape() (string, error) {
+	c := p.src[p.pos+1]
+	p.pos += 2
+	switch c {
+	case '\\', '\'', '$':
+		return string(c), nil
+	case 'n':
+		return "\n", nil
+	case 'r':
+		return "\r", nil
+	case 't':
+		return "\t", nil
+	case 'u':
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if !strings.HasPrefix(p.src[p.pos:], "{") || end < 0 {
+			return "", fmt.Errorf("line %d: invalid unicode escape sequence", p.line)
+		}
+		code, err := strconv.ParseUint(p.src[p.pos+1:p.pos+end], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("line %d: invalid unicode escape sequence", p.line)
+		}
+		p.pos += end + 1
+		return string(rune(code)), nil
+	default:
+		return "", fmt.Errorf("line %d: invalid escape sequence \\%c", p.line, c)
+	}
+}
+
+// parseInteger parses an integer literal, optionally negative.
+func (p *literalParser) parseInteger() (float64, error) {
+	start := p.pos
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == digits {
+		return 0, errNotLiteral
+	}
+	value, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return 0, errNotLiteral
+	}
+	return value, nil
+}
+
+// parseArray parses an array literal, whose items are separated by line breaks or commas.
+func (p *literalParser) parseArray() ([]any, error) {
+	p.pos++
+	items := []any{}
+	for {
+		if err := p.skipSeparators(); err != nil {
+			return nil, err
+		}
+		if p.consume(']') {
+			return items, nil
+		}
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if err := p.endItem(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseObject parses an object literal, whose properties are separated by line breaks or commas.
+func (p *literalParser) parseObject() (map[string]any, error) {
+	p.pos++
+	object := map[string]any{}
+	for {
+		if err := p.skipSeparators(); err != nil {
+			return nil, err
+		}
+		if p.consume('}') {
+			return object, nil
+		}
+
+		var key string
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			var err error
+			if key, err = p.parseString(); err != nil {
+				return nil, err
+			}
+		} else if key = p.identifier(); key == "" {
+			return nil, errNotLiteral
+		}
+		if err := p.skipSpace(false); err != nil {
+			return nil, err
+		}
+		if !p.consume(':') {
+			return nil, errNotLiteral
+		}
+		if err := p.skipSpace(false); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		object[key] = value
+		if err := p.endItem('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// endItem checks that an item of an array or an object is followed by a separator or the closing bracket.
+func (p *literalParser) endItem(closing byte) error {
+	if err := p.skipSpace(false); err != nil {
+		return err
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == '\n' || p.src[p.pos] == ',' || p.src[p.pos] == closing) {
+		return nil
+	}
+	return errNotLiteral
+}
+
+// skipSeparators skips the whitespace, comments, line breaks, and commas between the items of an array or an object.
+func (p *literalParser) skipSeparators() error {
+	for {
+		if err := p.skipSpace(true); err != nil {
+			return err
+		}
+		if !p.consume(',') {
+			return nil
+		}
+	}
+}
+
+// skipExpression skips the rest of a statement: up to the end of the line that is outside brackets and strings.
+func (p *literalParser) skipExpression() error {
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			if _, err := p.parseString(); err != nil && !errors.Is(err, errNotLiteral) {
+				return err
+			} else if err != nil {
+				// Skip the interpolated string, including the expressions between its braces
+				if err := p.skipInterpolation(); err != nil {
+					return err
+				}
+			}
+			continue
+		case c == '/' && (strings.HasPrefix(p.src[p.pos:], "//") || strings.HasPrefix(p.src[p.pos:], "/*")):
+			if err := p.skipSpace(false); err != nil {
+				return err
+			}
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == '\n':
+			if depth <= 0 {
+				return nil
+			}
+			p.line++
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// skipInterpolation skips the rest of an interpolated string, from its first "${".
+func (p *literalParser) skipInterpolation() error {
+	line := p.line
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && depth == 0:
+			p.pos++
+		case c == '$' && depth == 0 && strings.HasPrefix(p.src[p.pos:], "${"):
+			depth++
+			p.pos++
+		case c == '{' && depth > 0:
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == '\'' && depth == 0:
+			p.pos++
+			return nil
+		case c == '\n':
+			return fmt.Errorf("line %d: unterminated string", line)
+		}
+		p.pos++
+	}
+	return fmt.Errorf("line %d: unterminated string", line)
+}
+
+// skipSpace skips whitespace and comments, and line breaks if newlines is set.
+func (p *literalParser) skipSpace(newlines bool) error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end
+			}
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated comment", p.line)
+			}
+			p.line += strings.Count(p.src[p.pos:p.pos+2+end], "\n")
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// identifier consumes an identifier (or keyword) and returns it, or returns an empty string.
+func (p *literalParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := rune(p.src[p.pos])
+		if r != '_' && !unicode.IsLetter(r) && (p.pos == start || !unicode.IsDigit(r)) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// consume consumes the given character if it is the next one.
+func (p *literalParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// isDigit reports whether a character is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
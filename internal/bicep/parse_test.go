@@ -0,0 +1,80 @@
+package bicep
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseParamsFile(t *testing.T) {
+	content := `using './main.bicep'
+
+/* The parameters
+   of the storage account */
+param name = 'st\'001' // a comment
+param location = readEnvironmentVariable('LOCATION', 'westeurope')
+param retention = -7
+param enabled = true
+param tags = {
+  owner: 'platform'
+  'cost-center': 'it'
+}
+param zones = ['1', '2'
+  '3']
+param prefix = 'st-${name}'
+var suffix = {
+  a: 1
+}
+param script = '''
+echo hello
+'''
+param settings = {
+  tier: toLower('Hot')
+}
+param sku = null
+`
+	expected := &ParamsFile{
+		Using: "./main.bicep",
+		Params: []ParamStatement{
+			{Name: "name", Line: 5, Value: "st'001"},
+			{Name: "location", Line: 6, Expression: true},
+			{Name: "retention", Line: 7, Value: float64(-7)},
+			{Name: "enabled", Line: 8, Value: true},
+			{Name: "tags", Line: 9, Value: map[string]any{"owner": "platform", "cost-center": "it"}},
+			{Name: "zones", Line: 13, Value: []any{"1", "2", "3"}},
+			{Name: "prefix", Line: 15, Expression: true},
+			{Name: "script", Line: 19, Value: "echo hello\n"},
+			{Name: "settings", Line: 22, Expression: true},
+			{Name: "sku", Line: 25},
+		},
+	}
+
+	got, err := ParseParamsFile(content)
+	if err != nil {
+		t.Fatalf("ParseParamsFile() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseParamsFile() = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestParseParamsFile_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "unterminated_string", content: "using './main.bicep'\nparam name = 'abc\n", expected: "line 2: unterminated string"},
+		{name: "unterminated_comment", content: "/* using './main.bicep'\n", expected: "line 1: unterminated comment"},
+		{name: "missing_value", content: "param name\n", expected: "line 1: expected '=' after the parameter name"},
+		{name: "using_expression", content: "using concat('a', 'b')\n", expected: "the using statement must reference a Bicep file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseParamsFile(tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ParseParamsFile() error = %v, expected to contain %q", err, tt.expected)
+			}
+		})
+	}
+}
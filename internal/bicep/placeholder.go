@@ -0,0 +1,143 @@
+package bicep
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// maxPlaceholderDepth limits the nesting of placeholders, e.g. for recursive user-defined data types.
+const maxPlaceholderDepth = 8
+
+// Placeholder returns a Bicep value for a required parameter: a value of the parameter's type
+// that satisfies its constraints, so that a usage example with the placeholder compiles.
+//
+// The first allowed value is used if there are allowed values. Strings are named after the parameter
+// (e.g. '<location>') and fitted to the length constraints, integers to the value constraints,
+// and arrays get as many items as their minimum length. A user-defined data type (resolved from the template's
+// data types) or an inline object type yields an object with a placeholder for each of its required properties;
+// as only declared properties are given, the object also satisfies a sealed type.
+// The indent is the indentation of the line on which the value starts.
+func Placeholder(parameter *types.Parameter, dataTypes []types.UserDefinedDataType, indent string) string {
+	g := &placeholderGenerator{dataTypes: dataTypes}
+	if len(parameter.Properties) > 0 && len(parameter.AllowedValues) == 0 {
+		return g.objectPlaceholder(parameter.Properties, indent, 0)
+	}
+	return g.placeholder(parameter.Name, parameter.Constraints(), indent, 0)
+}
+
+// placeholderGenerator generates placeholders, resolving user-defined data types by name.
+type placeholderGenerator struct {
+	dataTypes []types.UserDefinedDataType
+}
+
+//nolint:gocyclo // One case per type.
+func (g *placeholderGenerator) placeholder(name string, c *types.Constraints, indent string, depth int) string {
+	if len(c.AllowedValues) > 0 {
+		if value, err := FormatValue(c.AllowedValues[0], indent); err == nil {
+			return value
+		}
+	}
+
+	if ref, ok := types.DefinitionName(c.Type); ok {
+		dataType := g.dataType(ref)
+		if dataType == nil || depth >= maxPlaceholderDepth {
+			return "{}"
+		}
+		return g.dataTypePlaceholder(dataType, name, indent, depth+1)
+	}
+
+	switch strings.ToLower(c.Type) {
+	case "string", "securestring":
+		return FormatString(fitLength("<"+name+">", c.MinLength, c.MaxLength))
+	case "int":
+		value := 0
+		if c.MinValue != nil && value < *c.MinValue {
+			value = *c.MinValue
+		}
+		if c.MaxValue != nil && value > *c.MaxValue {
+			value = *c.MaxValue
+		}
+		return strconv.Itoa(value)
+	case "bool":
+		return "false"
+	case "array":
+		count := 0
+		if c.MinLength != nil {
+			count = *c.MinLength
+		}
+		if count == 0 || depth >= maxPlaceholderDepth {
+			return "[]"
+		}
+		item := types.Constraints{Type: "any"}
+		if c.Items != nil && c.Items.Ref != nil {
+			item.Type = *c.Items.Ref
+		} else if c.Items != nil && c.Items.Type != nil {
+			item.Type = *c.Items.Type
+		}
+		var builder strings.Builder
+		builder.WriteString("[\n")
+		for range count {
+			builder.WriteString(indent + indentUnit + g.placeholder(name+"_item", &item, indent+indentUnit, depth+1) + "\n")
+		}
+		builder.WriteString(indent + "]")
+		return builder.String()
+	case "object", "secureobject":
+		return "{}"
+	default:
+		// 'any' and unknown types
+		return "null"
+	}
+}
+
+// dataTypePlaceholder returns the placeholder of a user-defined data type: an object with its required properties,
+// or a value of its underlying type.
+func (g *placeholderGenerator) dataTypePlaceholder(dataType *types.UserDefinedDataType, name, indent string, depth int) string {
+	if len(dataType.Properties) == 0 {
+		return g.placeholder(name, dataType.Constraints(), indent, depth)
+	}
+	return g.objectPlaceholder(dataType.Properties, indent, depth)
+}
+
+// objectPlaceholder returns an object with a placeholder for each of the required properties of an object type.
+func (g *placeholderGenerator) objectPlaceholder(properties []types.UserDefinedDataTypeProperty, indent string, depth int) string {
+	var builder strings.Builder
+	for i := range properties {
+		property := &properties[i]
+		if property.Nullable {
+			continue
+		}
+		value := g.placeholder(property.Name, property.Constraints(), indent+indentUnit, depth)
+		builder.WriteString(indent + indentUnit + formatKey(property.Name) + ": " + value + "\n")
+	}
+	if builder.Len() == 0 {
+		return "{}"
+	}
+	return "{\n" + builder.String() + indent + "}"
+}
+
+// dataType returns the user-defined data type with the given name, or nil if there is none.
+func (g *placeholderGenerator) dataType(name string) *types.UserDefinedDataType {
+	for i := range g.dataTypes {
+		if g.dataTypes[i].Name == name {
+			return &g.dataTypes[i]
+		}
+	}
+	return nil
+}
+
+// fitLength truncates or pads (with 'x') a placeholder string to satisfy the length constraints.
+// A placeholder that is too long loses its angle brackets before being truncated.
+func fitLength(s string, minLength, maxLength *int) string {
+	if maxLength != nil && len(s) > *maxLength {
+		s = strings.Trim(s, "<>")
+		if len(s) > *maxLength {
+			s = s[:max(*maxLength, 0)]
+		}
+	}
+	if minLength != nil && len(s) < *minLength {
+		s += strings.Repeat("x", *minLength-len(s))
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+package bicep
+
+import (
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestPlaceholder(t *testing.T) {
+	dataTypes := []types.UserDefinedDataType{
+		{
+			Name: "config",
+			Type: "object",
+			Properties: []types.UserDefinedDataTypeProperty{
+				{Name: "name", Type: "string"},
+				{Name: "tier", Type: "string", AllowedValues: []any{"Basic", "Standard"}},
+				{Name: "comment", Type: "string", Nullable: true},
+				{Name: "port", Type: "#/definitions/port"},
+			},
+		},
+		{Name: "port", Type: "int", MinValue: intPtr(1024), MaxValue: intPtr(65535)},
+		{Name: "env", Type: "string", AllowedValues: []any{"dev", "prod"}},
+		{
+			Name:       "node",
+			Type:       "object",
+			Properties: []types.UserDefinedDataTypeProperty{{Name: "next", Type: "#/definitions/node"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		parameter types.Parameter
+		expected  string
+	}{
+		{name: "string", parameter: types.Parameter{Name: "location", Type: "string"}, expected: "'<location>'"},
+		{name: "secure_string", parameter: types.Parameter{Name: "password", Type: "securestring"}, expected: "'<password>'"},
+		{
+			name:      "string_max_length",
+			parameter: types.Parameter{Name: "storageAccountName", Type: "string", MaxLength: intPtr(10)},
+			expected:  "'storageAcc'",
+		},
+		{
+			name:      "string_min_length",
+			parameter: types.Parameter{Name: "id", Type: "string", MinLength: intPtr(8)},
+			expected:  "'<id>xxxx'",
+		},
+		{
+			name:      "allowed_values",
+			parameter: types.Parameter{Name: "sku", Type: "string", AllowedValues: []any{"Standard_LRS", "Standard_GRS"}},
+			expected:  "'Standard_LRS'",
+		},
+		{name: "int", parameter: types.Parameter{Name: "count", Type: "int"}, expected: "0"},
+		{name: "int_min_value", parameter: types.Parameter{Name: "count", Type: "int", MinValue: intPtr(3)}, expected: "3"},
+		{name: "int_max_value", parameter: types.Parameter{Name: "offset", Type: "int", MaxValue: intPtr(-1)}, expected: "-1"},
+		{name: "bool", parameter: types.Parameter{Name: "enabled", Type: "bool"}, expected: "false"},
+		{name: "object", parameter: types.Parameter{Name: "tags", Type: "object"}, expected: "{}"},
+		{name: "any", parameter: types.Parameter{Name: "value", Type: "any"}, expected: "null"},
+		{name: "array", parameter: types.Parameter{Name: "zones", Type: "array"}, expected: "[]"},
+		{
+			name: "array_min_length",
+			parameter: types.Parameter{
+				Name: "zones", Type: "array", MinLength: intPtr(2), Items: &types.Items{Type: strPtr("string")},
+			},
+			expected: "[\n    '<zones_item>'\n    '<zones_item>'\n  ]",
+		},
+		{
+			name:      "user_defined_data_type",
+			parameter: types.Parameter{Name: "settings", Type: "#/definitions/config"},
+			expected:  "{\n    name: '<name>'\n    tier: 'Basic'\n    port: 1024\n  }",
+		},
+		{
+			name:      "union_user_defined_data_type",
+			parameter: types.Parameter{Name: "environment", Type: "#/definitions/env"},
+			expected:  "'dev'",
+		},
+		{
+			name: "inline_object",
+			parameter: types.Parameter{
+				Name: "cfg",
+				Type: "object",
+				Properties: []types.UserDefinedDataTypeProperty{
+					{Name: "tier", Type: "string"},
+					{Name: "env", Type: "#/definitions/env"},
+					{Name: "comment", Type: "string", Nullable: true},
+				},
+				Sealed: true,
+			},
+			expected: "{\n    tier: '<tier>'\n    env: 'dev'\n  }",
+		},
+		{
+			name:      "recursive_user_defined_data_type",
+			parameter: types.Parameter{Name: "list", Type: "#/definitions/node"},
+			expected: "{\n    next: {\n      next: {\n        next: {\n          next: {\n            next: {\n" +
+				"              next: {\n                next: {\n                  next: {}\n" +
+				"                }\n              }\n            }\n          }\n        }\n      }\n    }\n  }",
+		},
+		{name: "unknown_user_defined_data_type", parameter: types.Parameter{Name: "x", Type: "#/definitions/missing"}, expected: "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Placeholder(&tt.parameter, dataTypes, "  "); got != tt.expected {
+				t.Errorf("Placeholder() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
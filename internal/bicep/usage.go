@@ -0,0 +1,97 @@
+package bicep
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// FormatModuleUsage returns a Bicep example of a module call of a template.
+//
+// The example is valid Bicep: the required parameters get placeholder values that satisfy their type and constraints
+// (see Placeholder), and the default values of the optional parameters are converted to Bicep.
+// A default value with ARM template expressions (e.g. resourceGroup().location, or a reference to another parameter,
+// which is not in scope of the module call) is commented out, as is a default value without a Bicep equivalent
+// (e.g. a lambda function).
+// The module is referenced by its registry reference if the template has one, or otherwise by a placeholder.
+func FormatModuleUsage(template *types.Template) (string, error) {
+	var builder strings.Builder
+
+	reference := "'path_to_module | container_registry_reference'"
+	if template.ModuleReference != "" {
+		reference = FormatString(template.ModuleReference)
+	}
+
+	fmt.Fprintf(&builder, "module reference_name %s = {\n", reference)
+	builder.WriteString("  name: 'deployment_name'\n")
+	if scope := scopeExpression(template.TargetScope); scope != "" {
+		fmt.Fprintf(&builder, "  scope: %s\n", scope)
+	}
+	builder.WriteString("  params: {\n")
+
+	// Required parameters (without a default value), with a placeholder value.
+	builder.WriteString("    // Required parameters\n")
+	for i := range template.Parameters {
+		parameter := &template.Parameters[i]
+		if parameter.IsRequired() {
+			fmt.Fprintf(&builder, "    %s: %s\n", parameter.Name, Placeholder(parameter, template.UserDefinedDataTypes, "    "))
+		}
+	}
+
+	// Optional parameters (with a default value).
+	builder.WriteString("\n    // Optional parameters\n")
+	for i := range template.Parameters {
+		parameter := &template.Parameters[i]
+		if parameter.IsRequired() {
+			continue
+		}
+		defaultValue, err := FormatValue(parameter.DefaultValue, "    ")
+		if err != nil {
+			if !errors.Is(err, ErrUnsupportedExpression) {
+				return "", fmt.Errorf("failed to format the default value of %s: %w", parameter.Name, err)
+			}
+			fmt.Fprintf(&builder, "    // %s: the default value is an expression without a Bicep equivalent\n", parameter.Name)
+			continue
+		}
+		property := fmt.Sprintf("%s: %s", parameter.Name, defaultValue)
+		if containsExpression(parameter.DefaultValue) {
+			property = commentProperty(property, "    ")
+		}
+		fmt.Fprintf(&builder, "    %s\n", property)
+	}
+	builder.WriteString("  }\n")
+	builder.WriteString("}\n")
+
+	return builder.String(), nil
+}
+
+// commentProperty comments out every line of a property written at the given indentation,
+// keeping the comment markers aligned with the property (e.g. "// tags: {", "//   env: 'dev'", "// }").
+func commentProperty(property, indent string) string {
+	lines := strings.Split(property, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimPrefix(line, indent), " ")
+		if i > 0 {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scopeExpression returns the scope expression of a module deployed at the given target scope,
+// e.g. "subscription('subscription_id')" for SubscriptionScope.
+// It returns an empty string for the default resource group scope, which needs no scope expression.
+func scopeExpression(targetScope types.TargetScope) string {
+	switch targetScope {
+	case types.SubscriptionScope:
+		return "subscription('subscription_id')"
+	case types.ManagementGroupScope:
+		return "managementGroup('management_group_id')"
+	case types.TenantScope:
+		return "tenant()"
+	default:
+		return ""
+	}
+}
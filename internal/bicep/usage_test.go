@@ -0,0 +1,53 @@
+package bicep
+
+import (
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_scopeExpression(t *testing.T) {
+	tests := []struct {
+		targetScope types.TargetScope
+		expected    string
+	}{
+		{targetScope: "", expected: ""},
+		{targetScope: types.ResourceGroupScope, expected: ""},
+		{targetScope: types.SubscriptionScope, expected: "subscription('subscription_id')"},
+		{targetScope: types.ManagementGroupScope, expected: "managementGroup('management_group_id')"},
+		{targetScope: types.TenantScope, expected: "tenant()"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.targetScope), func(t *testing.T) {
+			if got := scopeExpression(tt.targetScope); got != tt.expected {
+				t.Errorf("scopeExpression() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_commentProperty(t *testing.T) {
+	tests := []struct {
+		name     string
+		property string
+		expected string
+	}{
+		{
+			name:     "single_line",
+			property: "location: resourceGroup().location",
+			expected: "// location: resourceGroup().location",
+		},
+		{
+			name:     "multiline",
+			property: "tags: {\n      environment: environment\n    }",
+			expected: "// tags: {\n    //   environment: environment\n    // }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commentProperty(tt.property, "    "); got != tt.expected {
+				t.Errorf("commentProperty() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Package bicep provides functionality to write Bicep syntax from the content of ARM templates:
+literal values, expressions converted from ARM template expressions, and placeholder values
+that satisfy the type and constraints of a parameter.
+*/
+package bicep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indentUnit is the indentation of each nesting level of objects and arrays.
+const indentUnit = "  "
+
+// identifierRegex matches a Bicep identifier, which can be used as an object key without quotes.
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrUnsupportedExpression is returned when an ARM template expression cannot be converted to Bicep.
+var ErrUnsupportedExpression = errors.New("unsupported ARM template expression")
+
+// FormatValue returns the Bicep representation of a value decoded from an ARM template (e.g. a default value).
+//
+// Strings are quoted and escaped, objects and arrays are written on multiple lines,
+// and ARM template expressions (e.g. "[resourceGroup().location]") are converted to Bicep expressions.
+// The indent is the indentation of the line on which the value starts; nested lines are indented further.
+//
+// An error wrapping ErrUnsupportedExpression is returned if the value contains an ARM template expression
+// that cannot be converted to Bicep.
+func FormatValue(value any, indent string) (string, error) {
+	var builder strings.Builder
+	if err := writeValue(&builder, normalize(value), indent); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// FormatString returns a Bicep string literal, quoting and escaping the string.
+func FormatString(s string) string {
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	builder.WriteString(escapeString(s))
+	builder.WriteByte('\'')
+	return builder.String()
+}
+
+// writeValue writes the Bicep representation of a normalized value.
+func writeValue(builder *strings.Builder, value any, indent string) error {
+	switch v := value.(type) {
+	case nil:
+		builder.WriteString("null")
+	case bool:
+		builder.WriteString(strconv.FormatBool(v))
+	case float64:
+		builder.WriteString(formatNumber(v))
+	case string:
+		if expression, ok := armExpression(v); ok {
+			converted, err := ConvertExpression(expression)
+			if err != nil {
+				return err
+			}
+			builder.WriteString(converted)
+		} else {
+			builder.WriteString(FormatString(unescapeARMString(v)))
+		}
+	case []any:
+		if len(v) == 0 {
+			builder.WriteString("[]")
+			return nil
+		}
+		builder.WriteString("[\n")
+		for _, item := range v {
+			builder.WriteString(indent + indentUnit)
+			if err := writeValue(builder, item, indent+indentUnit); err != nil {
+				return err
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString(indent + "]")
+	case map[string]any:
+		if len(v) == 0 {
+			builder.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		builder.WriteString("{\n")
+		for _, key := range keys {
+			builder.WriteString(indent + indentUnit + formatKey(key) + ": ")
+			if err := writeValue(builder, v[key], indent+indentUnit); err != nil {
+				return err
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString(indent + "}")
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+	return nil
+}
+
+// normalize converts a value to the types produced by decoding JSON into an 'any'
+// (nil, bool, float64, string, []any, map[string]any), e.g. a []string or an int.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return v
+	case []any:
+		items := make([]any, len(v))
+		for i := range v {
+			items[i] = normalize(v[i])
+		}
+		return items
+	case map[string]any:
+		properties := make(map[string]any, len(v))
+		for key, property := range v {
+			properties[key] = normalize(property)
+		}
+		return properties
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return v
+		}
+		return decoded
+	}
+}
+
+// formatNumber formats a number. Bicep has no literal for non-integral numbers,
+// so they are written with the json() function (e.g. json('0.5')).
+func formatNumber(n float64) string {
+	if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return fmt.Sprintf("json('%s')", strconv.FormatFloat(n, 'f', -1, 64))
+}
+
+// formatKey formats an object key, quoting it unless it is a valid identifier.
+func formatKey(key string) string {
+	if identifierRegex.MatchString(key) {
+		return key
+	}
+	return FormatString(key)
+}
+
+// escapeString escapes the characters that have a special meaning in a Bicep string literal.
+func escapeString(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		`${`, `\${`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return replacer.Replace(s)
+}
+
+// armExpression reports whether a string of an ARM template is an expression ("[...]")
+// and returns the expression without the enclosing brackets.
+// Strings starting with "[[" are escaped literals, not expressions.
+func armExpression(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' || strings.HasPrefix(s, "[[") {
+		return "", false
+	}
+	return s[1 : len(s)-1], true
+}
+
+// unescapeARMString removes the escaping of a literal string that starts with a bracket ("[[...]" is "[...]").
+func unescapeARMString(s string) string {
+	if strings.HasPrefix(s, "[[") {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package bicep
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		indent   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "null", value: nil, expected: "null"},
+		{name: "bool", value: true, expected: "true"},
+		{name: "integer", value: float64(42), expected: "42"},
+		{name: "negative_integer", value: float64(-3), expected: "-3"},
+		{name: "go_integer", value: 7, expected: "7"},
+		{name: "decimal", value: 0.5, expected: "json('0.5')"},
+		{name: "string", value: "westus", expected: "'westus'"},
+		{name: "escaped_string", value: "it's ${x}\\\nnext", expected: `'it\'s \${x}\\\nnext'`},
+		{name: "escaped_bracket", value: "[[not an expression]", expected: "'[not an expression]'"},
+		{name: "expression", value: "[resourceGroup().location]", expected: "resourceGroup().location"},
+		{
+			name:    "unsupported_expression",
+			value:   "[map(parameters('items'), lambda('item', lambdaVariables('item')))]",
+			wantErr: true,
+		},
+		{name: "empty_array", value: []any{}, expected: "[]"},
+		{name: "empty_object", value: map[string]any{}, expected: "{}"},
+		{name: "typed_array", value: []string{"a", "b"}, expected: "[\n  'a'\n  'b'\n]"},
+		{
+			name: "nested",
+			value: map[string]any{
+				"name":       "app",
+				"tags":       map[string]any{"cost-center": "42", "env": "[parameters('environment')]"},
+				"zones":      []any{float64(1), float64(2)},
+				"enabled":    false,
+				"properties": map[string]any{},
+			},
+			indent: "    ",
+			expected: "{\n" +
+				"      enabled: false\n" +
+				"      name: 'app'\n" +
+				"      properties: {}\n" +
+				"      tags: {\n" +
+				"        'cost-center': '42'\n" +
+				"        env: environment\n" +
+				"      }\n" +
+				"      zones: [\n" +
+				"        1\n" +
+				"        2\n" +
+				"      ]\n" +
+				"    }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatValue(tt.value, tt.indent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrUnsupportedExpression) {
+					t.Errorf("FormatValue() error = %v, expected ErrUnsupportedExpression", err)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("FormatValue() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/christosgalano/bicep-docs/internal/docsite"
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+)
+
+// writeDocsNav writes the navigation of the collected modules for a static site generator to the root directory:
+// the mkdocs-nav.yml fragment for MkDocs, or a _category_.json file in each module directory for Docusaurus.
+// With markdown.AzureDevOpsFlavor, a .order file is also written to each directory for the page order of the wiki.
+func (c *moduleCollector) writeDocsNav(root string, opts *Options) error {
+	modules := make([]docsite.Module, len(c.pages))
+	for i, page := range c.pages {
+		modules[i] = docsite.Module{Dir: page.Dir, File: "README.md", Title: page.Template.ModuleName()}
+	}
+
+	files := map[string]string{}
+	switch opts.DocsSite {
+	case docsite.MkDocs:
+		files = map[string]string{docsite.MkDocsNavFile: docsite.MkDocsNav(modules)}
+	case docsite.Docusaurus:
+		categories, err := docsite.DocusaurusCategories(modules)
+		if err != nil {
+			return fmt.Errorf("failed to generate the Docusaurus categories: %w", err)
+		}
+		for name, content := range categories {
+			files[name] = content
+		}
+	}
+	if opts.Flavor == markdown.AzureDevOpsFlavor {
+		for name, content := range docsite.AzureDevOpsOrder(modules) {
+			files[name] = content
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeGeneratedFile(filepath.Join(root, filepath.FromSlash(name)), files[name], opts.Verbose); err != nil {
+			return err
+		}
+	}
+	return nil
+}
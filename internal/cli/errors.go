@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Phase represents the stage of the documentation pipeline in which a Bicep file failed.
+type Phase string
+
+const (
+	BuildPhase    Phase = "build"    // BuildPhase is the compilation of the Bicep file into an ARM template
+	ParsePhase    Phase = "parse"    // ParsePhase is the parsing of the Bicep and ARM templates
+	RenderPhase   Phase = "render"   // RenderPhase is the creation of the documentation file (and of the example parameters file)
+	ValidatePhase Phase = "validate" // ValidatePhase is the compilation of the example parameters file
+)
+
+// phases lists the pipeline phases in the order they are executed.
+var phases = []Phase{BuildPhase, ParsePhase, RenderPhase, ValidatePhase}
+
+func (p Phase) String() string {
+	return string(p)
+}
+
+// FileError is the failure of a single Bicep file.
+// It records the phase in which the failure occurred, the Bicep file and the underlying error
+// (e.g. the compiler diagnostic).
+type FileError struct {
+	Phase Phase
+	File  string
+	Err   error
+}
+
+// Error returns the string representation of the FileError.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("error processing %s: %s failed: %v", e.File, e.Phase, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateError aggregates the failures of a directory run.
+// Total is the number of Bicep files that were processed, whether they failed or not
+// (in fail-fast mode, the files skipped after the first failure are not counted).
+type GenerateError struct {
+	Total  int
+	Errors []*FileError
+}
+
+// Error returns a summary of all failures grouped by phase.
+// Within a phase the failures are ordered by file name.
+func (e *GenerateError) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d of %d Bicep files failed", len(e.Errors), e.Total)
+
+	for _, phase := range phases {
+		var failures []*FileError
+		for _, fileErr := range e.Errors {
+			if fileErr.Phase == phase {
+				failures = append(failures, fileErr)
+			}
+		}
+		if len(failures) == 0 {
+			continue
+		}
+		sort.Slice(failures, func(i, j int) bool {
+			return failures[i].File < failures[j].File
+		})
+
+		fmt.Fprintf(&builder, "\n\n%s (%d):", phase, len(failures))
+		for _, failure := range failures {
+			// Indent continuation lines so multi-line diagnostics stay grouped under their file.
+			message := strings.ReplaceAll(strings.TrimSpace(failure.Err.Error()), "\n", "\n    ")
+			fmt.Fprintf(&builder, "\n  %s: %s", failure.File, message)
+		}
+	}
+
+	return builder.String()
+}
+
+// Unwrap returns the per-file errors, so that errors.Is and errors.As inspect each of them.
+func (e *GenerateError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fileErr := range e.Errors {
+		errs[i] = fileErr
+	}
+	return errs
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *GenerateError
+		expected string
+	}{
+		{
+			name: "grouped_by_phase",
+			err: &GenerateError{
+				Total: 4,
+				Errors: []*FileError{
+					{Phase: RenderPhase, File: "c/main.bicep", Err: errors.New("failed to create file")},
+					{Phase: BuildPhase, File: "b/main.bicep", Err: errors.New("Error BCP018: Expected the \"=\" character.")},
+					{Phase: BuildPhase, File: "a/main.bicep", Err: errors.New("first line\nsecond line")},
+				},
+			},
+			expected: "3 of 4 Bicep files failed\n\n" +
+				"build (2):\n" +
+				"  a/main.bicep: first line\n" +
+				"    second line\n" +
+				"  b/main.bicep: Error BCP018: Expected the \"=\" character.\n\n" +
+				"render (1):\n" +
+				"  c/main.bicep: failed to create file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("GenerateError.Error() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateError_Unwrap(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	err := error(&GenerateError{
+		Total:  2,
+		Errors: []*FileError{{Phase: ParsePhase, File: "main.bicep", Err: sentinel}},
+	})
+
+	if !errors.Is(err, sentinel) {
+		t.Errorf("errors.Is() = false, expected the wrapped per-file error to be found")
+	}
+
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) || fileErr.Phase != ParsePhase {
+		t.Errorf("errors.As() = %v, expected a *FileError in the parse phase", fileErr)
+	}
+}
+
+func TestFileError_Error(t *testing.T) {
+	err := &FileError{Phase: BuildPhase, File: "main.bicep", Err: errors.New("compilation failed")}
+	expected := "error processing main.bicep: build failed: compilation failed"
+	if got := err.Error(); got != expected {
+		t.Errorf("FileError.Error() = %q, expected %q", got, expected)
+	}
+}
@@ -1,33 +1,166 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/christosgalano/bicep-docs/internal/bicep"
+	"github.com/christosgalano/bicep-docs/internal/docsite"
 	"github.com/christosgalano/bicep-docs/internal/markdown"
+	"github.com/christosgalano/bicep-docs/internal/schema"
 	"github.com/christosgalano/bicep-docs/internal/template"
 	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
+// Options contains the settings that control a documentation generation run.
+//
+// Verbose controls whether additional information is printed during the generation process.
+// Sections contains the sections that should be included in the documentation, in order.
+// Decorators contains the groups of decorators shown in the columns of the tables.
+// CompactConstraints controls whether the length and value constraints are merged into a single "Constraints" column.
+// Sort is the order of the declarations in the documentation: types.AlphaSort (the default), types.SourceSort,
+// or types.RequiredFirstSort.
+// GroupByCategory controls whether the parameters are grouped in a sub-table per category,
+// the value of their custom metadata entry "category" (e.g. @metadata({ category: 'Networking' })).
+// KeepGoing controls whether, in directory mode, the remaining Bicep files are still processed
+// after one of them fails.
+// BuildTimeout limits the compilation of each Bicep file; zero means no limit.
+// Compiler compiles the Bicep files; nil means a template.ExecCompiler (one process per file).
+// SkipVersionCheck disables the check of the compiler version against the minimum supported versions.
+// Footer controls whether a footer recording the compiler version is appended to the documentation.
+// ParamsFile controls whether an example parameters file (e.g. main.example.bicepparam) is created next to each Bicep file.
+// Schema controls whether a JSON Schema of the parameters (e.g. main.schema.json) is created next to each Bicep file.
+// ValidateParams controls whether the example parameters file is validated by compiling it;
+// it requires a compiler that implements template.ParamsValidator.
+// Registry is the base of the references of the modules published to a registry (e.g. "br/modules:" or
+// "br:myacr.azurecr.io/bicep/"); if set, the usage example references each module by its registry path and version.
+// ModuleVersion is the version source of the registry references: MetadataVersionSource (the default),
+// GitTagVersionSource, or the version itself.
+// MetadataBadges and MetadataHeader contain the keys of the custom template metadata entries
+// shown as badges and as fields below the title, respectively.
+// MetadataColumns contains the keys of the custom metadata entries of the parameters, outputs, and user-defined data types
+// shown as columns of their tables.
+// VariableValues controls whether the values of the variables are included in the variables table.
+// Format is the output format: MarkdownFormat (the default), HTMLFormat, AsciiDocFormat, or RSTFormat.
+// DocsSite is the static site generator the Markdown files are published with: docsite.MkDocs or docsite.Docusaurus.
+// If set, a YAML front matter is prepended to each Markdown file and, in directory mode, the navigation
+// of the modules is written to the input directory.
+// Flavor is the Markdown dialect of the renderer the Markdown files are published with (markdown.GitHubFlavor by default).
+// With markdown.AzureDevOpsFlavor, in directory mode, a .order file is written to each directory of the modules
+// for the page order of the wiki.
+type Options struct {
+	Verbose            bool
+	Sections           []types.Section
+	Decorators         []types.Decorator
+	CompactConstraints bool
+	Sort               types.SortOrder
+	GroupByCategory    bool
+	KeepGoing          bool
+	BuildTimeout       time.Duration
+	Compiler           template.Compiler
+	SkipVersionCheck   bool
+	Footer             bool
+	ParamsFile         bool
+	Schema             bool
+	ValidateParams     bool
+	Registry           string
+	ModuleVersion      string
+	MetadataBadges     []string
+	MetadataHeader     []string
+	MetadataColumns    []string
+	VariableValues     bool
+	Format             string
+	DocsSite           string
+	Flavor             markdown.Flavor
+
+	// root is the directory the registry paths and local paths of the modules are relative to:
+	// the input directory, or the directory of the input Bicep file.
+	root string
+
+	// compilerVersion is the version of the compiler, detected once per run by GenerateDocs.
+	compilerVersion template.Version
+
+	// modules collects the processed modules, for the static HTML site or the navigation of the docs site.
+	modules *moduleCollector
+
+	// concurrency is the maximum number of Bicep files processed at once in directory mode;
+	// zero means runtime.GOMAXPROCS(0) * 10.
+	concurrency int
+}
+
+// renderer returns the renderer of the documentation files of the format; nil means the Markdown of the flavor.
+func (opts *Options) renderer() markdown.Renderer {
+	switch opts.Format {
+	case AsciiDocFormat:
+		return markdown.AsciiDoc{}
+	case RSTFormat:
+		return markdown.ReStructuredText{}
+	default:
+		return nil
+	}
+}
+
+// documentationFile returns the name of the documentation file of a module in directory mode, e.g. README.md.
+func (opts *Options) documentationFile() string {
+	if r := opts.renderer(); r != nil {
+		return "README" + r.FileExtension()
+	}
+	return "README.md"
+}
+
+// documentName returns the name of a documentation file relative to the root, without its extension
+// (e.g. "network/vnet/README"), which is how Sphinx names the documents.
+func (opts *Options) documentName(file string) string {
+	root := opts.root
+	if root == "" {
+		root = filepath.Dir(file)
+	}
+	name, err := filepath.Rel(root, file)
+	if err != nil {
+		name = filepath.Base(file)
+	}
+	return strings.TrimSuffix(filepath.ToSlash(name), filepath.Ext(name))
+}
+
+// compiler returns the compiler of the options, defaulting to a template.ExecCompiler.
+func (opts *Options) compiler() template.Compiler {
+	if opts.Compiler == nil {
+		return &template.ExecCompiler{}
+	}
+	return opts.Compiler
+}
+
 // GenerateDocs generates documentation based on the input file or directory.
 //
 // If the input is a directory, it generates documentation for all 'main.bicep' files in the directory.
 // If the input is a Bicep file, it generates documentation for that file only.
 //
-// The output is used only when the input is a Bicep file; in other cases it is always set to 'README.md'.
+// The output is used only when the input is a Bicep file; in other cases it is always set to 'README.md'
+// (or 'README.adoc' and 'README.rst' with AsciiDocFormat and RSTFormat).
+// With HTMLFormat, the output is instead the directory of a static HTML site documenting every Bicep file,
+// which is written once all of them are processed (with opts.KeepGoing, even if some of them failed).
+// Likewise, with opts.DocsSite or markdown.AzureDevOpsFlavor, the navigation of the modules is written to the input directory.
 //
-// The sections slice contains the sections that should be included in the documentation.
+// A failure is reported as a *FileError for a single Bicep file, and as a *GenerateError
+// aggregating every failed file for a directory.
 //
-// If verbose is true, additional information will be printed during the generation process.
+// The compiler version is detected once, before any build, and an error is returned
+// if it is older than the minimum supported version (unless opts.SkipVersionCheck is set).
 //
-// If showAllDecorators is true, all decorator columns will be included in the output tables.
-func GenerateDocs(input, output string, verbose bool, sections []types.Section, showAllDecorators bool) error {
+// Canceling ctx (e.g. on Ctrl-C) kills every in-flight build and stops the directory walk.
+func GenerateDocs(ctx context.Context, input, output string, opts Options) error {
 	f, err := os.Stat(input)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -36,41 +169,170 @@ func GenerateDocs(input, output string, verbose bool, sections []types.Section,
 		return err
 	}
 
+	if opts.Registry != "" {
+		if opts.Registry, err = parseRegistry(opts.Registry); err != nil {
+			return err
+		}
+	}
+
+	switch opts.Format {
+	case "", MarkdownFormat, AsciiDocFormat, RSTFormat:
+	case HTMLFormat:
+		opts.modules = &moduleCollector{}
+	default:
+		return fmt.Errorf("invalid format %q: must be one of %q, %q, %q, or %q", opts.Format, MarkdownFormat, HTMLFormat, AsciiDocFormat, RSTFormat)
+	}
+	markdownFormat := opts.Format == "" || opts.Format == MarkdownFormat
+	switch opts.DocsSite {
+	case "":
+	case docsite.MkDocs, docsite.Docusaurus:
+		if !markdownFormat {
+			return fmt.Errorf("a docs site (%s) cannot be used with the %s format", opts.DocsSite, opts.Format)
+		}
+		if f.IsDir() {
+			opts.modules = &moduleCollector{}
+		}
+	default:
+		return fmt.Errorf("invalid docs site %q: must be %q or %q", opts.DocsSite, docsite.MkDocs, docsite.Docusaurus)
+	}
+
+	if opts.CompactConstraints && !slices.Contains(opts.Decorators, types.LengthDecorator) && !slices.Contains(opts.Decorators, types.ValueDecorator) {
+		return errors.New("compact constraints require the length or value decorators")
+	}
+
+	if opts.Sort, err = types.ParseSortOrderFromString(string(opts.Sort)); err != nil {
+		return err
+	}
+
+	if opts.Flavor, err = markdown.ParseFlavor(string(opts.Flavor)); err != nil {
+		return err
+	}
+	if opts.Flavor != markdown.GitHubFlavor && !markdownFormat {
+		return fmt.Errorf("a Markdown flavor (%s) cannot be used with the %s format", opts.Flavor, opts.Format)
+	}
+	if opts.Flavor == markdown.AzureDevOpsFlavor && f.IsDir() {
+		opts.modules = &moduleCollector{}
+	}
+
+	opts.compilerVersion, err = opts.compiler().Version(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect the compiler version: %w", err)
+	}
+	if !opts.SkipVersionCheck {
+		if err := opts.compilerVersion.Check(); err != nil {
+			return err
+		}
+	}
+	if opts.Verbose && opts.compilerVersion.Bicep != "" {
+		fmt.Printf("Using %s\n", opts.compilerVersion)
+	}
+
 	if f.IsDir() {
-		return generateDocsFromDirectory(input, verbose, sections, showAllDecorators)
+		opts.root = input
+		err = generateDocsFromDirectory(ctx, input, opts)
+	} else {
+		opts.root = filepath.Dir(input)
+		err = generateDocsFromBicepFile(ctx, input, output, opts)
 	}
-	return generateDocsFromBicepFile(input, output, verbose, sections, showAllDecorators)
+
+	// Write the site or the navigation of the processed modules
+	if opts.modules != nil {
+		var generateErr *GenerateError
+		if err != nil && (!opts.KeepGoing || !errors.As(err, &generateErr) || len(opts.modules.pages) == 0) {
+			return err
+		}
+		var writeErr error
+		if opts.Format == HTMLFormat {
+			writeErr = opts.modules.writeSite(output, &opts)
+		} else {
+			writeErr = opts.modules.writeDocsNav(input, &opts)
+		}
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	return err
 }
 
 // generateDocsFromDirectory processes the directory and its subdirectories recursively.
 //
-// For each 'main.bicep' file, it creates/updates a 'README.md' file in the same directory.
+// For each 'main.bicep' file, it creates/updates a 'README.md' file in the same directory
+// (or the documentation file of the format, see Options.documentationFile).
+//
+// Every failure is collected. Unless opts.KeepGoing is set, no new files are started after
+// the first failure (the files waiting for a worker are skipped), but the files already in progress
+// are allowed to finish.
+// If ctx is canceled, the in-flight builds are killed and the cancellation is returned
+// instead of the collected failures.
 //
 //nolint:mnd // Sensible default.
-func generateDocsFromDirectory(dirPath string, verbose bool, sections []types.Section, showAllDecorators bool) error {
-	g := new(errgroup.Group)
-	g.SetLimit(runtime.GOMAXPROCS(0) * 10)
+func generateDocsFromDirectory(ctx context.Context, dirPath string, opts Options) error {
+	g, groupCtx := errgroup.WithContext(ctx)
+	limit := opts.concurrency
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0) * 10
+	}
+	g.SetLimit(limit)
+
+	var mu sync.Mutex
+	result := &GenerateError{}
 
 	// Traverse the directory and process each main.bicep file
-	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+	walkErr := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		// Stop scheduling new files once a failure has been recorded (fail-fast mode) or ctx is canceled
+		if groupCtx.Err() != nil {
+			return filepath.SkipAll
+		}
 		if !d.IsDir() && d.Name() == "main.bicep" {
 			// Create a README.md file in the same directory as the main.bicep file
-			markdownFile := filepath.Join(filepath.Dir(path), "README.md")
+			markdownFile := filepath.Join(filepath.Dir(path), opts.documentationFile())
 			g.Go(func() error {
-				return generateDocsFromBicepFile(path, markdownFile, verbose, sections, showAllDecorators)
+				// Skip the files scheduled before the first failure but not started yet
+				if groupCtx.Err() != nil {
+					return nil
+				}
+				mu.Lock()
+				result.Total++
+				mu.Unlock()
+
+				err := generateDocsFromBicepFile(ctx, path, markdownFile, opts)
+				if err == nil {
+					return nil
+				}
+
+				var fileErr *FileError
+				if !errors.As(err, &fileErr) {
+					fileErr = &FileError{Phase: BuildPhase, File: path, Err: err}
+				}
+				mu.Lock()
+				result.Errors = append(result.Errors, fileErr)
+				mu.Unlock()
+
+				if opts.KeepGoing {
+					return nil
+				}
+				return fileErr
 			})
 		}
 		return nil
 	})
-	if err != nil {
-		return err
-	}
 
-	// Wait for all goroutines to finish and return the first non-nil error
-	return g.Wait()
+	// Wait for all goroutines to finish; their errors are collected in result
+	_ = g.Wait()
+
+	if walkErr != nil {
+		return walkErr
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("generation canceled: %w", ctx.Err())
+	}
+	if len(result.Errors) > 0 {
+		return result
+	}
+	return nil
 }
 
 // generateDocsFromBicepFile processes a Bicep template and creates/updates
@@ -83,28 +345,196 @@ func generateDocsFromDirectory(dirPath string, verbose bool, sections []types.Se
 // user defined functions, variables, outputs, and metadata.
 //
 // Finally it creates a corresponding Markdown file based on the gathered information
-// and the provided section, while also deleting the ARM template.
+// and the provided sections (unless the format is HTMLFormat), and collects the template for the files
+// documenting every module (see Options.modules).
+// The ARM template is kept in memory and never written to disk.
 //
 // If the Markdown file already exists, it will be overwritten.
-func generateDocsFromBicepFile(bicepFile, markdownFile string, verbose bool, sections []types.Section, showAllDecorators bool) error {
-	// Build Bicep template into ARM template
-	armFile, err := template.BuildBicepTemplate(bicepFile)
+//
+// A failure is returned as a *FileError recording the phase in which it occurred.
+func generateDocsFromBicepFile(ctx context.Context, bicepFile, markdownFile string, opts Options) error {
+	// Build Bicep template into ARM template, within the per-file timeout if any
+	buildCtx := ctx
+	if opts.BuildTimeout > 0 {
+		var cancel context.CancelFunc
+		buildCtx, cancel = context.WithTimeout(ctx, opts.BuildTimeout)
+		defer cancel()
+	}
+	armTemplate, diagnostics, err := opts.compiler().Build(buildCtx, bicepFile)
 	if err != nil {
-		return err
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("build timed out after %s", opts.BuildTimeout)
+		}
+		return &FileError{Phase: BuildPhase, File: bicepFile, Err: err}
+	}
+
+	// Print the compiler warnings and informational diagnostics (e.g. linter rule violations)
+	if opts.Verbose {
+		for i := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostics[i].String())
+		}
 	}
-	defer os.Remove(armFile)
 
 	// Parse both Bicep and ARM templates
 	var tmpl *types.Template
-	tmpl, err = template.ParseTemplates(bicepFile, armFile)
+	tmpl, err = template.ParseTemplate(bicepFile, bytes.NewReader(armTemplate))
 	if err != nil {
-		return fmt.Errorf("error processing %s: %w", bicepFile, err)
+		return &FileError{Phase: ParsePhase, File: bicepFile, Err: err}
+	}
+	tmpl.SortBy(opts.Sort)
+	tmpl.Diagnostics = diagnostics
+	tmpl.CompilerVersion = opts.compilerVersion.String()
+
+	root := opts.root
+	if root == "" {
+		root = filepath.Dir(bicepFile)
+	}
+
+	// Reference the module by its registry path and version
+	if opts.Registry != "" {
+		tmpl.ModuleReference, tmpl.ModulePath, err = moduleReference(ctx, bicepFile, root, tmpl, &opts)
+		if err != nil {
+			return &FileError{Phase: RenderPhase, File: bicepFile, Err: fmt.Errorf("failed to resolve the module reference: %w", err)}
+		}
+	}
+
+	// Collect the module, and create/update the Markdown file unless the format is HTMLFormat
+	if opts.modules != nil {
+		if err := opts.modules.add(bicepFile, root, tmpl); err != nil {
+			return &FileError{Phase: RenderPhase, File: bicepFile, Err: err}
+		}
+	}
+	if opts.Format != HTMLFormat {
+		if err := createMarkdownFile(bicepFile, markdownFile, tmpl, &opts); err != nil {
+			return err
+		}
 	}
 
-	// Create/Update Markdown file
-	if err := markdown.CreateFile(markdownFile, tmpl, verbose, sections, showAllDecorators); err != nil {
-		return fmt.Errorf("error processing %s: %w", bicepFile, err)
+	// Create/Update the JSON Schema of the parameters
+	if opts.Schema {
+		if err := generateSchemaFile(bicepFile, tmpl, opts.Verbose); err != nil {
+			return err
+		}
+	}
+
+	// Create/Update the example parameters file, and validate it
+	if opts.ParamsFile {
+		return generateParamsFile(ctx, bicepFile, tmpl, opts)
 	}
 
 	return nil
 }
+
+// createMarkdownFile creates/updates the Markdown file of a Bicep file.
+// A failure is returned as a *FileError of the render phase.
+func createMarkdownFile(bicepFile, markdownFile string, tmpl *types.Template, opts *Options) error {
+	markdownOpts := markdown.Options{
+		Verbose:            opts.Verbose,
+		Sections:           opts.Sections,
+		Decorators:         opts.Decorators,
+		CompactConstraints: opts.CompactConstraints,
+		GroupByCategory:    opts.GroupByCategory,
+		Footer:             opts.Footer,
+		MetadataBadges:     opts.MetadataBadges,
+		MetadataHeader:     opts.MetadataHeader,
+		MetadataColumns:    opts.MetadataColumns,
+		VariableValues:     opts.VariableValues,
+		FrontMatter:        opts.DocsSite != "",
+		Flavor:             opts.Flavor,
+		Renderer:           opts.renderer(),
+	}
+	if rst, ok := markdownOpts.Renderer.(markdown.ReStructuredText); ok {
+		rst.Document = opts.documentName(markdownFile)
+		markdownOpts.Renderer = rst
+	}
+	if err := markdown.CreateFile(markdownFile, tmpl, markdownOpts); err != nil {
+		return &FileError{Phase: RenderPhase, File: bicepFile, Err: err}
+	}
+	return nil
+}
+
+// generateParamsFile creates/updates the example parameters file of a Bicep file (e.g. main.example.bicepparam)
+// in the same directory, and validates it by compiling it if opts.ValidateParams is set.
+// The file is not rewritten if its content is unchanged.
+//
+// A failure is returned as a *FileError recording the phase in which it occurred.
+func generateParamsFile(ctx context.Context, bicepFile string, tmpl *types.Template, opts Options) error {
+	content, err := bicep.FormatParamsFile(tmpl, bicepFile)
+	if err != nil {
+		return &FileError{Phase: RenderPhase, File: bicepFile, Err: err}
+	}
+
+	paramsFile := filepath.Join(filepath.Dir(bicepFile), bicep.ParamsFileName(bicepFile))
+	if err := writeGeneratedFile(paramsFile, content, opts.Verbose); err != nil {
+		return &FileError{Phase: RenderPhase, File: bicepFile, Err: err}
+	}
+
+	if !opts.ValidateParams {
+		return nil
+	}
+	validator, ok := opts.compiler().(template.ParamsValidator)
+	if !ok {
+		return &FileError{Phase: ValidatePhase, File: bicepFile, Err: errors.New("the compiler cannot validate parameters files")}
+	}
+	validateCtx := ctx
+	if opts.BuildTimeout > 0 {
+		var cancel context.CancelFunc
+		validateCtx, cancel = context.WithTimeout(ctx, opts.BuildTimeout)
+		defer cancel()
+	}
+	diagnostics, err := validator.ValidateParams(validateCtx, paramsFile)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("validation timed out after %s", opts.BuildTimeout)
+		}
+		return &FileError{Phase: ValidatePhase, File: bicepFile, Err: err}
+	}
+	if opts.Verbose {
+		for i := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostics[i].String())
+		}
+	}
+	return nil
+}
+
+// generateSchemaFile creates/updates the JSON Schema of the parameters of a Bicep file (e.g. main.schema.json)
+// in the same directory. The file is not rewritten if its content is unchanged.
+//
+// A failure is returned as a *FileError of the render phase.
+func generateSchemaFile(bicepFile string, tmpl *types.Template, verbose bool) error {
+	content, err := schema.New(tmpl).Marshal()
+	if err != nil {
+		return &FileError{Phase: RenderPhase, File: bicepFile, Err: fmt.Errorf("failed to generate the schema: %w", err)}
+	}
+
+	schemaFile := filepath.Join(filepath.Dir(bicepFile), schema.FileName(bicepFile))
+	if err := writeGeneratedFile(schemaFile, string(content), verbose); err != nil {
+		return &FileError{Phase: RenderPhase, File: bicepFile, Err: err}
+	}
+	return nil
+}
+
+// writeGeneratedFile creates/updates a generated file (e.g. an example parameters file).
+// The file is not rewritten if its content is unchanged.
+func writeGeneratedFile(file, content string, verbose bool) error {
+	existing, err := os.ReadFile(file)
+	fileExists := err == nil
+	if fileExists && string(existing) == content {
+		if verbose {
+			fmt.Printf("No changes to %s\n", file)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil { //nolint:gosec // The generated files are not sensitive.
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if verbose {
+		if fileExists {
+			fmt.Printf("Updated %s\n", file)
+		} else {
+			fmt.Printf("Created %s\n", file)
+		}
+	}
+	return nil
+}
@@ -1,57 +1,60 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/christosgalano/bicep-docs/internal/docsite"
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+	"github.com/christosgalano/bicep-docs/internal/schema"
+	"github.com/christosgalano/bicep-docs/internal/template"
 	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
 func TestGenerateDocs(t *testing.T) {
 	tests := []struct {
-		name              string
-		input             string
-		output            string
-		verbose           bool
-		sections          []types.Section
-		showAllDecorators bool
-		expected          string
+		name     string
+		input    string
+		output   string
+		verbose  bool
+		sections []types.Section
+		expected string
 	}{
 		{
-			name:              "directory_input",
-			input:             "./testdata",
-			output:            "",
-			verbose:           true,
-			sections:          []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
-			showAllDecorators: false,
-			expected:          "",
+			name:     "directory_input",
+			input:    "./testdata",
+			output:   "",
+			verbose:  true,
+			sections: []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
+			expected: "",
 		},
 		{
-			name:              "file_input",
-			input:             "./testdata/main.bicep",
-			output:            "./testdata/README.md",
-			verbose:           false,
-			sections:          []types.Section{types.ModulesSection, types.ParametersSection},
-			showAllDecorators: false,
-			expected:          "",
+			name:     "file_input",
+			input:    "./testdata/main.bicep",
+			output:   "./testdata/README.md",
+			verbose:  false,
+			sections: []types.Section{types.ModulesSection, types.ParametersSection},
+			expected: "",
 		},
 		{
-			name:              "non_existent_input",
-			input:             "./path/to/non-existent",
-			output:            "",
-			verbose:           true,
-			sections:          []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
-			showAllDecorators: false,
-			expected:          "no such file or directory \"./path/to/non-existent\"",
+			name:     "non_existent_input",
+			input:    "./path/to/non-existent",
+			output:   "",
+			verbose:  true,
+			sections: []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
+			expected: "no such file or directory \"./path/to/non-existent\"",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := GenerateDocs(tt.input, tt.output, tt.verbose, tt.sections, tt.showAllDecorators)
+			err := GenerateDocs(context.Background(), tt.input, tt.output, Options{Verbose: tt.verbose, Sections: tt.sections, Compiler: &template.FixtureCompiler{}})
 			if tt.expected != "" {
 				if err == nil {
 					t.Errorf("GenerateDocs() expected error but got none")
@@ -65,28 +68,90 @@ func TestGenerateDocs(t *testing.T) {
 	}
 }
 
+// versionedCompiler is a template.FixtureCompiler that reports a fixed compiler version.
+type versionedCompiler struct {
+	template.FixtureCompiler
+	version template.Version
+}
+
+func (c *versionedCompiler) Version(_ context.Context) (template.Version, error) {
+	return c.version, nil
+}
+
+func TestGenerateDocs_versionCheck(t *testing.T) {
+	tests := []struct {
+		name             string
+		version          template.Version
+		skipVersionCheck bool
+		expected         string
+	}{
+		{
+			name:    "supported_version",
+			version: template.Version{Bicep: "0.38.33"},
+		},
+		{
+			name:     "unsupported_version",
+			version:  template.Version{Bicep: "0.26.54"},
+			expected: "unsupported Bicep CLI version 0.26.54",
+		},
+		{
+			name:             "skip_version_check",
+			version:          template.Version{Bicep: "0.26.54"},
+			skipVersionCheck: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "README.md")
+			opts := Options{
+				Sections:         []types.Section{types.DescriptionSection},
+				Compiler:         &versionedCompiler{version: tt.version},
+				SkipVersionCheck: tt.skipVersionCheck,
+				Footer:           true,
+			}
+			err := GenerateDocs(context.Background(), "./testdata/main.bicep", output, opts)
+			if tt.expected != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expected) {
+					t.Errorf("GenerateDocs() error = %v, expected to contain = %s", err, tt.expected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateDocs() unexpected error = %v", err)
+			}
+
+			content, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if footer := "_Generated by bicep-docs with " + tt.version.String() + "._"; !strings.Contains(string(content), footer) {
+				t.Errorf("GenerateDocs() output does not contain the footer %q:\n%s", footer, content)
+			}
+		})
+	}
+}
+
 func Test_generateDocsFromDirectory(t *testing.T) {
 	tests := []struct {
-		name              string
-		dirPath           string
-		verbose           bool
-		sections          []types.Section
-		showAllDecorators bool
-		expected          string
+		name     string
+		dirPath  string
+		verbose  bool
+		sections []types.Section
+		expected string
 	}{
 		{
-			name:              "valid_directory",
-			dirPath:           "./testdata",
-			verbose:           true,
-			sections:          []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
-			showAllDecorators: false,
-			expected:          "",
+			name:     "valid_directory",
+			dirPath:  "./testdata",
+			verbose:  true,
+			sections: []types.Section{types.DescriptionSection, types.ParametersSection, types.VariablesSection},
+			expected: "",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := generateDocsFromDirectory(tt.dirPath, tt.verbose, tt.sections, tt.showAllDecorators)
+			err := generateDocsFromDirectory(context.Background(), tt.dirPath, Options{Verbose: tt.verbose, Sections: tt.sections, Compiler: &template.FixtureCompiler{}})
 			if tt.expected != "" {
 				if err == nil {
 					t.Errorf("generateDocsFromDirectory() expected error but got none")
@@ -100,30 +165,317 @@ func Test_generateDocsFromDirectory(t *testing.T) {
 	}
 }
 
+func Test_generateDocsFromDirectory_failures(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepGoing bool
+		total     int
+		errors    int
+	}{
+		{
+			name:      "fail_fast",
+			keepGoing: false,
+			total:     1,
+			errors:    1,
+		},
+		{
+			name:      "keep_going",
+			keepGoing: true,
+			total:     2,
+			errors:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirPath := t.TempDir()
+			for _, module := range []string{"first", "second"} {
+				if err := os.MkdirAll(filepath.Join(dirPath, module), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dirPath, module, "main.bicep"), []byte("param invalid"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// One file at a time, so that the second file is only started after the first one failed
+			opts := Options{KeepGoing: tt.keepGoing, Compiler: &template.FixtureCompiler{}, concurrency: 1}
+			err := generateDocsFromDirectory(context.Background(), dirPath, opts)
+
+			var generateErr *GenerateError
+			if !errors.As(err, &generateErr) {
+				t.Fatalf("generateDocsFromDirectory() error = %v, expected a *GenerateError", err)
+			}
+			if generateErr.Total != tt.total {
+				t.Errorf("GenerateError.Total = %d, expected %d", generateErr.Total, tt.total)
+			}
+			if n := len(generateErr.Errors); n != tt.errors {
+				t.Errorf("len(GenerateError.Errors) = %d, expected %d", n, tt.errors)
+			}
+			for _, fileErr := range generateErr.Errors {
+				if fileErr.Phase != BuildPhase {
+					t.Errorf("FileError.Phase = %s, expected %s", fileErr.Phase, BuildPhase)
+				}
+			}
+		})
+	}
+}
+
+func Test_generateDocsFromDirectory_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := generateDocsFromDirectory(ctx, "./testdata", Options{Compiler: &template.FixtureCompiler{}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("generateDocsFromDirectory() error = %v, expected context.Canceled", err)
+	}
+}
+
+func TestGenerateDocs_html(t *testing.T) {
+	bicepFile, err := os.ReadFile("testdata/main.bicep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	armTemplate, err := os.ReadFile("testdata/main.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		keepGoing     bool
+		wantErr       bool
+		expectedPages []string
+	}{
+		{
+			name:          "all_modules",
+			expectedPages: []string{"network/vnet/index.html", "storage/index.html"},
+		},
+		{
+			name:          "keep_going",
+			keepGoing:     true,
+			wantErr:       true,
+			expectedPages: []string{"network/vnet/index.html", "storage/index.html"},
+		},
+		{
+			name:    "fail_fast",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := t.TempDir()
+			for _, module := range []string{"network/vnet", "storage"} {
+				dir := filepath.Join(input, filepath.FromSlash(module))
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "main.bicep"), bicepFile, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "main.json"), armTemplate, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.wantErr {
+				// A module without a pre-built ARM template fails to build
+				if err := os.MkdirAll(filepath.Join(input, "broken"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(input, "broken", "main.bicep"), []byte("param invalid"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			output := filepath.Join(t.TempDir(), "site")
+			opts := Options{
+				Sections:  []types.Section{types.DescriptionSection, types.ParametersSection},
+				KeepGoing: tt.keepGoing,
+				Compiler:  &template.FixtureCompiler{},
+				Format:    HTMLFormat,
+			}
+			err := GenerateDocs(context.Background(), input, output, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateDocs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(tt.expectedPages) == 0 {
+				if _, err := os.Stat(output); !os.IsNotExist(err) {
+					t.Errorf("GenerateDocs() created the site %s after a failure", output)
+				}
+				return
+			}
+			for _, page := range append(tt.expectedPages, "index.html", "search-index.js") {
+				if _, err := os.Stat(filepath.Join(output, filepath.FromSlash(page))); err != nil {
+					t.Errorf("GenerateDocs() did not create %s: %v", page, err)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(input, "storage", "README.md")); !os.IsNotExist(err) {
+				t.Errorf("GenerateDocs() created a README.md in html format")
+			}
+		})
+	}
+}
+
+func TestGenerateDocs_docsSite(t *testing.T) {
+	bicepFile, err := os.ReadFile("testdata/main.bicep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	armTemplate, err := os.ReadFile("testdata/main.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		docsSite      string
+		flavor        markdown.Flavor
+		expectedFiles []string
+	}{
+		{name: "mkdocs", docsSite: docsite.MkDocs, expectedFiles: []string{"mkdocs-nav.yml"}},
+		{name: "docusaurus", docsSite: docsite.Docusaurus, expectedFiles: []string{"network/_category_.json", "network/vnet/_category_.json", "storage/_category_.json"}},
+		{name: "azure_devops", flavor: markdown.AzureDevOpsFlavor, expectedFiles: []string{".order", "network/.order", "network/vnet/.order", "storage/.order"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := t.TempDir()
+			for _, module := range []string{"network/vnet", "storage"} {
+				dir := filepath.Join(input, filepath.FromSlash(module))
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "main.bicep"), bicepFile, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "main.json"), armTemplate, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts := Options{Sections: []types.Section{types.DescriptionSection}, Compiler: &template.FixtureCompiler{}, DocsSite: tt.docsSite, Flavor: tt.flavor}
+			if err := GenerateDocs(context.Background(), input, "", opts); err != nil {
+				t.Fatalf("GenerateDocs() unexpected error = %v", err)
+			}
+
+			for _, file := range tt.expectedFiles {
+				if _, err := os.Stat(filepath.Join(input, filepath.FromSlash(file))); err != nil {
+					t.Errorf("GenerateDocs() did not create %s: %v", file, err)
+				}
+			}
+			readme, err := os.ReadFile(filepath.Join(input, "storage", "README.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.docsSite != "" && !strings.HasPrefix(string(readme), "---\ntitle: \"test\"\n") {
+				t.Errorf("GenerateDocs() README.md has no front matter:\n%s", readme)
+			}
+		})
+	}
+}
+
+func TestGenerateDocs_renderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		file     string
+		expected string
+		contains string
+	}{
+		{name: "asciidoc", format: AsciiDocFormat, file: "README.adoc", expected: "= ", contains: "[[_parameters]]"},
+		{name: "rst", format: RSTFormat, file: "README.rst", expected: "====", contains: ".. _readme-parameters:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := t.TempDir()
+			for _, file := range []string{"main.bicep", "main.json"} {
+				data, err := os.ReadFile(filepath.Join("testdata", file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(input, file), data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts := Options{Sections: []types.Section{types.DescriptionSection, types.ParametersSection}, Compiler: &template.FixtureCompiler{}, Format: tt.format}
+			if err := GenerateDocs(context.Background(), input, "", opts); err != nil {
+				t.Fatalf("GenerateDocs() unexpected error = %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(input, tt.file))
+			if err != nil {
+				t.Fatalf("GenerateDocs() did not create %s: %v", tt.file, err)
+			}
+			if !strings.HasPrefix(string(content), tt.expected) {
+				t.Errorf("GenerateDocs() %s does not start with %q:\n%s", tt.file, tt.expected, content)
+			}
+			if !strings.Contains(string(content), tt.contains) {
+				t.Errorf("GenerateDocs() %s does not contain %q:\n%s", tt.file, tt.contains, content)
+			}
+			if _, err := os.Stat(filepath.Join(input, "README.md")); !os.IsNotExist(err) {
+				t.Errorf("GenerateDocs() created README.md with the %s format", tt.format)
+			}
+		})
+	}
+}
+
+func TestGenerateDocs_invalidFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		docsSite string
+		flavor   markdown.Flavor
+		compact  bool
+		sort     types.SortOrder
+		expected string
+	}{
+		{name: "format", format: "pdf", expected: `invalid format "pdf"`},
+		{name: "docs_site", docsSite: "hugo", expected: `invalid docs site "hugo"`},
+		{name: "html_docs_site", format: HTMLFormat, docsSite: docsite.MkDocs, expected: "cannot be used with the html format"},
+		{name: "flavor", flavor: "bitbucket", expected: `invalid flavor "bitbucket"`},
+		{name: "html_flavor", format: HTMLFormat, flavor: markdown.GitLabFlavor, expected: "cannot be used with the html format"},
+		{name: "asciidoc_docs_site", format: AsciiDocFormat, docsSite: docsite.MkDocs, expected: "cannot be used with the asciidoc format"},
+		{name: "rst_flavor", format: RSTFormat, flavor: markdown.CommonMarkFlavor, expected: "cannot be used with the rst format"},
+		{name: "compact_constraints", compact: true, expected: "compact constraints require the length or value decorators"},
+		{name: "sort", sort: "random", expected: `invalid sort order: "random"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Compiler: &template.FixtureCompiler{}, Format: tt.format, DocsSite: tt.docsSite, Flavor: tt.flavor, CompactConstraints: tt.compact, Sort: tt.sort}
+			err := GenerateDocs(context.Background(), "./testdata", "", opts)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateDocs() error = %v, expected to contain = %s", err, tt.expected)
+			}
+		})
+	}
+}
+
 func Test_generateDocsFromBicepFile(t *testing.T) {
 	tests := []struct {
-		name              string
-		bicepFile         string
-		markdownFile      string
-		verbose           bool
-		sections          []types.Section
-		showAllDecorators bool
-		expected          string
+		name         string
+		bicepFile    string
+		markdownFile string
+		verbose      bool
+		sections     []types.Section
+		expected     string
 	}{
 		{
-			name:              "valid_file",
-			bicepFile:         "./testdata/main.bicep",
-			markdownFile:      "./testdata/README.md",
-			verbose:           true,
-			sections:          []types.Section{types.ModulesSection, types.ParametersSection},
-			showAllDecorators: false,
-			expected:          "",
+			name:         "valid_file",
+			bicepFile:    "./testdata/main.bicep",
+			markdownFile: "./testdata/README.md",
+			verbose:      true,
+			sections:     []types.Section{types.ModulesSection, types.ParametersSection},
+			expected:     "",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := generateDocsFromBicepFile(tt.bicepFile, tt.markdownFile, tt.verbose, tt.sections, tt.showAllDecorators)
+			err := generateDocsFromBicepFile(context.Background(), tt.bicepFile, tt.markdownFile, Options{Verbose: tt.verbose, Sections: tt.sections, Compiler: &template.FixtureCompiler{}})
 			if tt.expected != "" {
 				if err == nil {
 					t.Errorf("generateDocsFromBicepFile() expected error but got none")
@@ -137,6 +489,122 @@ func Test_generateDocsFromBicepFile(t *testing.T) {
 	}
 }
 
+// paramsValidatorCompiler is a template.FixtureCompiler that validates parameters files.
+type paramsValidatorCompiler struct {
+	template.FixtureCompiler
+	validated []string
+}
+
+func (c *paramsValidatorCompiler) ValidateParams(_ context.Context, paramsFile string) ([]types.Diagnostic, error) {
+	c.validated = append(c.validated, paramsFile)
+	return nil, nil
+}
+
+func Test_generateDocsFromBicepFile_paramsFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		compiler       template.Compiler
+		validateParams bool
+		expected       string
+	}{
+		{
+			name:     "generate",
+			compiler: &template.FixtureCompiler{},
+		},
+		{
+			name:           "validate",
+			compiler:       &paramsValidatorCompiler{},
+			validateParams: true,
+		},
+		{
+			name:           "validate_unsupported_compiler",
+			compiler:       &template.FixtureCompiler{},
+			validateParams: true,
+			expected:       "the compiler cannot validate parameters files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{"main.bicep", "main.json"} {
+				data, err := os.ReadFile(filepath.Join("testdata", name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			bicepFile := filepath.Join(dir, "main.bicep")
+			opts := Options{
+				Sections:       []types.Section{types.DescriptionSection},
+				Compiler:       tt.compiler,
+				ParamsFile:     true,
+				ValidateParams: tt.validateParams,
+			}
+			err := generateDocsFromBicepFile(context.Background(), bicepFile, filepath.Join(dir, "README.md"), opts)
+			if tt.expected != "" {
+				var fileErr *FileError
+				if !errors.As(err, &fileErr) || fileErr.Phase != ValidatePhase || !strings.Contains(err.Error(), tt.expected) {
+					t.Errorf("generateDocsFromBicepFile() error = %v, expected a validate error containing = %s", err, tt.expected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateDocsFromBicepFile() unexpected error = %v", err)
+			}
+
+			paramsFile := filepath.Join(dir, "main.example.bicepparam")
+			content, err := os.ReadFile(paramsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(content), "using './main.bicep'\n") {
+				t.Errorf("generateDocsFromBicepFile() parameters file does not reference main.bicep:\n%s", content)
+			}
+			if c, ok := tt.compiler.(*paramsValidatorCompiler); ok && (len(c.validated) != 1 || c.validated[0] != paramsFile) {
+				t.Errorf("ValidateParams() called with %v, expected [%s]", c.validated, paramsFile)
+			}
+		})
+	}
+}
+
+func Test_generateDocsFromBicepFile_schema(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.bicep", "main.json"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bicepFile := filepath.Join(dir, "main.bicep")
+	opts := Options{Sections: []types.Section{types.DescriptionSection}, Compiler: &template.FixtureCompiler{}, Schema: true}
+	if err := generateDocsFromBicepFile(context.Background(), bicepFile, filepath.Join(dir, "README.md"), opts); err != nil {
+		t.Fatalf("generateDocsFromBicepFile() unexpected error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("generateDocsFromBicepFile() schema is not valid JSON: %v", err)
+	}
+	if document["$schema"] != schema.Dialect {
+		t.Errorf("generateDocsFromBicepFile() schema dialect = %v, expected %s", document["$schema"], schema.Dialect)
+	}
+	if _, ok := document["properties"].(map[string]any); !ok {
+		t.Errorf("generateDocsFromBicepFile() schema has no parameters:\n%s", content)
+	}
+}
+
 // createTestDirectory creates a temporary directory with the specified number of main.bicep files.
 func createTestDirectory(numFiles int) (string, error) {
 	tempDir, err := os.MkdirTemp("", "bicep-docs-benchmark")
@@ -186,7 +654,7 @@ func BenchmarkGenerateDocs(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				err := GenerateDocs(tempDir, "", false, sections, false)
+				err := GenerateDocs(context.Background(), tempDir, "", Options{Sections: sections})
 				if err != nil {
 					b.Fatalf("GenerateDocs() failed: %v", err)
 				}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/christosgalano/bicep-docs/internal/types"
@@ -19,6 +20,30 @@ func convertStringsToSections(sections []string) ([]types.Section, error) {
 	return convertedSections, nil
 }
 
+// allDecorators is the name that selects every group of decorators.
+const allDecorators = "all"
+
+// convertStringsToDecorators converts a slice of strings to a slice of Decorator enums, without duplicates.
+// The name "all" selects every group of decorators.
+func convertStringsToDecorators(decorators []string) ([]types.Decorator, error) {
+	var convertedDecorators []types.Decorator
+	for _, decorator := range decorators {
+		if strings.EqualFold(strings.TrimSpace(decorator), allDecorators) {
+			return types.AllDecorators, nil
+		}
+	}
+	for _, decorator := range decorators {
+		d, err := types.ParseDecoratorFromString(strings.TrimSpace(decorator))
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(convertedDecorators, d) {
+			convertedDecorators = append(convertedDecorators, d)
+		}
+	}
+	return convertedDecorators, nil
+}
+
 // computeSectionDifference computes the difference between two sets of sections.
 // It takes two comma-separated strings, includeSections and excludeSections,
 // and returns a slice of sections that are included in includeSections but not
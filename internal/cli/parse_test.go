@@ -43,6 +43,47 @@ func Test_convertStringsToSections(t *testing.T) {
 	}
 }
 
+func Test_convertStringsToDecorators(t *testing.T) {
+	tests := []struct {
+		name           string
+		decorators     []string
+		expectedResult []types.Decorator
+		expectedError  error
+	}{
+		{
+			name:           "valid_decorators",
+			decorators:     []string{"allowed", "length", "allowed", "exportable"},
+			expectedResult: []types.Decorator{types.AllowedDecorator, types.LengthDecorator, types.ExportableDecorator},
+			expectedError:  nil,
+		},
+		{
+			name:           "all_decorators",
+			decorators:     []string{"sealed", "all"},
+			expectedResult: types.AllDecorators,
+			expectedError:  nil,
+		},
+		{
+			name:           "invalid_decorator",
+			decorators:     []string{"allowed", "invalid"},
+			expectedResult: nil,
+			expectedError:  errors.New("invalid decorator: \"invalid\""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertStringsToDecorators(tt.decorators)
+			if !reflect.DeepEqual(result, tt.expectedResult) {
+				t.Errorf("convertStringsToDecorators() result = %v, expected %v", result, tt.expectedResult)
+			}
+			if (err != nil && tt.expectedError != nil && err.Error() != tt.expectedError.Error()) ||
+				(err == nil && tt.expectedError != nil) || (err != nil && tt.expectedError == nil) {
+				t.Errorf("convertStringsToDecorators() error = %v, expected %v", err, tt.expectedError)
+			}
+		})
+	}
+}
+
 func Test_computeSectionDifference(t *testing.T) {
 	tests := []struct {
 		name            string
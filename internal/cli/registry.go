@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// Version sources of the modules published to a registry (see Options.ModuleVersion).
+// Any other value is used as the version itself.
+const (
+	MetadataVersionSource = "metadata" // MetadataVersionSource uses the version metadata item of each module (metadata version = '...')
+	GitTagVersionSource   = "git-tag"  // GitTagVersionSource uses the latest git tag reachable from the module's directory
+)
+
+// parseRegistry validates the base of the module references in a registry, e.g. "br/modules:"
+// (an alias of bicepconfig.json) or "br:myacr.azurecr.io/bicep/", and returns it with its trailing separator.
+func parseRegistry(registry string) (string, error) {
+	switch {
+	case strings.HasPrefix(registry, "br/"):
+		if !strings.HasSuffix(registry, ":") {
+			registry += ":"
+		}
+	case strings.HasPrefix(registry, "br:"):
+		if !strings.HasSuffix(registry, "/") {
+			registry += "/"
+		}
+	default:
+		return "", fmt.Errorf("invalid registry %q: must start with 'br:' (e.g. br:myacr.azurecr.io/bicep/) or 'br/' (e.g. br/modules:)", registry)
+	}
+	return registry, nil
+}
+
+// moduleReference returns the registry reference of a Bicep file (e.g. "br/modules:network/vnet:1.0.0")
+// and its local path (e.g. "./network/vnet/main.bicep"), both relative to the root directory.
+//
+// The module path is the lowercase path of the file's directory relative to the root,
+// or the name of the directory itself if it is the root.
+// The version is resolved according to opts.ModuleVersion.
+func moduleReference(ctx context.Context, bicepFile, root string, tmpl *types.Template, opts *Options) (reference, localPath string, err error) {
+	file, err := filepath.Abs(bicepFile)
+	if err != nil {
+		return "", "", err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", "", err
+	}
+	relativeFile, err := filepath.Rel(root, file)
+	if err != nil {
+		return "", "", err
+	}
+	localPath = "./" + filepath.ToSlash(relativeFile)
+
+	dir := filepath.Dir(file)
+	modulePath := filepath.Dir(relativeFile)
+	if modulePath == "." {
+		modulePath = filepath.Base(dir)
+	}
+
+	version, err := resolveModuleVersion(ctx, dir, tmpl, opts.ModuleVersion)
+	if err != nil {
+		return "", "", err
+	}
+
+	reference = opts.Registry + strings.ToLower(filepath.ToSlash(modulePath)) + ":" + version
+	return reference, localPath, nil
+}
+
+// resolveModuleVersion resolves the version of the module in a directory from a version source.
+func resolveModuleVersion(ctx context.Context, dir string, tmpl *types.Template, source string) (string, error) {
+	switch source {
+	case "", MetadataVersionSource:
+		if tmpl.Metadata == nil || tmpl.Metadata.Version == nil || *tmpl.Metadata.Version == "" {
+			return "", errors.New("the module has no version metadata item (metadata version = '...')")
+		}
+		return *tmpl.Metadata.Version, nil
+	case GitTagVersionSource:
+		return latestGitTag(ctx, dir)
+	default:
+		return source, nil
+	}
+}
+
+// latestGitTag returns the latest git tag reachable from the HEAD of the repository containing a directory.
+func latestGitTag(ctx context.Context, dir string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "describe", "--tags", "--abbrev=0")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("failed to find the latest git tag: %s", message)
+		}
+		return "", fmt.Errorf("failed to find the latest git tag: %w", err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_parseRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		expected string
+		wantErr  bool
+	}{
+		{name: "alias", registry: "br/modules:", expected: "br/modules:"},
+		{name: "alias_without_separator", registry: "br/modules", expected: "br/modules:"},
+		{name: "registry", registry: "br:myacr.azurecr.io/bicep/", expected: "br:myacr.azurecr.io/bicep/"},
+		{name: "registry_without_separator", registry: "br:myacr.azurecr.io/bicep", expected: "br:myacr.azurecr.io/bicep/"},
+		{name: "invalid", registry: "myacr.azurecr.io/bicep/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRegistry(tt.registry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseRegistry() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_moduleReference(t *testing.T) {
+	version := "1.2.0"
+	withVersion := &types.Template{Metadata: &types.Metadata{Version: &version}}
+
+	tests := []struct {
+		name              string
+		bicepFile         string
+		root              string
+		template          *types.Template
+		moduleVersion     string
+		expectedReference string
+		expectedPath      string
+		wantErr           string
+	}{
+		{
+			name:              "metadata_version",
+			bicepFile:         "modules/Network/VNet/main.bicep",
+			root:              "modules",
+			template:          withVersion,
+			moduleVersion:     MetadataVersionSource,
+			expectedReference: "br/modules:network/vnet:1.2.0",
+			expectedPath:      "./Network/VNet/main.bicep",
+		},
+		{
+			name:              "fixed_version",
+			bicepFile:         "modules/storage/main.bicep",
+			root:              "modules",
+			template:          &types.Template{},
+			moduleVersion:     "2.0.1",
+			expectedReference: "br/modules:storage:2.0.1",
+			expectedPath:      "./storage/main.bicep",
+		},
+		{
+			name:              "root_module",
+			bicepFile:         "modules/storage/main.bicep",
+			root:              "modules/storage",
+			template:          withVersion,
+			expectedReference: "br/modules:storage:1.2.0",
+			expectedPath:      "./main.bicep",
+		},
+		{
+			name:          "missing_metadata_version",
+			bicepFile:     "modules/storage/main.bicep",
+			root:          "modules",
+			template:      &types.Template{},
+			moduleVersion: MetadataVersionSource,
+			wantErr:       "no version metadata item",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{Registry: "br/modules:", ModuleVersion: tt.moduleVersion}
+			reference, path, err := moduleReference(context.Background(), tt.bicepFile, tt.root, tt.template, opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("moduleReference() error = %v, expected to contain = %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("moduleReference() unexpected error = %v", err)
+			}
+			if reference != tt.expectedReference || path != tt.expectedPath {
+				t.Errorf("moduleReference() = (%q, %q), expected (%q, %q)", reference, path, tt.expectedReference, tt.expectedPath)
+			}
+		})
+	}
+}
+
+func Test_moduleReference_gitTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "storage")
+	if err := os.MkdirAll(moduleDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	bicepFile := filepath.Join(moduleDir, "main.bicep")
+	if err := os.WriteFile(bicepFile, []byte("param name string\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+		{"tag", "1.0.0"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "update"},
+		{"tag", "1.1.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	opts := &Options{Registry: "br:myacr.azurecr.io/bicep/", ModuleVersion: GitTagVersionSource}
+	reference, _, err := moduleReference(context.Background(), bicepFile, root, &types.Template{}, opts)
+	if err != nil {
+		t.Fatalf("moduleReference() unexpected error = %v", err)
+	}
+	if expected := "br:myacr.azurecr.io/bicep/storage:1.1.0"; reference != expected {
+		t.Errorf("moduleReference() = %q, expected %q", reference, expected)
+	}
+}
@@ -4,32 +4,64 @@ Package cli provides a command-line interface (CLI) for the bicep-docs tool, uti
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+	"github.com/christosgalano/bicep-docs/internal/template"
 	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
 // CLI flags.
 var (
-	input             string
-	output            string
-	verbose           bool
-	includeSections   string
-	excludeSections   string
-	showAllDecorators bool
+	input              string
+	output             string
+	verbose            bool
+	includeSections    string
+	excludeSections    string
+	showAllDecorators  bool
+	decorators         []string
+	compactConstraints bool
+	sortOrder          string
+	groupByCategory    bool
+	keepGoing          bool
+	buildTimeout       time.Duration
+	buildBackend       string
+	compilerName       string
+	skipVersionCheck   bool
+	footer             bool
+	paramsFile         bool
+	schemaFile         bool
+	validateParams     bool
+	registry           string
+	moduleVersion      string
+	metadataBadges     []string
+	metadataHeader     []string
+	metadataColumns    []string
+	variableValues     bool
+	format             string
+	docsSite           string
+	flavor             string
 )
 
 // CLI variables.
 var (
-	sections []types.Section
+	sections           []types.Section
+	selectedDecorators []types.Decorator
+	sortBy             types.SortOrder
+	backend            template.Backend
 )
 
 // CLI constants.
 const (
 	defaultSections = "description,usage,modules,resources,parameters,uddts,udfs,variables,outputs"
+	defaultSiteDir  = "site"
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -43,12 +75,55 @@ It parses Bicep files or directories to produce Markdown documentation. For dire
 it processes all main.bicep files, creating README.md in each directory containing a main.bicep file.
 For single Bicep files, it generates a README.md in the same directory unless an output path is specified.
 Existing README.md files will be overwritten.
+With --format html, it generates a static HTML site of every module instead,
+and with --format asciidoc or --format rst, AsciiDoc (README.adoc) or reStructuredText (README.rst) files.
 
-Azure CLI or Bicep CLI need to be installed.
+Azure CLI or Bicep CLI need to be installed, unless --compiler none is used with pre-built ARM templates.
 `,
 	//revive:disable:unused-parameter
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := GenerateDocs(input, output, verbose, sections, showAllDecorators); err != nil {
+		compiler, err := template.NewCompiler(compilerName, backend)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		opts := Options{
+			Verbose:            verbose,
+			Sections:           sections,
+			Decorators:         selectedDecorators,
+			CompactConstraints: compactConstraints,
+			Sort:               sortBy,
+			GroupByCategory:    groupByCategory,
+			KeepGoing:          keepGoing,
+			BuildTimeout:       buildTimeout,
+			Compiler:           compiler,
+			SkipVersionCheck:   skipVersionCheck,
+			Footer:             footer,
+			ParamsFile:         paramsFile,
+			Schema:             schemaFile,
+			ValidateParams:     validateParams,
+			Registry:           registry,
+			ModuleVersion:      moduleVersion,
+			MetadataBadges:     metadataBadges,
+			MetadataHeader:     metadataHeader,
+			MetadataColumns:    metadataColumns,
+			VariableValues:     variableValues,
+			Format:             format,
+			DocsSite:           docsSite,
+			Flavor:             markdown.Flavor(flavor),
+		}
+		if !cmd.Flags().Changed("output") {
+			switch format {
+			case HTMLFormat:
+				output = defaultSiteDir
+			case AsciiDocFormat, RSTFormat:
+				output = opts.documentationFile()
+			}
+		}
+		err = GenerateDocs(cmd.Context(), input, output, opts)
+		compiler.Close()
+		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -56,8 +131,12 @@ Azure CLI or Bicep CLI need to be installed.
 }
 
 // Execute executes the root command.
+// The command's context is canceled on an interrupt (Ctrl-C) or termination signal,
+// which kills every in-flight Bicep build.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 // init initializes the root command.
@@ -81,7 +160,8 @@ func init() {
 		"output",
 		"o",
 		"README.md",
-		"output Markdown file; ignored if input is a directory",
+		"output Markdown file, ignored if input is a directory; with --format asciidoc or rst, README.adoc or README.rst unless set; "+
+			"with --format html, the output directory of the site ('site' unless set)",
 	)
 
 	// verbose - optional
@@ -111,15 +191,201 @@ func init() {
 		"E",
 		"",
 		"comma-separated list of sections to exclude from the default output; "+
-			"available sections: description, usage, modules, resources, parameters, uddts, udfs, variables, outputs",
+			"available sections: description, usage, modules, resources, providers, parameters, uddts, udfs, variables, outputs, diagnostics, toc",
+	)
+
+	// decorators - optional
+	rootCmd.Flags().StringSliceVar(
+		&decorators,
+		"decorators",
+		nil,
+		"comma-separated groups of decorators to show in the columns of the output tables: "+
+			"allowed, length, value, sealed, exportable, or all; columns that are empty in every row are omitted",
 	)
 
-	// show-all-decorators - optional
+	// compact-constraints - optional
+	rootCmd.Flags().BoolVar(
+		&compactConstraints,
+		"compact-constraints",
+		false,
+		"merge the length and value decorators into a single Constraints column (e.g. '3–24 chars', '1 ≤ x ≤ 10')",
+	)
+
+	// sort - optional
+	rootCmd.Flags().StringVar(
+		&sortOrder,
+		"sort",
+		types.AlphaSort.String(),
+		"order of the declarations in the output: 'alpha' (by name), 'source' (as declared in the Bicep file), "+
+			"or 'required-first' (the required parameters first, then by name)",
+	)
+
+	// group-by-category - optional
+	rootCmd.Flags().BoolVar(
+		&groupByCategory,
+		"group-by-category",
+		false,
+		"group the parameters in a sub-table per category, the value of their metadata entry 'category' (e.g. @metadata({ category: 'Networking' }))",
+	)
+
+	// show-all-decorators - optional, deprecated
 	rootCmd.Flags().BoolVar(
 		&showAllDecorators,
 		"show-all-decorators",
 		false,
-		"show all decorator columns (exportable, constraints) in the output tables",
+		"show all decorator columns in the output tables",
+	)
+	if err := rootCmd.Flags().MarkDeprecated("show-all-decorators", "use --decorators all instead"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// keep-going - optional
+	rootCmd.Flags().BoolVar(
+		&keepGoing,
+		"keep-going",
+		false,
+		"in directory mode, keep generating documentation for the remaining files when one fails",
+	)
+
+	// build-timeout - optional
+	rootCmd.Flags().DurationVar(
+		&buildTimeout,
+		"build-timeout",
+		0,
+		"maximum duration of the compilation of each Bicep file (e.g. 30s, 2m); 0 means no limit",
+	)
+
+	// build-backend - optional
+	rootCmd.Flags().StringVar(
+		&buildBackend,
+		"build-backend",
+		template.ExecBackend.String(),
+		"how the Bicep CLI is run: exec (one process per file) or jsonrpc (one long-running 'bicep jsonrpc' process for all files)",
+	)
+
+	// compiler - optional
+	rootCmd.Flags().StringVar(
+		&compilerName,
+		"compiler",
+		"",
+		"compiler of the Bicep files: bicep, az, the path to a Bicep CLI binary, "+
+			"or none to read the pre-built ARM template (name.json) next to each Bicep file; "+
+			"by default bicep is used if it exists, otherwise az",
+	)
+
+	// skip-version-check - optional
+	rootCmd.Flags().BoolVar(
+		&skipVersionCheck,
+		"skip-version-check",
+		false,
+		"do not fail when the compiler is older than the minimum supported version (Bicep CLI "+
+			template.MinBicepVersion+", Azure CLI "+template.MinAzureCLIVersion+")",
+	)
+
+	// footer - optional
+	rootCmd.Flags().BoolVar(
+		&footer,
+		"footer",
+		false,
+		"append a footer recording the compiler version to the generated documentation",
+	)
+
+	// params-file - optional
+	rootCmd.Flags().BoolVar(
+		&paramsFile,
+		"params-file",
+		false,
+		"create an example parameters file (e.g. main.example.bicepparam) next to each Bicep file",
+	)
+
+	// schema - optional
+	rootCmd.Flags().BoolVar(
+		&schemaFile,
+		"schema",
+		false,
+		"create a JSON Schema of the parameters (e.g. main.schema.json) next to each Bicep file",
+	)
+
+	// validate-params - optional
+	rootCmd.Flags().BoolVar(
+		&validateParams,
+		"validate-params",
+		false,
+		"validate the example parameters files by compiling them with 'bicep build-params'; requires --params-file",
+	)
+
+	// registry - optional
+	rootCmd.Flags().StringVar(
+		&registry,
+		"registry",
+		"",
+		"base of the registry references of the modules in the usage example (e.g. br/modules: or br:myacr.azurecr.io/bicep/)",
+	)
+
+	// module-version - optional
+	rootCmd.Flags().StringVar(
+		&moduleVersion,
+		"module-version",
+		MetadataVersionSource,
+		"version of the modules in the registry references: a version (e.g. 1.2.0), 'metadata' (the version metadata item of each module), or 'git-tag' (the latest git tag)",
+	)
+
+	// metadata-badges - optional
+	rootCmd.Flags().StringSliceVar(
+		&metadataBadges,
+		"metadata-badges",
+		nil,
+		"comma-separated keys of the template metadata entries to show as badges below the title (e.g. version,owner)",
+	)
+
+	// metadata-header - optional
+	rootCmd.Flags().StringSliceVar(
+		&metadataHeader,
+		"metadata-header",
+		nil,
+		"comma-separated keys of the template metadata entries to show as fields below the title (e.g. owner,docsUrl)",
+	)
+
+	// metadata-columns - optional
+	rootCmd.Flags().StringSliceVar(
+		&metadataColumns,
+		"metadata-columns",
+		nil,
+		"comma-separated keys of the @metadata entries of the parameters, outputs, and user-defined data types to show as columns of their tables (e.g. docsLink,owner)",
+	)
+
+	// format - optional
+	rootCmd.Flags().StringVar(
+		&format,
+		"format",
+		MarkdownFormat,
+		"output format: 'markdown' (a README.md file per module), 'html' (a static site of every module), "+
+			"'asciidoc' (a README.adoc file per module), or 'rst' (a README.rst file per module)",
+	)
+
+	// docs-site - optional
+	rootCmd.Flags().StringVar(
+		&docsSite,
+		"docs-site",
+		"",
+		"static site generator of the Markdown files: 'mkdocs' or 'docusaurus'; adds a YAML front matter to each file and, for a directory, writes the navigation of the modules",
+	)
+
+	// flavor - optional
+	rootCmd.Flags().StringVar(
+		&flavor,
+		"flavor",
+		string(markdown.GitHubFlavor),
+		"Markdown flavor of the renderer: 'github', 'azure-devops', 'gitlab', or 'commonmark'; for a directory, 'azure-devops' also writes the .order files of the wiki",
+	)
+
+	// variable-values - optional
+	rootCmd.Flags().BoolVar(
+		&variableValues,
+		"variable-values",
+		false,
+		"include the values of the variables in the variables table",
 	)
 
 	rootCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
@@ -136,6 +402,31 @@ func init() {
 			return err
 		}
 
+		if showAllDecorators && len(decorators) > 0 {
+			return fmt.Errorf("--show-all-decorators and --decorators cannot be provided simultaneously")
+		}
+		if showAllDecorators {
+			decorators = []string{allDecorators}
+		}
+		selectedDecorators, err = convertStringsToDecorators(decorators)
+		if err != nil {
+			return err
+		}
+
+		sortBy, err = types.ParseSortOrderFromString(sortOrder)
+		if err != nil {
+			return err
+		}
+
+		if validateParams && !paramsFile {
+			return fmt.Errorf("--validate-params requires --params-file")
+		}
+
+		backend, err = template.ParseBackendFromString(buildBackend)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	}
 }
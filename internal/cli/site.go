@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/christosgalano/bicep-docs/internal/site"
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// Output formats of the documentation (see Options.Format).
+const (
+	MarkdownFormat = "markdown" // MarkdownFormat writes a Markdown file (README.md) for each Bicep file
+	HTMLFormat     = "html"     // HTMLFormat writes a static HTML site of every Bicep file to the output directory
+	AsciiDocFormat = "asciidoc" // AsciiDocFormat writes an AsciiDoc file (README.adoc) for each Bicep file
+	RSTFormat      = "rst"      // RSTFormat writes a reStructuredText file (README.rst) for each Bicep file
+)
+
+// moduleCollector collects the modules while the Bicep files are processed, so that the files documenting
+// every module (the static HTML site, or the navigation of a docs site) are written once at the end.
+type moduleCollector struct {
+	mu    sync.Mutex
+	pages []site.Page
+}
+
+// add adds the template of a Bicep file, under the directory of the file relative to the root.
+func (c *moduleCollector) add(bicepFile, root string, tmpl *types.Template) error {
+	dir, err := filepath.Rel(root, filepath.Dir(bicepFile))
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pages = append(c.pages, site.Page{Dir: filepath.ToSlash(dir), Template: tmpl})
+	return nil
+}
+
+// writeSite writes the static HTML site of the collected modules to a directory.
+func (c *moduleCollector) writeSite(dir string, opts *Options) error {
+	siteOpts := site.Options{
+		Sections:           opts.Sections,
+		Decorators:         opts.Decorators,
+		CompactConstraints: opts.CompactConstraints,
+		MetadataColumns:    opts.MetadataColumns,
+		GroupByCategory:    opts.GroupByCategory,
+		VariableValues:     opts.VariableValues,
+	}
+	if err := site.Create(dir, c.pages, siteOpts); err != nil {
+		return fmt.Errorf("failed to create the site: %w", err)
+	}
+	if opts.Verbose {
+		fmt.Printf("Created site %s (%d modules)\n", dir, len(c.pages))
+	}
+	return nil
+}
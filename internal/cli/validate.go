@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/christosgalano/bicep-docs/internal/template"
+	"github.com/christosgalano/bicep-docs/internal/validate"
+)
+
+// Output formats of the validation result (see WriteValidation).
+const (
+	TextOutput = "text" // TextOutput writes an issue per line
+	JSONOutput = "json" // JSONOutput writes a JSON object with the issues
+)
+
+// Validate CLI flags.
+var (
+	validateInput        string
+	validateParamsPath   string
+	validateOutputFormat string
+	validateCompilerName string
+)
+
+// Validation is the result of the validation of a parameters file against the parameters of a module.
+type Validation struct {
+	File   string `json:"file"`
+	Module string `json:"module"`
+	Valid  bool   `json:"valid"`
+	*validate.Result
+}
+
+// validateCmd represents the validate command.
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate a parameters file against the parameters of a module.",
+	Long: `Validate a parameters file (an ARM parameters.json file or a .bicepparam file) against the parameters
+of a module, without deploying it. It reports the missing required parameters, the unknown parameters,
+the values of the wrong type, the values that are not allowed, and the length and value constraint violations.
+
+The module is the input Bicep file, or else the Bicep file referenced by the using statement of a .bicepparam file.
+Values that are expressions (e.g. readEnvironmentVariable() or Key Vault references) are not checked.
+The exit code is 1 if the parameters file has any issue.
+`,
+	Args: cobra.NoArgs,
+	//revive:disable:unused-parameter
+	Run: func(cmd *cobra.Command, args []string) {
+		compiler, err := template.NewCompiler(validateCompilerName, template.ExecBackend)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		validation, err := ValidateParamsFile(cmd.Context(), validateInput, validateParamsPath, compiler)
+		compiler.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := WriteValidation(os.Stdout, validation, validateOutputFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if !validation.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+// ValidateParamsFile validates a parameters file against the parameters of a module, see validate.Validate.
+// If bicepFile is empty, the module is the Bicep file referenced by the using statement of a .bicepparam file,
+// relative to the directory of the parameters file. The module is compiled with the compiler.
+func ValidateParamsFile(ctx context.Context, bicepFile, paramsFile string, compiler template.Compiler) (*Validation, error) {
+	params, err := validate.LoadParamsFile(paramsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no such file %q", paramsFile)
+		}
+		return nil, err
+	}
+	if bicepFile == "" {
+		if params.Using == "" {
+			return nil, errors.New("no module to validate against: set the input Bicep file, or reference it in the using statement of a .bicepparam file")
+		}
+		bicepFile = filepath.Join(filepath.Dir(paramsFile), filepath.FromSlash(params.Using))
+	}
+
+	armTemplate, _, err := compiler.Build(ctx, bicepFile)
+	if err != nil {
+		return nil, &FileError{Phase: BuildPhase, File: bicepFile, Err: err}
+	}
+	tmpl, err := template.ParseTemplate(bicepFile, bytes.NewReader(armTemplate))
+	if err != nil {
+		return nil, &FileError{Phase: ParsePhase, File: bicepFile, Err: err}
+	}
+
+	result := validate.Validate(tmpl, params.Values)
+	return &Validation{File: paramsFile, Module: bicepFile, Valid: result.Valid(), Result: result}, nil
+}
+
+// WriteValidation writes the result of a validation in an output format: TextOutput (the default) or JSONOutput.
+func WriteValidation(w io.Writer, validation *Validation, format string) error {
+	switch format {
+	case "", TextOutput:
+		for _, issue := range validation.Issues {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", validation.File, issue); err != nil {
+				return err
+			}
+		}
+		for _, name := range validation.Unchecked {
+			if _, err := fmt.Fprintf(w, "%s: %s: not checked, the value is only known at deployment time\n", validation.File, name); err != nil {
+				return err
+			}
+		}
+		summary := "valid"
+		if n := len(validation.Issues); n == 1 {
+			summary = "1 issue"
+		} else if n > 1 {
+			summary = fmt.Sprintf("%d issues", n)
+		}
+		_, err := fmt.Fprintf(w, "%s: %s\n", validation.File, summary)
+		return err
+	case JSONOutput:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(validation)
+	default:
+		return fmt.Errorf("invalid output format %q: must be %q or %q", format, TextOutput, JSONOutput)
+	}
+}
+
+// init initializes the validate command.
+func init() {
+	validateCmd.Flags().StringVarP(
+		&validateInput,
+		"input",
+		"i",
+		"",
+		"input Bicep file of the module; by default the Bicep file of the using statement of a .bicepparam file",
+	)
+
+	validateCmd.Flags().StringVarP(
+		&validateParamsPath,
+		"params",
+		"p",
+		"",
+		"parameters file to validate: an ARM parameters file (e.g. parameters.json) or a .bicepparam file",
+	)
+	if err := validateCmd.MarkFlagRequired("params"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	validateCmd.Flags().StringVar(
+		&validateOutputFormat,
+		"format",
+		TextOutput,
+		"output format: 'text' (an issue per line) or 'json'",
+	)
+
+	validateCmd.Flags().StringVar(
+		&validateCompilerName,
+		"compiler",
+		"",
+		"compiler of the Bicep file: bicep, az, the path to a Bicep CLI binary, "+
+			"or none to read the pre-built ARM template (name.json) next to the Bicep file; "+
+			"by default bicep is used if it exists, otherwise az",
+	)
+
+	validateCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if validateOutputFormat != TextOutput && validateOutputFormat != JSONOutput {
+			return fmt.Errorf("invalid output format %q: must be %q or %q", validateOutputFormat, TextOutput, JSONOutput)
+		}
+		return nil
+	}
+
+	rootCmd.AddCommand(validateCmd)
+}
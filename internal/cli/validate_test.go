@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/template"
+)
+
+func TestValidateParamsFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		paramsFile    string
+		content       string
+		bicepFile     string
+		expectedValid bool
+		expectedText  string
+		expectedErr   string
+	}{
+		{
+			name:          "bicepparam_using",
+			paramsFile:    "main.bicepparam",
+			content:       "using './main.bicep'\n\nparam test_parameter = 'value'\n",
+			expectedValid: true,
+			expectedText:  "main.bicepparam: valid\n",
+		},
+		{
+			name:          "arm_parameters",
+			paramsFile:    "parameters.json",
+			content:       `{"parameters": {"test_parameter": {"value": 1}, "location": {"value": "westeurope"}}}`,
+			bicepFile:     "main.bicep",
+			expectedValid: false,
+			expectedText: "parameters.json: test_parameter: expected a string, got an integer\n" +
+				"parameters.json: location: unknown parameter, not declared by the module\n" +
+				"parameters.json: 2 issues\n",
+		},
+		{
+			name:          "unchecked",
+			paramsFile:    "main.bicepparam",
+			content:       "using './main.bicep'\n\nparam test_parameter = readEnvironmentVariable('TEST')\n",
+			expectedValid: true,
+			expectedText: "main.bicepparam: test_parameter: not checked, the value is only known at deployment time\n" +
+				"main.bicepparam: valid\n",
+		},
+		{
+			name:        "no_module",
+			paramsFile:  "parameters.json",
+			content:     `{"parameters": {}}`,
+			expectedErr: "no module to validate against",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{"main.bicep", "main.json"} {
+				data, err := os.ReadFile(filepath.Join("testdata", name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			paramsFile := filepath.Join(dir, tt.paramsFile)
+			if err := os.WriteFile(paramsFile, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			bicepFile := ""
+			if tt.bicepFile != "" {
+				bicepFile = filepath.Join(dir, tt.bicepFile)
+			}
+
+			validation, err := ValidateParamsFile(context.Background(), bicepFile, paramsFile, &template.FixtureCompiler{})
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("ValidateParamsFile() error = %v, expected to contain %q", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateParamsFile() unexpected error = %v", err)
+			}
+			if validation.Valid != tt.expectedValid {
+				t.Errorf("ValidateParamsFile() valid = %v, expected %v", validation.Valid, tt.expectedValid)
+			}
+
+			var text bytes.Buffer
+			if err := WriteValidation(&text, validation, TextOutput); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.ReplaceAll(text.String(), paramsFile, tt.paramsFile); got != tt.expectedText {
+				t.Errorf("WriteValidation() = %q, expected %q", got, tt.expectedText)
+			}
+
+			var output bytes.Buffer
+			if err := WriteValidation(&output, validation, JSONOutput); err != nil {
+				t.Fatal(err)
+			}
+			var document struct {
+				Valid  bool  `json:"valid"`
+				Issues []any `json:"issues"`
+			}
+			if err := json.Unmarshal(output.Bytes(), &document); err != nil {
+				t.Fatalf("WriteValidation() invalid JSON: %v", err)
+			}
+			if document.Valid != tt.expectedValid || document.Issues == nil {
+				t.Errorf("WriteValidation() = %s", output.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Package docsite generates the navigation files of static site generators (MkDocs and Docusaurus)
+and of Azure DevOps wikis for the Markdown documentation of a directory of modules, mirroring the hierarchy
+of the module directories, so that the generated files can be published as a documentation site as they are.
+*/
+package docsite
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Static site generators.
+const (
+	MkDocs     = "mkdocs"     // MkDocs is MkDocs (e.g. with the Material theme)
+	Docusaurus = "docusaurus" // Docusaurus is Docusaurus
+)
+
+// Files of the navigation.
+const (
+	MkDocsNavFile          = "mkdocs-nav.yml"  // MkDocsNavFile is the nav fragment of mkdocs.yml, written to the root directory
+	DocusaurusCategoryFile = "_category_.json" // DocusaurusCategoryFile is the sidebar category of a directory
+	AzureDevOpsOrderFile   = ".order"          // AzureDevOpsOrderFile is the order of the pages of a directory of an Azure DevOps wiki
+)
+
+// Module is a documented module: the slash-separated directory of its documentation file relative to the root
+// directory (e.g. "network/vnet", or "." for the root), the name of the documentation file (e.g. "README.md"),
+// and the title of the module.
+type Module struct {
+	Dir   string
+	File  string
+	Title string
+}
+
+// node is a directory of the hierarchy of the modules, with its module if it has one.
+type node struct {
+	name     string
+	module   *Module
+	children []*node
+}
+
+// newTree returns the hierarchy of the module directories, sorted by name.
+func newTree(modules []Module) *node {
+	sorted := append([]Module(nil), modules...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Dir < sorted[j].Dir })
+
+	root := &node{}
+	for i := range sorted {
+		module := &sorted[i]
+		current := root
+		if dir := path.Clean(module.Dir); dir != "." {
+			for _, segment := range strings.Split(dir, "/") {
+				current = current.child(segment)
+			}
+		}
+		current.module = module
+	}
+	return root
+}
+
+// child returns the child node of a directory, adding it if needed.
+func (n *node) child(name string) *node {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+	child := &node{name: name}
+	n.children = append(n.children, child)
+	return child
+}
+
+// label returns the label of a directory in the navigation: the title of its module, or otherwise its name.
+func (n *node) label() string {
+	if n.module != nil && n.module.Title != "" {
+		return n.module.Title
+	}
+	return n.name
+}
+
+// MkDocsNav returns a nav fragment of mkdocs.yml listing the documentation files of the modules,
+// with the paths relative to the root directory (the docs_dir of MkDocs).
+//
+// Each directory is a section: a module directory without subdirectories is a page,
+// and a module directory with subdirectories is a section whose first page is the module.
+func MkDocsNav(modules []Module) string {
+	var builder strings.Builder
+	builder.WriteString("# Generated by bicep-docs: the navigation of the module documentation, to merge into mkdocs.yml.\n")
+	builder.WriteString("nav:\n")
+	tree := newTree(modules)
+	if tree.module != nil {
+		fmt.Fprintf(&builder, "  - %s: %s\n", yamlString(tree.label()), yamlString(tree.module.File))
+	}
+	tree.writeMkDocs(&builder, "  ", "")
+	return builder.String()
+}
+
+func (n *node) writeMkDocs(builder *strings.Builder, indent, dir string) {
+	for _, child := range n.children {
+		childDir := path.Join(dir, child.name)
+		if child.module != nil && len(child.children) == 0 {
+			fmt.Fprintf(builder, "%s- %s: %s\n", indent, yamlString(child.label()), yamlString(path.Join(childDir, child.module.File)))
+			continue
+		}
+		fmt.Fprintf(builder, "%s- %s:\n", indent, yamlString(child.name))
+		if child.module != nil {
+			fmt.Fprintf(builder, "%s    - %s: %s\n", indent, yamlString(child.label()), yamlString(path.Join(childDir, child.module.File)))
+		}
+		child.writeMkDocs(builder, indent+"    ", childDir)
+	}
+}
+
+// category is the content of a Docusaurus _category_.json file.
+type category struct {
+	Label    string `json:"label"`
+	Position int    `json:"position"`
+}
+
+// DocusaurusCategories returns the _category_.json file of every directory of the hierarchy of the modules,
+// by slash-separated directory relative to the root directory (e.g. "network/vnet/_category_.json").
+//
+// The label of a directory is the title of its module, or otherwise its name, and its position is its rank among
+// its sibling directories. The documentation file of a module (e.g. README.md) is the index of its category.
+func DocusaurusCategories(modules []Module) (map[string]string, error) {
+	files := map[string]string{}
+	if err := newTree(modules).writeDocusaurus(files, ""); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (n *node) writeDocusaurus(files map[string]string, dir string) error {
+	for i, child := range n.children {
+		childDir := path.Join(dir, child.name)
+		data, err := json.MarshalIndent(category{Label: child.label(), Position: i + 1}, "", "  ")
+		if err != nil {
+			return err
+		}
+		files[path.Join(childDir, DocusaurusCategoryFile)] = string(data) + "\n"
+		if err := child.writeDocusaurus(files, childDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AzureDevOpsOrder returns the .order file of every directory of the hierarchy of the modules, including the root
+// directory, by slash-separated directory relative to the root directory (e.g. "network/.order").
+//
+// An Azure DevOps wiki lists the pages and subdirectories of a directory in the order of its .order file,
+// by name and without the .md extension: the documentation file of the module of the directory comes first
+// (e.g. "README"), followed by the subdirectories sorted by name.
+func AzureDevOpsOrder(modules []Module) map[string]string {
+	files := map[string]string{}
+	newTree(modules).writeAzureDevOps(files, ".")
+	return files
+}
+
+func (n *node) writeAzureDevOps(files map[string]string, dir string) {
+	var builder strings.Builder
+	if n.module != nil {
+		builder.WriteString(strings.TrimSuffix(n.module.File, path.Ext(n.module.File)) + "\n")
+	}
+	for _, child := range n.children {
+		builder.WriteString(child.name + "\n")
+		child.writeAzureDevOps(files, path.Join(dir, child.name))
+	}
+	if builder.Len() > 0 {
+		files[path.Join(dir, AzureDevOpsOrderFile)] = builder.String()
+	}
+}
+
+// yamlString returns a string as a double-quoted YAML string.
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,61 @@
+package docsite
+
+import (
+	"reflect"
+	"testing"
+)
+
+var modules = []Module{
+	{Dir: "storage", File: "README.md", Title: "Storage Account"},
+	{Dir: "network/vnet", File: "README.md", Title: "Virtual Network"},
+	{Dir: "network", File: "README.md", Title: "Network"},
+	{Dir: "network/nsg/rules", File: "README.md", Title: "Security Rules"},
+	{Dir: ".", File: "README.md", Title: "Landing Zone"},
+}
+
+func TestMkDocsNav(t *testing.T) {
+	expected := `# Generated by bicep-docs: the navigation of the module documentation, to merge into mkdocs.yml.
+nav:
+  - "Landing Zone": "README.md"
+  - "network":
+      - "Network": "network/README.md"
+      - "nsg":
+          - "Security Rules": "network/nsg/rules/README.md"
+      - "Virtual Network": "network/vnet/README.md"
+  - "Storage Account": "storage/README.md"
+`
+	if got := MkDocsNav(modules); got != expected {
+		t.Errorf("MkDocsNav() = %s, expected %s", got, expected)
+	}
+}
+
+func TestDocusaurusCategories(t *testing.T) {
+	expected := map[string]string{
+		"network/_category_.json":           "{\n  \"label\": \"Network\",\n  \"position\": 1\n}\n",
+		"network/nsg/_category_.json":       "{\n  \"label\": \"nsg\",\n  \"position\": 1\n}\n",
+		"network/nsg/rules/_category_.json": "{\n  \"label\": \"Security Rules\",\n  \"position\": 1\n}\n",
+		"network/vnet/_category_.json":      "{\n  \"label\": \"Virtual Network\",\n  \"position\": 2\n}\n",
+		"storage/_category_.json":           "{\n  \"label\": \"Storage Account\",\n  \"position\": 2\n}\n",
+	}
+	got, err := DocusaurusCategories(modules)
+	if err != nil {
+		t.Fatalf("DocusaurusCategories() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("DocusaurusCategories() = %v, expected %v", got, expected)
+	}
+}
+
+func TestAzureDevOpsOrder(t *testing.T) {
+	expected := map[string]string{
+		".order":                   "README\nnetwork\nstorage\n",
+		"network/.order":           "README\nnsg\nvnet\n",
+		"network/nsg/.order":       "rules\n",
+		"network/nsg/rules/.order": "README\n",
+		"network/vnet/.order":      "README\n",
+		"storage/.order":           "README\n",
+	}
+	if got := AzureDevOpsOrder(modules); !reflect.DeepEqual(got, expected) {
+		t.Errorf("AzureDevOpsOrder() = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,184 @@
+package markdown
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// AsciiDoc is the Renderer of AsciiDoc documents, e.g. the pages of an Antora site.
+//
+// The headings have explicit anchors, and the tables use AsciiDoc cells (the "a" style),
+// so that the collapsible values of the variables can be nested within them.
+type AsciiDoc struct{}
+
+// asciiDocSpecialCharacters are the characters of a text that are subject to the inline formatting of AsciiDoc.
+const asciiDocSpecialCharacters = "*_`#^~+[]{}<>\\"
+
+// FileExtension returns the extension of AsciiDoc files.
+func (AsciiDoc) FileExtension() string {
+	return ".adoc"
+}
+
+// Slug returns the anchor of a heading as generated by Asciidoctor: lowercased, prefixed by an underscore,
+// with spaces, hyphens, and periods replaced by underscores, and other punctuation removed.
+func (AsciiDoc) Slug(title string) string {
+	var builder strings.Builder
+	builder.WriteRune('_')
+	for _, r := range strings.ToLower(strings.TrimSpace(title)) {
+		switch {
+		case r == ' ' || r == '-' || r == '.' || r == '_':
+			builder.WriteRune('_')
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		}
+	}
+	slug := builder.String()
+	for strings.Contains(slug, "__") {
+		slug = strings.ReplaceAll(slug, "__", "_")
+	}
+	return strings.TrimSuffix(slug, "_")
+}
+
+// Heading returns a section title with an explicit anchor; the level-1 heading is the document title.
+func (AsciiDoc) Heading(level HeaderType, title, anchor string) string {
+	if level == H1 {
+		return fmt.Sprintf("= %s\n\n", title)
+	}
+	return fmt.Sprintf("[[%s]]\n%s %s\n\n", anchor, strings.Repeat("=", int(level)), title)
+}
+
+// Table returns a table with a header row, whose cells are AsciiDoc content.
+func (AsciiDoc) Table(headers []string, rows [][]string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[%%header,cols=\"%d*a\"]\n|===\n", len(headers))
+	for i, header := range headers {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		fmt.Fprintf(&builder, "|%s", header)
+	}
+	builder.WriteString("\n")
+	for _, row := range rows {
+		builder.WriteString("\n")
+		for _, cell := range row {
+			fmt.Fprintf(&builder, "|%s\n", cell)
+		}
+	}
+	builder.WriteString("|===\n")
+	return builder.String()
+}
+
+// CodeBlock returns a source block.
+func (AsciiDoc) CodeBlock(language, code string) string {
+	return fmt.Sprintf("[source,%s]\n----\n%s----\n", language, code)
+}
+
+// ListItem returns an unordered list item, whose marker is repeated for every level of depth.
+func (AsciiDoc) ListItem(depth int, text string) string {
+	return fmt.Sprintf("%s %s\n", strings.Repeat("*", depth+1), text)
+}
+
+// Badges returns the badge images on a single line.
+func (AsciiDoc) Badges(badges []Badge) string {
+	images := make([]string, len(badges))
+	for i, badge := range badges {
+		label := strings.ReplaceAll(badge.Label, "\"", "")
+		if badge.Link != "" {
+			images[i] = fmt.Sprintf("image:%s[\"%s\",link=\"%s\"]", badge.Image, label, badge.Link)
+		} else {
+			images[i] = fmt.Sprintf("image:%s[\"%s\"]", badge.Image, label)
+		}
+	}
+	return strings.Join(images, " ") + "\n\n"
+}
+
+// Rule returns a thematic break.
+func (AsciiDoc) Rule() string {
+	return "'''"
+}
+
+// Paragraph returns the text as it is, e.g. a description with AsciiDoc formatting.
+func (AsciiDoc) Paragraph(text string) string {
+	return text + "\n"
+}
+
+// Text returns a text with its pipes escaped, so that they do not split a table cell, and its line breaks
+// replaced by hard line breaks. The lines that contain formatting characters, or that start with the marker
+// of a block (e.g. a list item), are passed through without substitutions other than the HTML escaping.
+func (AsciiDoc) Text(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case line == "" && len(lines) > 1:
+			line = "{empty}"
+		case strings.ContainsAny(line, asciiDocSpecialCharacters) || strings.IndexAny(line, "-.=/:'") == 0:
+			line = "pass:c[" + strings.ReplaceAll(line, "]", "\\]") + "]"
+		}
+		lines[i] = strings.ReplaceAll(line, "|", "\\|")
+	}
+	return strings.Join(lines, " +\n")
+}
+
+// Lines returns the lines separated by hard line breaks.
+func (AsciiDoc) Lines(lines []string) string {
+	return strings.Join(lines, " +\n")
+}
+
+// Code returns literal monospace text, with its pipes escaped. The passthrough of literal monospace (`+code+`)
+// can end early at a plus within the code (e.g. one followed by a backtick), so code with a plus or a backtick
+// is passed through by the pass macro instead, with its closing brackets escaped.
+func (AsciiDoc) Code(code string) string {
+	code = strings.ReplaceAll(code, "|", "\\|")
+	if strings.ContainsAny(code, "+`") {
+		return "`pass:c[" + strings.ReplaceAll(code, "]", "\\]") + "]`"
+	}
+	return "`+" + code + "+`"
+}
+
+// Expandable returns a collapsible source block titled by the summary.
+func (a AsciiDoc) Expandable(summary, code string) string {
+	return fmt.Sprintf(".%s\n[%%collapsible]\n====\n%s====", a.Code(summary),
+		a.CodeBlock("bicep", strings.ReplaceAll(strings.TrimRight(code, "\n"), "|", "\\|")+"\n"))
+}
+
+// Strong returns bold text.
+func (AsciiDoc) Strong(text string) string {
+	return "*" + text + "*"
+}
+
+// Emphasis returns italic text.
+func (AsciiDoc) Emphasis(text string) string {
+	return "_" + text + "_"
+}
+
+// Deleted returns text with the built-in line-through role.
+func (AsciiDoc) Deleted(text string) string {
+	return "[.line-through]#" + text + "#"
+}
+
+// Link returns a link macro, which links to URLs and relative paths alike.
+func (AsciiDoc) Link(text, url string) string {
+	return fmt.Sprintf("link:%s[%s]", url, strings.ReplaceAll(text, "]", "\\]"))
+}
+
+// DocumentLink returns a link macro to another document by its relative path, as an xref macro would need
+// the page ID of the document (e.g. its path relative to the pages of an Antora module);
+// a link to a Markdown document is redirected to the AsciiDoc document generated in its place.
+func (adoc AsciiDoc) DocumentLink(text, target string) string {
+	if path.Ext(target) == ".md" {
+		target = strings.TrimSuffix(target, ".md") + ".adoc"
+	}
+	return adoc.Link(text, target)
+}
+
+// Reference returns a cross-reference to the anchor of a heading.
+func (AsciiDoc) Reference(text, anchor string) string {
+	return fmt.Sprintf("<<%s,%s>>", anchor, text)
+}
+
+// Anchor returns the text itself; only the headings have explicit anchors.
+func (AsciiDoc) Anchor(text, _ string) string {
+	return text
+}
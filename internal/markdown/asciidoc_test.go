@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestAsciiDoc_Slug(t *testing.T) {
+	tests := []struct {
+		name     string
+		heading  string
+		expected string
+	}{
+		{name: "words", heading: "User Defined Data Types (UDDTs)", expected: "_user_defined_data_types_uddts"},
+		{name: "underscores", heading: "storage_Config", expected: "_storage_config"},
+		{name: "hyphens", heading: "a - b.c", expected: "_a_b_c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (AsciiDoc{}).Slug(tt.heading); got != tt.expected {
+				t.Errorf("Slug() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAsciiDoc_Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "plain", text: "The name of the account.", expected: "The name of the account."},
+		{name: "pipes", text: "a|b", expected: "a\\|b"},
+		{name: "formatting", text: "*not* [bold]|", expected: "pass:c[*not* [bold\\]\\|]"},
+		{name: "list", text: "Tiers:\n- Basic\n\n. Premium", expected: "Tiers: +\npass:c[- Basic] +\n{empty} +\npass:c[. Premium]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (AsciiDoc{}).Text(tt.text); got != tt.expected {
+				t.Errorf("Text() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAsciiDoc_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{name: "plain", code: "a | b", expected: "`+a \\| b+`"},
+		{name: "plus_backtick", code: "a +` b]", expected: "`pass:c[a +` b\\]]`"},
+		{name: "double_backticks", code: "a``b", expected: "`pass:c[a``b]`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (AsciiDoc{}).Code(tt.code); got != tt.expected {
+				t.Errorf("Code() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 /*
 Package markdown provides functionality to create a Markdown file from a Bicep template.
+The documentation can also be rendered in AsciiDoc or reStructuredText, see Renderer.
 */
 package markdown
 
@@ -11,14 +12,47 @@ import (
 	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
+// Options contains the settings that control the generated Markdown.
+//
+// Verbose controls whether informational messages are printed to stdout.
+// Sections contains the sections to include in the generated Markdown, in order.
+// Decorators contains the groups of decorators shown in the columns of the tables; a column that is empty in every row is omitted.
+// CompactConstraints controls whether the length and value constraints are merged into a single "Constraints" column,
+// e.g. "3–24 chars" or "1 ≤ x ≤ 10".
+// MetadataColumns contains the keys of the custom metadata entries of the parameters, outputs, and user-defined data types
+// (and their properties) shown as columns of their tables, after the "Example" column of their example values;
+// a column that is empty in every row is omitted.
+// GroupByCategory controls whether the parameters are grouped in a sub-table per category,
+// the value of their custom metadata entry "category" (e.g. @metadata({ category: 'Networking' })).
+// Footer controls whether a footer recording the compiler version is appended.
+// MetadataBadges contains the keys of the custom metadata entries shown as badges below the title.
+// MetadataHeader contains the keys of the custom metadata entries shown as fields below the title.
+// VariableValues controls whether the values of the variables are included in the variables table.
+// FrontMatter controls whether a YAML front matter (title, description, and tags) is prepended for static site generators.
+// Flavor is the Markdown dialect of the renderer the Markdown is published with; it defaults to GitHubFlavor.
+// Renderer renders the documentation in another markup language (e.g. AsciiDoc); if nil, the Markdown of Flavor is generated.
+type Options struct {
+	Verbose            bool
+	Sections           []types.Section
+	Decorators         []types.Decorator
+	CompactConstraints bool
+	MetadataColumns    []string
+	GroupByCategory    bool
+	Footer             bool
+	MetadataBadges     []string
+	MetadataHeader     []string
+	VariableValues     bool
+	FrontMatter        bool
+	Flavor             Flavor
+	Renderer           Renderer
+}
+
 // CreateFile creates or updates a file with the specified filename using the provided template.
 // If the file already exists and its content matches the generated Markdown string, no changes are made.
 // If the file does not exist or its content differs from the generated Markdown string, the file is created or updated accordingly.
-// The verbose parameter controls whether informational messages are printed to stdout.
-// The sections parameter specifies the sections to include in the generated Markdown string.
-// The showAllDecorators parameter controls whether to include all decorator columns in the output.
+// The options control the content of the generated Markdown string, see Options.
 // Returns an error if any operation fails.
-func CreateFile(filename string, template *types.Template, verbose bool, sections []types.Section, showAllDecorators bool) error { //nolint:gocyclo // This function is complex by design.
+func CreateFile(filename string, template *types.Template, opts Options) error { //nolint:gocyclo // This function is complex by design.
 	// Check if template is nil
 	if template == nil {
 		return fmt.Errorf("invalid template (nil)")
@@ -40,16 +74,14 @@ func CreateFile(filename string, template *types.Template, verbose bool, section
 	}
 
 	// Build Markdown string
-	var builder strings.Builder
-	builder.Grow(estimateMarkdownSize(template, sections))
-	if err := buildMarkdownString(&builder, template, sections, showAllDecorators); err != nil {
-		return fmt.Errorf("failed to build Markdown string: %w", err)
+	markdownString, err := Render(template, opts)
+	if err != nil {
+		return err
 	}
-	markdownString := builder.String()
 
 	// Check if file needs to be updated
 	if fileExists && fileContent == markdownString {
-		if verbose {
+		if opts.Verbose {
 			fmt.Printf("No changes to %s\n", filename)
 		}
 		return nil
@@ -69,7 +101,7 @@ func CreateFile(filename string, template *types.Template, verbose bool, section
 	}
 
 	// Print message to stdout
-	if verbose {
+	if opts.Verbose {
 		if fileExists {
 			fmt.Printf("Updated %s\n", filename)
 		} else {
@@ -80,7 +112,33 @@ func CreateFile(filename string, template *types.Template, verbose bool, section
 	return nil
 }
 
-func buildMarkdownString(builder *strings.Builder, template *types.Template, sections []types.Section, showAllDecorators bool) error {
+// Render returns the documentation of a template, as CreateFile writes it, in the markup language of the options
+// (see Options.Renderer). It is used to render the documentation without writing it to a file, e.g. in the pages of a site.
+// Returns an error if the template is nil or if a section cannot be generated.
+func Render(template *types.Template, opts Options) (string, error) {
+	if template == nil {
+		return "", fmt.Errorf("invalid template (nil)")
+	}
+	var builder strings.Builder
+	builder.Grow(estimateMarkdownSize(template, opts.Sections))
+	if err := buildMarkdownString(&builder, template, opts); err != nil {
+		return "", fmt.Errorf("failed to build Markdown string: %w", err)
+	}
+	return builder.String(), nil
+}
+
+func buildMarkdownString(builder *strings.Builder, template *types.Template, opts Options) error {
+	r := opts.Renderer
+	if r == nil {
+		r = opts.Flavor
+	}
+	if opts.FrontMatter {
+		if _, ok := r.(Flavor); !ok {
+			return fmt.Errorf("front matter is only supported in Markdown")
+		}
+		builder.WriteString(generateFrontMatter(template))
+	}
+
 	// Template metadata
 	var title *string
 	if template.Metadata == nil || template.Metadata.Name == nil || *template.Metadata.Name == "" {
@@ -88,46 +146,98 @@ func buildMarkdownString(builder *strings.Builder, template *types.Template, sec
 	} else {
 		title = template.Metadata.Name
 	}
-	fmt.Fprintf(builder, "# %s\n\n", *title)
+	headings := newHeadingRegistry(r)
+	builder.WriteString(headings.heading(H1, *title))
+	header, err := generateMetadataHeader(template, opts.MetadataBadges, opts.MetadataHeader, r)
+	if err != nil {
+		return err
+	}
+	builder.WriteString(header)
+	// The default scope (resourceGroup) is omitted
+	if template.TargetScope != "" && template.TargetScope != types.ResourceGroupScope {
+		fmt.Fprintf(builder, "%s %s\n\n", r.Strong("Target Scope:"), template.TargetScope)
+	}
 
 	// Create a mapping between the section enum and the corresponding markdown function
 	// Each function will be called in turn to generate a specific section of the markdown file.
 	// The order of the functions in the slice determines the order of the sections in the markdown file.
-	sectionMarkdownFunctions := map[types.Section]func(*types.Template, bool) (string, error){
-		types.DescriptionSection: func(t *types.Template, _ bool) (string, error) {
-			return generateDescriptionSection(t)
+	selection := decoratorSelection{decorators: opts.Decorators, compact: opts.CompactConstraints}
+	sectionMarkdownFunctions := map[types.Section]func(*types.Template) (string, error){
+		types.DescriptionSection: func(t *types.Template) (string, error) {
+			return generateDescriptionSection(t, r, headings)
+		},
+		types.MetadataSection: func(t *types.Template) (string, error) {
+			return generateMetadataSection(t, r, headings)
+		},
+		types.UsageSection: func(t *types.Template) (string, error) {
+			return generateUsageSection(t, r, headings)
+		},
+		types.ImportsSection: func(t *types.Template) (string, error) {
+			return generateImportsSection(t, r, headings)
+		},
+		types.ModulesSection: func(t *types.Template) (string, error) {
+			return generateModulesSection(t, r, headings)
+		},
+		types.ResourcesSection: func(t *types.Template) (string, error) {
+			return generateResourcesSection(t, r, headings)
+		},
+		types.ProvidersSection: func(t *types.Template) (string, error) {
+			return generateProvidersSection(t, r, headings)
+		},
+		types.ParametersSection: func(t *types.Template) (string, error) {
+			return generateParametersSection(t, selection, opts.MetadataColumns, opts.GroupByCategory, r, headings)
+		},
+		types.UserDefinedDataTypesSection: func(t *types.Template) (string, error) {
+			return generateUserDefinedDataTypesSection(t, selection, opts.MetadataColumns, r, headings)
+		},
+		types.UserDefinedFunctionsSection: func(t *types.Template) (string, error) {
+			return generateUserDefinedFunctionsSection(t, selection, r, headings)
 		},
-		types.UsageSection: func(t *types.Template, _ bool) (string, error) {
-			return generateUsageSection(t)
+		types.VariablesSection: func(t *types.Template) (string, error) {
+			return generateVariablesSection(t, selection, opts.VariableValues, r, headings)
 		},
-		types.ModulesSection: func(t *types.Template, _ bool) (string, error) {
-			return generateModulesSection(t)
+		types.OutputsSection: func(t *types.Template) (string, error) {
+			return generateOutputsSection(t, selection, opts.MetadataColumns, r, headings)
 		},
-		types.ResourcesSection: func(t *types.Template, _ bool) (string, error) {
-			return generateResourcesSection(t)
+		types.DiagnosticsSection: func(t *types.Template) (string, error) {
+			return generateDiagnosticsSection(t, r, headings)
 		},
-		types.ParametersSection:           generateParametersSection,
-		types.UserDefinedDataTypesSection: generateUserDefinedDataTypesSection,
-		types.UserDefinedFunctionsSection: generateUserDefinedFunctionsSection,
-		types.VariablesSection:            generateVariablesSection,
-		types.OutputsSection:              generateOutputsSection,
 	}
 
-	// Iterate over the sections slice and call the corresponding function for each section
-	for _, section := range sections {
+	// Iterate over the sections slice and call the corresponding function for each section.
+	// The table of contents lists the headings of every section, so it is generated once all of them are.
+	contents := make([]string, len(opts.Sections))
+	tableOfContents := -1
+	for i, section := range opts.Sections {
+		if section == types.TableOfContentsSection {
+			headings.add(H2, tableOfContentsTitle)
+			tableOfContents = i
+			continue
+		}
 		if function, ok := sectionMarkdownFunctions[section]; ok {
-			sectionContent, err := function(template, showAllDecorators)
+			sectionContent, err := function(template)
 			if err != nil {
 				return err
 			}
-			builder.WriteString(sectionContent)
-			if sectionContent != "" {
-				builder.WriteString("\n")
-			}
+			contents[i] = sectionContent
 		} else {
 			return fmt.Errorf("invalid section: %s", section)
 		}
 	}
+	if tableOfContents >= 0 {
+		contents[tableOfContents] = generateTableOfContents(headings)
+	}
+	for _, sectionContent := range contents {
+		builder.WriteString(sectionContent)
+		if sectionContent != "" {
+			builder.WriteString("\n")
+		}
+	}
+
+	// Footer
+	if opts.Footer {
+		builder.WriteString(generateFooter(template, r))
+	}
 
 	// Trim trailing newlines and add a single newline at the end
 	result := strings.TrimRight(builder.String(), "\n") + "\n"
@@ -149,12 +259,20 @@ func estimateMarkdownSize(template *types.Template, sections []types.Section) in
 			if template.Metadata != nil && template.Metadata.Description != nil {
 				baseSize += len(*template.Metadata.Description) + 50 // Add description length plus some overhead
 			}
+		case types.MetadataSection:
+			if template.Metadata != nil {
+				baseSize += len(template.Metadata.Custom) * 50 // Estimate 50 characters per metadata entry
+			}
 		case types.UsageSection:
 			baseSize += 150 + (len(template.Parameters) * 20) // Estimate based on number of parameters
+		case types.ImportsSection:
+			baseSize += (len(template.Imports) + len(template.Extensions)) * 100 // Estimate 100 characters per import or extension
 		case types.ModulesSection:
 			baseSize += len(template.Modules) * 100 // Estimate 100 characters per module
 		case types.ResourcesSection:
 			baseSize += len(template.Resources) * 150 // Estimate 150 characters per resource
+		case types.ProvidersSection:
+			baseSize += len(template.Resources) * 100 // Estimate 100 characters per resource type
 		case types.ParametersSection:
 			baseSize += len(template.Parameters) * 50 // Estimate 50 characters per parameter
 		case types.UserDefinedDataTypesSection:
@@ -165,6 +283,10 @@ func estimateMarkdownSize(template *types.Template, sections []types.Section) in
 			baseSize += len(template.Variables) * 40 // Estimate 40 characters per variable
 		case types.OutputsSection:
 			baseSize += len(template.Outputs) * 50 // Estimate 50 characters per output
+		case types.DiagnosticsSection:
+			baseSize += len(template.Diagnostics) * 150 // Estimate 150 characters per diagnostic
+		case types.TableOfContentsSection:
+			baseSize += (len(sections) + len(template.UserDefinedDataTypes) + len(template.UserDefinedFunctions)) * 40 // Estimate 40 characters per heading
 		}
 	}
 
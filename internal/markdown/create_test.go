@@ -13,7 +13,9 @@ import (
 var (
 	defaultSections = []types.Section{
 		types.DescriptionSection,
+		types.MetadataSection,
 		types.UsageSection,
+		types.ImportsSection,
 		types.ModulesSection,
 		types.ResourcesSection,
 		types.ParametersSection,
@@ -27,6 +29,7 @@ var (
 func TestCreateFile(t *testing.T) {
 	templateName := "test"
 	templateDescription := "This is a test template."
+	quotedDescription := "Deploys a \"hub\" virtual network."
 	parameterDescription := "This is a test parameter."
 	stringType := "string"
 	positiveIntType := "#/definitions/positive_int"
@@ -220,6 +223,24 @@ func TestCreateFile(t *testing.T) {
 		UserDefinedFunctions: []types.UserDefinedFunction{
 			{
 				Name: "build_url",
+				Parameters: []types.Parameter{
+					{
+						Name: "https",
+						Type: "bool",
+					},
+					{
+						Name: "hostname",
+						Type: stringType,
+						Metadata: &types.Metadata{
+							Description: func() *string { s := "The host name."; return &s }(),
+						},
+					},
+					{
+						Name:     "path",
+						Type:     stringType,
+						Nullable: true,
+					},
+				},
 				Output: types.Output{
 					Type: stringType,
 				},
@@ -229,6 +250,12 @@ func TestCreateFile(t *testing.T) {
 			},
 			{
 				Name: "double",
+				Parameters: []types.Parameter{
+					{
+						Name: "input",
+						Type: positiveIntType,
+					},
+				},
 				Output: types.Output{
 					Type: positiveIntType,
 				},
@@ -280,10 +307,123 @@ func TestCreateFile(t *testing.T) {
 		},
 	}
 
+	flavorTemplate := &types.Template{
+		FileName: "test.bicep",
+		Imports: []types.Import{
+			{Source: "../network/main.bicep", Symbols: []types.ImportedSymbol{{Name: "subnet", Kind: types.TypeSymbol}}},
+		},
+		Parameters: []types.Parameter{
+			{
+				Name: "tags",
+				Type: "object",
+				Metadata: &types.Metadata{
+					Description: func() *string { s := "The tags of the resources:\n- owner\n- cost center"; return &s }(),
+				},
+			},
+		},
+		UserDefinedDataTypes: []types.UserDefinedDataType{
+			{
+				Name: "storage_Config",
+				Type: "object",
+				Properties: []types.UserDefinedDataTypeProperty{
+					{Name: "name", Type: "string"},
+				},
+			},
+		},
+		Variables: []types.Variable{
+			{Name: "suffix", Value: "[if(parameters('short'), 'a|b', 'c')]"},
+			{Name: "settings", Value: map[string]any{"retention": float64(7), "tier": "Standard", "replication": "Geo-Redundant-Storage"}},
+		},
+	}
+
+	decoratorsTemplate := &types.Template{
+		FileName: "test.bicep",
+		Parameters: []types.Parameter{
+			{Name: "name", Type: "string", MinLength: intPtr(3), MaxLength: intPtr(24)},
+			{Name: "sku", Type: "string", AllowedValues: []any{"Basic", "Standard"}, DefaultValue: "Basic"},
+			{Name: "count", Type: "int", MinValue: intPtr(1), MaxValue: intPtr(10), DefaultValue: float64(1)},
+			{Name: "zones", Type: "array", MinLength: intPtr(1)},
+			{Name: "config", Type: "#/definitions/config"},
+		},
+		UserDefinedDataTypes: []types.UserDefinedDataType{
+			{
+				Name:   "config",
+				Type:   "object",
+				Sealed: true,
+				Properties: []types.UserDefinedDataTypeProperty{
+					{Name: "tier", Type: "string", AllowedValues: []any{"Hot", "Cool"}},
+					{Name: "retention", Type: "int", MinValue: intPtr(1), MaxValue: intPtr(365)},
+				},
+			},
+		},
+		Outputs: []types.Output{
+			{Name: "id", Type: "string"},
+			{Name: "names", Type: "array", MaxLength: intPtr(5)},
+		},
+	}
+
+	subnetDescription := "The ID of the subnet."
+	skuDescription := "The SKU of the account."
+	metadataTemplate := &types.Template{
+		FileName: "test.bicep",
+		Parameters: []types.Parameter{
+			{
+				Name: "location",
+				Type: "string",
+				Metadata: &types.Metadata{Custom: []types.MetadataItem{
+					{Key: "example", Value: "westeurope"},
+					{Key: "docsLink", Value: "https://learn.microsoft.com/azure/reliability/regions-list"},
+				}},
+			},
+			{
+				Name: "network",
+				Type: "object",
+				Metadata: &types.Metadata{Custom: []types.MetadataItem{
+					{Key: "example", Value: map[string]any{"addressPrefix": "10.0.0.0/16", "subnets": []any{"default", "private-endpoints"}}},
+				}},
+			},
+			{
+				Name:         "skuName",
+				Type:         "string",
+				DefaultValue: "Standard_LRS",
+				Metadata: &types.Metadata{
+					Description: &skuDescription,
+					Custom:      []types.MetadataItem{{Key: "deprecated", Value: "Use sku instead."}},
+				},
+			},
+			{Name: "legacy", Type: "bool", Nullable: true, Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "deprecated", Value: true}}}},
+		},
+		UserDefinedDataTypes: []types.UserDefinedDataType{
+			{
+				Name: "config",
+				Type: "object",
+				Properties: []types.UserDefinedDataTypeProperty{
+					{Name: "tier", Type: "string", Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "example", Value: "Hot"}}}},
+					{Name: "retention", Type: "int", Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "deprecated", Value: "Use policy instead."}}}},
+				},
+			},
+		},
+		Outputs: []types.Output{
+			{Name: "id", Type: "string"},
+			{Name: "endpoint", Type: "string", Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "docsLink", Value: "https://example.com/endpoints"}}}},
+		},
+	}
+
 	type args struct {
-		filename          string
-		template          *types.Template
-		showAllDecorators bool
+		filename           string
+		template           *types.Template
+		decorators         []types.Decorator
+		compactConstraints bool
+		metadataColumns    []string
+		groupByCategory    bool
+		footer             bool
+		metadataBadges     []string
+		metadataHeader     []string
+		variableValues     bool
+		frontMatter        bool
+		flavor             Flavor
+		renderer           Renderer
+		sections           []types.Section
 	}
 	tests := []struct {
 		name      string
@@ -294,9 +434,8 @@ func TestCreateFile(t *testing.T) {
 		{
 			name: "basic template",
 			args: args{
-				filename:          "basic.md",
-				template:          basicTemplate,
-				showAllDecorators: false,
+				filename: "basic.md",
+				template: basicTemplate,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/basic.md",
@@ -304,9 +443,8 @@ func TestCreateFile(t *testing.T) {
 		{
 			name: "extended template",
 			args: args{
-				filename:          "extended.md",
-				template:          extendedTemplate,
-				showAllDecorators: false,
+				filename: "extended.md",
+				template: extendedTemplate,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/extended.md",
@@ -332,7 +470,6 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/multiline_markup.md",
@@ -347,7 +484,6 @@ func TestCreateFile(t *testing.T) {
 						Description: &templateDescription,
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/no_name.md",
@@ -362,7 +498,6 @@ func TestCreateFile(t *testing.T) {
 						Name: &templateName,
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/no_description.md",
@@ -374,11 +509,162 @@ func TestCreateFile(t *testing.T) {
 				template: &types.Template{
 					FileName: "test.bicep",
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/no_metadata.md",
 		},
+		{
+			name: "footer",
+			args: args{
+				filename: "footer.md",
+				template: &types.Template{
+					FileName:        "test.bicep",
+					CompilerVersion: "Bicep CLI 0.38.33",
+				},
+				footer: true,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/footer.md",
+		},
+		{
+			name: "footer from generator",
+			args: args{
+				filename: "footer_generator.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Metadata: &types.Metadata{
+						Generator: &types.Generator{Name: "bicep", Version: "0.38.33.27573"},
+					},
+				},
+				footer: true,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/footer_generator.md",
+		},
+		{
+			name: "target scope",
+			args: args{
+				filename: "target_scope.md",
+				template: &types.Template{
+					FileName:    "test.bicep",
+					TargetScope: types.SubscriptionScope,
+				},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/target_scope.md",
+		},
+		{
+			name: "usage expressions",
+			args: args{
+				filename: "usage_expressions.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Parameters: []types.Parameter{
+						{
+							Name:          "environment",
+							Type:          "string",
+							AllowedValues: []any{"dev", "prod"},
+						},
+						{
+							Name:         "location",
+							Type:         "string",
+							DefaultValue: "[resourceGroup().location]",
+						},
+						{
+							Name:         "name",
+							Type:         "string",
+							DefaultValue: "[format('app-{0}-{1}', parameters('environment'), uniqueString(resourceGroup().id))]",
+						},
+						{
+							Name:         "names",
+							Type:         "array",
+							DefaultValue: "[map(createArray('a'), lambda('x', lambdaVariables('x')))]",
+						},
+						{
+							Name:         "tags",
+							Type:         "object",
+							DefaultValue: map[string]any{"cost-center": "it's", "environment": "[parameters('environment')]"},
+						},
+					},
+				},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/usage_expressions.md",
+		},
+		{
+			name: "usage registry reference",
+			args: args{
+				filename: "usage_registry.md",
+				template: &types.Template{
+					FileName:        "test.bicep",
+					ModuleReference: "br/modules:network/vnet:1.2.0",
+					ModulePath:      "./network/vnet/main.bicep",
+					Parameters: []types.Parameter{
+						{
+							Name: "name",
+							Type: "string",
+						},
+					},
+				},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/usage_registry.md",
+		},
+		{
+			name: "custom metadata",
+			args: args{
+				filename: "metadata.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Metadata: &types.Metadata{
+						Custom: []types.MetadataItem{
+							{Key: "owner", Value: "platform-team"},
+							{Key: "version", Value: "1.2.0"},
+							{Key: "docsUrl", Value: "https://example.com/docs"},
+							{Key: "tags", Value: []any{"network", "core"}},
+							{Key: "notes", Value: "Line 1\nLine 2 | with a pipe"},
+						},
+					},
+				},
+				metadataBadges: []string{"version", "docsUrl", "missing"},
+				metadataHeader: []string{"owner"},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/metadata.md",
+		},
+		{
+			name: "imports and extensions",
+			args: args{
+				filename: "imports.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Imports: []types.Import{
+						{
+							Source: "../shared/main.bicep",
+							Symbols: []types.ImportedSymbol{
+								{Name: "storageSku", Kind: types.TypeSymbol},
+								{Name: "buildName", Alias: "name", Kind: types.FunctionSymbol},
+							},
+						},
+						{
+							Source:   "types.bicep",
+							Wildcard: "shared",
+							Symbols:  []types.ImportedSymbol{{Name: "defaultTags", Kind: types.VariableSymbol}},
+						},
+						{
+							Source:   "br/modules:common:1.0.0",
+							Wildcard: "common",
+						},
+					},
+					Extensions: []types.Extension{
+						{Name: "microsoftGraphV1", Alias: "graph"},
+						{Name: "kubernetes", Alias: "k8s", Configurable: true},
+					},
+				},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/imports.md",
+		},
 		{
 			name: "secure params and outputs",
 			args: args{
@@ -443,7 +729,6 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/secure.md",
@@ -523,7 +808,6 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/sealed.md",
@@ -617,13 +901,12 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/export.md",
 		},
 		{
-			name: "exportable UDDTs and UDFs with show-all-decorators",
+			name: "exportable UDDTs and UDFs with all decorators",
 			args: args{
 				filename: "export_all.md",
 				template: &types.Template{
@@ -711,13 +994,13 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: true,
+				decorators: types.AllDecorators,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/export_all.md",
 		},
 		{
-			name: "exportable variables with show-all-decorators",
+			name: "exportable variables with all decorators",
 			args: args{
 				filename: "export_variables.md",
 				template: &types.Template{
@@ -734,11 +1017,236 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: true,
+				decorators: types.AllDecorators,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/export_variables.md",
 		},
+		{
+			name: "selected decorators",
+			args: args{
+				filename:   "decorators_selected.md",
+				template:   decoratorsTemplate,
+				decorators: []types.Decorator{types.AllowedDecorator, types.SealedDecorator},
+				sections:   []types.Section{types.ParametersSection, types.UserDefinedDataTypesSection, types.OutputsSection},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/decorators_selected.md",
+		},
+		{
+			name: "compact constraints",
+			args: args{
+				filename:           "decorators_compact.md",
+				template:           decoratorsTemplate,
+				decorators:         types.AllDecorators,
+				compactConstraints: true,
+				sections:           []types.Section{types.ParametersSection, types.UserDefinedDataTypesSection, types.OutputsSection},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/decorators_compact.md",
+		},
+		{
+			name: "parameter categories",
+			args: args{
+				filename: "categories.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Parameters: []types.Parameter{
+						{Name: "location", Type: "string", DefaultValue: "westeurope"},
+						{
+							Name: "subnetId",
+							Type: "string",
+							Metadata: &types.Metadata{
+								Description: &subnetDescription,
+								Custom:      []types.MetadataItem{{Key: "category", Value: "Networking"}},
+							},
+						},
+						{
+							Name:      "skuName",
+							Type:      "string",
+							MinLength: intPtr(1),
+							Metadata:  &types.Metadata{Custom: []types.MetadataItem{{Key: "category", Value: "Compute"}}},
+						},
+						{
+							Name:     "publicAccess",
+							Type:     "bool",
+							Nullable: true,
+							Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "category", Value: "Networking"}}},
+						},
+					},
+				},
+				decorators:      types.AllDecorators,
+				groupByCategory: true,
+				sections:        []types.Section{types.TableOfContentsSection, types.ParametersSection},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/categories.md",
+		},
+		{
+			name: "declaration metadata",
+			args: args{
+				filename:        "declaration_metadata.md",
+				template:        metadataTemplate,
+				metadataColumns: []string{"docsLink"},
+				sections:        []types.Section{types.ParametersSection, types.UserDefinedDataTypesSection, types.OutputsSection},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/declaration_metadata.md",
+		},
+		{
+			name: "declaration metadata in asciidoc",
+			args: args{
+				filename: "declaration_metadata.adoc",
+				template: metadataTemplate,
+				renderer: AsciiDoc{},
+				sections: []types.Section{types.ParametersSection},
+			},
+			wantErr:   false,
+			checkFile: "./testdata/declaration_metadata.adoc",
+		},
+		{
+			name: "variable values",
+			args: args{
+				filename: "variable_values.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Variables: []types.Variable{
+						{
+							Name:        "location",
+							Value:       "[resourceGroup().location]",
+							Description: "The location of the resources.",
+						},
+						{
+							Name:  "skus",
+							Value: []any{"Standard_LRS", "Premium_LRS|ZRS"},
+						},
+						{
+							Name:        "connectionString",
+							Value:       "[format('DefaultEndpointsProtocol=https;AccountName={0};EndpointSuffix={1}', parameters('name'), environment().suffixes.storage)]",
+							Description: "A long value.",
+						},
+						{
+							Name:      "storageNames",
+							Value:     "[variables('storageConfigs')[copyIndex('storageNames')].name]",
+							LoopCount: "[length(variables('storageConfigs'))]",
+						},
+						{
+							Name:  "names",
+							Value: "[map(parameters('items'), lambda('item', lambdaVariables('item').name))]",
+						},
+					},
+				},
+				variableValues: true,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/variable_values.md",
+		},
+		{
+			name: "front matter",
+			args: args{
+				filename: "front_matter.md",
+				template: &types.Template{
+					FileName: "network/vnet/main.bicep",
+					Metadata: &types.Metadata{
+						Description: &quotedDescription,
+						Custom:      []types.MetadataItem{{Key: "tags", Value: []any{"network", "core"}}},
+					},
+				},
+				frontMatter: true,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/front_matter.md",
+		},
+		{
+			name: "azure devops flavor",
+			args: args{
+				filename:       "flavor_azure_devops.md",
+				template:       flavorTemplate,
+				variableValues: true,
+				flavor:         AzureDevOpsFlavor,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/flavor_azure_devops.md",
+		},
+		{
+			name: "commonmark flavor",
+			args: args{
+				filename:       "flavor_commonmark.md",
+				template:       flavorTemplate,
+				variableValues: true,
+				flavor:         CommonMarkFlavor,
+			},
+			wantErr:   false,
+			checkFile: "./testdata/flavor_commonmark.md",
+		},
+		{
+			name: "asciidoc renderer",
+			args: args{
+				filename:       "renderer.adoc",
+				template:       flavorTemplate,
+				footer:         true,
+				variableValues: true,
+				renderer:       AsciiDoc{},
+				sections:       append([]types.Section{types.TableOfContentsSection}, defaultSections...),
+			},
+			wantErr:   false,
+			checkFile: "./testdata/renderer.adoc",
+		},
+		{
+			name: "restructuredtext renderer",
+			args: args{
+				filename:       "renderer.rst",
+				template:       flavorTemplate,
+				footer:         true,
+				variableValues: true,
+				renderer:       ReStructuredText{Document: "network/vnet/README"},
+				sections:       append([]types.Section{types.TableOfContentsSection}, defaultSections...),
+			},
+			wantErr:   false,
+			checkFile: "./testdata/renderer.rst",
+		},
+		{
+			name: "front matter with a renderer",
+			args: args{
+				filename:    "front_matter.adoc",
+				template:    flavorTemplate,
+				frontMatter: true,
+				renderer:    AsciiDoc{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "table of contents",
+			args: args{
+				filename: "toc.md",
+				template: &types.Template{
+					FileName: "test.bicep",
+					Parameters: []types.Parameter{
+						{Name: "config", Type: "#/definitions/parameters"},
+					},
+					UserDefinedDataTypes: []types.UserDefinedDataType{
+						{
+							Name:       "parameters",
+							Type:       "object",
+							Properties: []types.UserDefinedDataTypeProperty{{Name: "name", Type: "string"}},
+						},
+					},
+					UserDefinedFunctions: []types.UserDefinedFunction{
+						{
+							Name:       "outputs",
+							Parameters: []types.Parameter{{Name: "value", Type: "string"}},
+							Output:     types.Output{Type: "string"},
+						},
+					},
+					Outputs: []types.Output{
+						{Name: "name", Type: "string"},
+					},
+				},
+				sections: append([]types.Section{types.TableOfContentsSection}, defaultSections...),
+			},
+			wantErr:   false,
+			checkFile: "./testdata/toc.md",
+		},
 		{
 			name: "conditional resources and modules",
 			args: args{
@@ -772,7 +1280,6 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/condition.md",
@@ -803,7 +1310,6 @@ func TestCreateFile(t *testing.T) {
 						},
 					},
 				},
-				showAllDecorators: false,
 			},
 			wantErr:   false,
 			checkFile: "./testdata/decorators.md",
@@ -811,18 +1317,16 @@ func TestCreateFile(t *testing.T) {
 		{
 			name: "given path is a directory",
 			args: args{
-				filename:          "testdata",
-				template:          nil,
-				showAllDecorators: false,
+				filename: "testdata",
+				template: nil,
 			},
 			wantErr: true,
 		},
 		{
 			name: "nil template",
 			args: args{
-				filename:          "nil_template.md",
-				template:          nil,
-				showAllDecorators: false,
+				filename: "nil_template.md",
+				template: nil,
 			},
 			wantErr: true,
 		},
@@ -839,7 +1343,24 @@ func TestCreateFile(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call CreateFile with the filename in the temporary directory
 			filename := filepath.Join(tempDir, tt.args.filename)
-			if err := CreateFile(filename, tt.args.template, false, defaultSections, tt.args.showAllDecorators); (err != nil) != tt.wantErr {
+			sections := tt.args.sections
+			if sections == nil {
+				sections = defaultSections
+			}
+			if err := CreateFile(filename, tt.args.template, Options{
+				Sections:           sections,
+				Decorators:         tt.args.decorators,
+				CompactConstraints: tt.args.compactConstraints,
+				MetadataColumns:    tt.args.metadataColumns,
+				GroupByCategory:    tt.args.groupByCategory,
+				Footer:             tt.args.footer,
+				MetadataBadges:     tt.args.metadataBadges,
+				MetadataHeader:     tt.args.metadataHeader,
+				VariableValues:     tt.args.variableValues,
+				FrontMatter:        tt.args.frontMatter,
+				Flavor:             tt.args.flavor,
+				Renderer:           tt.args.renderer,
+			}); (err != nil) != tt.wantErr {
 				t.Errorf("CreateFile() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
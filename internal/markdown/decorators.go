@@ -0,0 +1,205 @@
+package markdown
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// decorators contains the decorators of a declaration (a parameter, a property, a user-defined data type, ...)
+// shown in the decorator columns of its table. The length unit is the one of its type, see lengthUnit.
+type decorators struct {
+	allowedValues []any
+	minLength     *int
+	maxLength     *int
+	minValue      *int
+	maxValue      *int
+	sealed        bool
+	exportable    bool
+	unit          string
+}
+
+// decoratorColumn is a decorator column of a table.
+type decoratorColumn struct {
+	header string
+	cell   func(d *decorators, r Renderer) string
+}
+
+// decoratorSelection is the groups of decorators shown in the decorator columns of the tables,
+// and whether the length and value constraints are merged into a single compact column.
+type decoratorSelection struct {
+	decorators []types.Decorator
+	compact    bool
+}
+
+// columns returns the decorator columns of a table, in order, for the groups of decorators
+// that the table supports and that are selected. In compact mode, the length and value constraints
+// are merged into a single "Constraints" column (see formatConstraints).
+func (s decoratorSelection) columns(supported ...types.Decorator) []decoratorColumn {
+	var columns []decoratorColumn
+	length := slices.Contains(s.decorators, types.LengthDecorator)
+	value := slices.Contains(s.decorators, types.ValueDecorator)
+	constraints := false
+	for _, decorator := range supported {
+		if !slices.Contains(s.decorators, decorator) {
+			continue
+		}
+		switch decorator {
+		case types.AllowedDecorator:
+			columns = append(columns, decoratorColumn{"Allowed Values", formatAllowedValues})
+		case types.LengthDecorator, types.ValueDecorator:
+			if s.compact {
+				if !constraints {
+					columns = append(columns, decoratorColumn{"Constraints", func(d *decorators, _ Renderer) string {
+						return formatConstraints(d, length, value)
+					}})
+					constraints = true
+				}
+			} else if decorator == types.LengthDecorator {
+				columns = append(columns,
+					decoratorColumn{"Min Length", func(d *decorators, _ Renderer) string { return formatInt(d.minLength) }},
+					decoratorColumn{"Max Length", func(d *decorators, _ Renderer) string { return formatInt(d.maxLength) }},
+				)
+			} else {
+				columns = append(columns,
+					decoratorColumn{"Min Value", func(d *decorators, _ Renderer) string { return formatInt(d.minValue) }},
+					decoratorColumn{"Max Value", func(d *decorators, _ Renderer) string { return formatInt(d.maxValue) }},
+				)
+			}
+		case types.SealedDecorator:
+			columns = append(columns, decoratorColumn{"Sealed", func(d *decorators, _ Renderer) string { return formatFlag(d.sealed) }})
+		case types.ExportableDecorator:
+			columns = append(columns, decoratorColumn{"Exportable", func(d *decorators, _ Renderer) string { return formatFlag(d.exportable) }})
+		}
+	}
+	return columns
+}
+
+// decoratorHeaders returns the headers of decorator columns.
+func decoratorHeaders(columns []decoratorColumn) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	return headers
+}
+
+// decoratorCells returns the cells of the decorator columns of a declaration.
+func decoratorCells(columns []decoratorColumn, d *decorators, r Renderer) []string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = column.cell(d, r)
+	}
+	return cells
+}
+
+// dropEmptyColumns removes the columns from first to first+count (the decorator columns of a table)
+// that are empty in every row.
+func dropEmptyColumns(headers []string, rows [][]string, first, count int) ([]string, [][]string) {
+	for column := first + count - 1; column >= first; column-- {
+		empty := true
+		for _, row := range rows {
+			if row[column] != "" {
+				empty = false
+				break
+			}
+		}
+		if !empty {
+			continue
+		}
+		headers = slices.Delete(slices.Clone(headers), column, column+1)
+		for i, row := range rows {
+			rows[i] = slices.Delete(row, column, column+1)
+		}
+	}
+	return headers, rows
+}
+
+// formatAllowedValues returns the allowed values as inline code, separated by commas.
+func formatAllowedValues(d *decorators, r Renderer) string {
+	values := make([]string, len(d.allowedValues))
+	for i, value := range d.allowedValues {
+		values[i] = r.Code(fmt.Sprint(value))
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatInt returns an integer, or an empty string if it is not set.
+func formatInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *value)
+}
+
+// formatFlag returns flagYes if a flag is set, or an empty string.
+func formatFlag(flag bool) string {
+	if flag {
+		return flagYes
+	}
+	return ""
+}
+
+// formatConstraints returns the length and value constraints of a declaration in a readable form,
+// e.g. "3–24 chars" or "1 ≤ x ≤ 10", separated by commas.
+func formatConstraints(d *decorators, length, value bool) string {
+	var constraints []string
+	if length && (d.minLength != nil || d.maxLength != nil) {
+		constraints = append(constraints, formatLength(d.minLength, d.maxLength, d.unit))
+	}
+	if value && (d.minValue != nil || d.maxValue != nil) {
+		constraints = append(constraints, formatRange(d.minValue, d.maxValue))
+	}
+	return strings.Join(constraints, ", ")
+}
+
+// formatLength returns a length constraint, e.g. "3–24 chars", "≥ 1 items", or "length ≤ 5" without a unit.
+func formatLength(minLength, maxLength *int, unit string) string {
+	var bounds string
+	switch {
+	case minLength != nil && maxLength != nil && *minLength == *maxLength:
+		bounds = fmt.Sprintf("%d", *minLength)
+	case minLength != nil && maxLength != nil:
+		bounds = fmt.Sprintf("%d–%d", *minLength, *maxLength)
+	case minLength != nil:
+		bounds = fmt.Sprintf("≥ %d", *minLength)
+	default:
+		bounds = fmt.Sprintf("≤ %d", *maxLength)
+	}
+	if unit == "" {
+		return "length " + bounds
+	}
+	return bounds + " " + unit
+}
+
+// formatRange returns a value constraint, e.g. "1 ≤ x ≤ 10", "x ≥ 0", or "x = 5".
+func formatRange(minValue, maxValue *int) string {
+	switch {
+	case minValue != nil && maxValue != nil && *minValue == *maxValue:
+		return fmt.Sprintf("x = %d", *minValue)
+	case minValue != nil && maxValue != nil:
+		return fmt.Sprintf("%d ≤ x ≤ %d", *minValue, *maxValue)
+	case minValue != nil:
+		return fmt.Sprintf("x ≥ %d", *minValue)
+	default:
+		return fmt.Sprintf("x ≤ %d", *maxValue)
+	}
+}
+
+// lengthUnit returns the unit of the length of a type: "chars" for strings, "items" for arrays,
+// and an empty string for other types (e.g. a user-defined data type).
+func lengthUnit(t string, items *types.Items) string {
+	switch strings.ToLower(t) {
+	case "string", "securestring":
+		return "chars"
+	case "array":
+		return "items"
+	default:
+		if items != nil {
+			return "items"
+		}
+		return ""
+	}
+}
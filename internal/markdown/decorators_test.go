@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func Test_formatConstraints(t *testing.T) {
+	tests := []struct {
+		name       string
+		decorators *decorators
+		length     bool
+		value      bool
+		want       string
+	}{
+		{name: "none", decorators: &decorators{unit: "chars"}, length: true, value: true, want: ""},
+		{name: "length_range", decorators: &decorators{minLength: intPtr(3), maxLength: intPtr(24), unit: "chars"}, length: true, want: "3–24 chars"},
+		{name: "exact_length", decorators: &decorators{minLength: intPtr(2), maxLength: intPtr(2), unit: "items"}, length: true, want: "2 items"},
+		{name: "min_length", decorators: &decorators{minLength: intPtr(1), unit: "items"}, length: true, want: "≥ 1 items"},
+		{name: "max_length_without_unit", decorators: &decorators{maxLength: intPtr(5)}, length: true, want: "length ≤ 5"},
+		{name: "value_range", decorators: &decorators{minValue: intPtr(1), maxValue: intPtr(10)}, value: true, want: "1 ≤ x ≤ 10"},
+		{name: "exact_value", decorators: &decorators{minValue: intPtr(5), maxValue: intPtr(5)}, value: true, want: "x = 5"},
+		{name: "min_value", decorators: &decorators{minValue: intPtr(0)}, value: true, want: "x ≥ 0"},
+		{name: "max_value", decorators: &decorators{maxValue: intPtr(365)}, value: true, want: "x ≤ 365"},
+		{
+			name:       "length_and_value",
+			decorators: &decorators{minLength: intPtr(1), maxLength: intPtr(3), minValue: intPtr(1), unit: "items"},
+			length:     true,
+			value:      true,
+			want:       "1–3 items, x ≥ 1",
+		},
+		{name: "value_not_selected", decorators: &decorators{minValue: intPtr(1), maxValue: intPtr(10)}, length: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatConstraints(tt.decorators, tt.length, tt.value); got != tt.want {
+				t.Errorf("formatConstraints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dropEmptyColumns(t *testing.T) {
+	headers := []string{"Name", "Allowed Values", "Min Length", "Max Length"}
+	rows := [][]string{
+		{"a", "", "", "24"},
+		{"", "", "", ""},
+	}
+	gotHeaders, gotRows := dropEmptyColumns(headers, rows, 1, 3)
+	if want := []string{"Name", "Max Length"}; !reflect.DeepEqual(gotHeaders, want) {
+		t.Errorf("dropEmptyColumns() headers = %v, want %v", gotHeaders, want)
+	}
+	if want := [][]string{{"a", "24"}, {"", ""}}; !reflect.DeepEqual(gotRows, want) {
+		t.Errorf("dropEmptyColumns() rows = %v, want %v", gotRows, want)
+	}
+	if want := []string{"Name", "Allowed Values", "Min Length", "Max Length"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("dropEmptyColumns() modified the headers: %v", headers)
+	}
+}
@@ -0,0 +1,253 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Flavor is the Markdown dialect of the renderer the documentation is published with, and the Renderer of its Markdown.
+// It controls the line breaks and HTML within table cells, the escaping of text,
+// the slugs of the heading anchors, and the links to the documentation of other modules.
+// The zero value is GitHubFlavor.
+type Flavor string
+
+const (
+	GitHubFlavor      Flavor = "github"       // GitHubFlavor is GitHub Flavored Markdown (the default)
+	AzureDevOpsFlavor Flavor = "azure-devops" // AzureDevOpsFlavor is the Markdown of Azure DevOps wikis
+	GitLabFlavor      Flavor = "gitlab"       // GitLabFlavor is GitLab Flavored Markdown
+	CommonMarkFlavor  Flavor = "commonmark"   // CommonMarkFlavor is CommonMark with pipe tables, without raw HTML
+)
+
+// Flavors contains the supported flavors.
+var Flavors = []Flavor{GitHubFlavor, AzureDevOpsFlavor, GitLabFlavor, CommonMarkFlavor}
+
+// ParseFlavor returns the flavor with the given name; an empty name is GitHubFlavor.
+// An error is returned if the flavor is not supported.
+func ParseFlavor(name string) (Flavor, error) {
+	if name == "" {
+		return GitHubFlavor, nil
+	}
+	for _, flavor := range Flavors {
+		if string(flavor) == name {
+			return flavor, nil
+		}
+	}
+	names := make([]string, len(Flavors))
+	for i, flavor := range Flavors {
+		names[i] = string(flavor)
+	}
+	return "", fmt.Errorf("invalid flavor %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// String returns the name of the flavor.
+func (f Flavor) String() string {
+	if f == "" {
+		return string(GitHubFlavor)
+	}
+	return string(f)
+}
+
+// html reports whether the flavor renders raw HTML, e.g. the <details> expanders of long values.
+// CommonMark renderers commonly disable raw HTML (e.g. markdown-it), so it is not used for CommonMarkFlavor.
+func (f Flavor) html() bool {
+	return f != CommonMarkFlavor
+}
+
+// lineBreak returns the line break within a table cell: an HTML break for the flavors that render HTML
+// (Azure DevOps wikis document the self-closing form), and a space otherwise.
+func (f Flavor) lineBreak() string {
+	switch f {
+	case AzureDevOpsFlavor:
+		return "<br/>"
+	case CommonMarkFlavor:
+		return " "
+	default:
+		return "<br>"
+	}
+}
+
+// escapeHTML escapes a text for an HTML element within a table cell.
+func (f Flavor) escapeHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "&#124;", "\n", f.lineBreak()).Replace(text)
+}
+
+// FileExtension returns the extension of Markdown files.
+func (f Flavor) FileExtension() string {
+	return ".md"
+}
+
+// Slug returns the anchor of a heading as generated by the renderer of the flavor:
+//
+//   - GitHubFlavor: lowercased, with spaces replaced by hyphens, and punctuation other than hyphens and underscores removed.
+//   - GitLabFlavor: likewise, with consecutive hyphens collapsed into one.
+//   - AzureDevOpsFlavor: lowercased, with spaces replaced by hyphens, and other characters percent-encoded.
+//   - CommonMarkFlavor: CommonMark does not define heading anchors; the slugs of markdown-it-anchor are used
+//     (lowercased, with runs of whitespace replaced by a hyphen, and other characters percent-encoded).
+func (f Flavor) Slug(heading string) string {
+	heading = strings.ToLower(strings.TrimSpace(heading))
+	switch f {
+	case AzureDevOpsFlavor:
+		return url.PathEscape(strings.ReplaceAll(heading, " ", "-"))
+	case CommonMarkFlavor:
+		return url.PathEscape(strings.Join(strings.Fields(heading), "-"))
+	}
+
+	var builder strings.Builder
+	for _, r := range heading {
+		switch {
+		case r == ' ':
+			builder.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		}
+	}
+	slug := builder.String()
+	if f == GitLabFlavor {
+		for strings.Contains(slug, "--") {
+			slug = strings.ReplaceAll(slug, "--", "-")
+		}
+	}
+	return slug
+}
+
+// Heading returns an ATX heading; its anchor is generated by the renderer of the flavor.
+func (f Flavor) Heading(level HeaderType, title, _ string) string {
+	return fmt.Sprintf("%s %s\n\n", level, title)
+}
+
+// Table returns a pipe table.
+func (f Flavor) Table(headers []string, rows [][]string) string {
+	var builder strings.Builder
+	builder.WriteString(generateTableHeaders(headers))
+	for _, row := range rows {
+		builder.WriteString(generateTableRow(row))
+	}
+	return builder.String()
+}
+
+// CodeBlock returns a fenced code block.
+func (f Flavor) CodeBlock(language, code string) string {
+	return fmt.Sprintf("```%s\n%s```\n", language, code)
+}
+
+// ListItem returns a bulleted list item, indented by two spaces per depth.
+func (f Flavor) ListItem(depth int, text string) string {
+	return fmt.Sprintf("%s- %s\n", strings.Repeat("  ", depth), text)
+}
+
+// Badges returns the badge images on a single line.
+func (f Flavor) Badges(badges []Badge) string {
+	images := make([]string, len(badges))
+	for i, badge := range badges {
+		images[i] = fmt.Sprintf("![%s](%s)", badge.Label, badge.Image)
+		if badge.Link != "" {
+			images[i] = fmt.Sprintf("[%s](%s)", images[i], badge.Link)
+		}
+	}
+	return strings.Join(images, " ") + "\n\n"
+}
+
+// Rule returns a thematic break.
+func (f Flavor) Rule() string {
+	return "---"
+}
+
+// Paragraph returns the text as it is: the prose of a template may be written in Markdown.
+func (f Flavor) Paragraph(text string) string {
+	return text + "\n"
+}
+
+// Text returns a text with its pipes escaped, so that they do not split a table cell,
+// and its line breaks replaced by the line breaks of the flavor.
+//
+// The escaping is the same for every flavor: all of them implement the pipe tables of GitHub Flavored Markdown
+// (GitLab natively, Azure DevOps wikis and CommonMark sites through markdown-it), which split a row into cells
+// on the pipes that are not escaped with a backslash, before the inlines of the cells (including code spans) are parsed.
+// The other inline markup of a text (e.g. emphasis) is left as it is, as in the prose of a template.
+func (f Flavor) Text(text string) string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", f.lineBreak())
+}
+
+// Lines returns the lines separated by the line breaks of the flavor.
+func (f Flavor) Lines(lines []string) string {
+	return strings.Join(lines, f.lineBreak())
+}
+
+// Code returns a code span, with its pipes escaped as in Text. The span is delimited by a run of backticks
+// longer than any run of backticks in the code, with a space on each side if the code starts or ends with a backtick
+// (the space is stripped by the renderer).
+func (f Flavor) Code(code string) string {
+	code = strings.ReplaceAll(code, "|", "\\|")
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	delimiter := strings.Repeat("`", longest+1)
+	return delimiter + code + delimiter
+}
+
+// Expandable returns a details/summary expander if the flavor renders HTML, and otherwise the code on a single line.
+func (f Flavor) Expandable(summary, code string) string {
+	if !f.html() {
+		if strings.Contains(code, "\n") {
+			code = strings.Join(strings.Fields(code), " ")
+		}
+		return f.Code(code)
+	}
+	return fmt.Sprintf("<details><summary><code>%s</code></summary><pre>%s</pre></details>", f.escapeHTML(summary), f.escapeHTML(code))
+}
+
+// Strong returns strongly emphasized text.
+func (f Flavor) Strong(text string) string {
+	return "**" + text + "**"
+}
+
+// Emphasis returns emphasized text.
+func (f Flavor) Emphasis(text string) string {
+	return "_" + text + "_"
+}
+
+// Deleted returns struck-through text; strikethrough is an extension of GitHub Flavored Markdown,
+// which Azure DevOps and GitLab support too, but not CommonMark.
+func (f Flavor) Deleted(text string) string {
+	if f == CommonMarkFlavor {
+		return text
+	}
+	return "~~" + text + "~~"
+}
+
+// Link returns an inline link.
+func (f Flavor) Link(text, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// DocumentLink returns an inline link to another Markdown document.
+// Azure DevOps wikis address pages by their path without the .md extension.
+func (f Flavor) DocumentLink(text, target string) string {
+	if f == AzureDevOpsFlavor && path.Ext(target) == ".md" {
+		target = strings.TrimSuffix(target, ".md")
+	}
+	return f.Link(text, target)
+}
+
+// Reference returns an inline link to the anchor of a heading.
+func (f Flavor) Reference(text, anchor string) string {
+	return f.Link(text, "#"+anchor)
+}
+
+// Anchor returns the text itself; the anchors of a Markdown document are generated from its headings.
+func (f Flavor) Anchor(text, _ string) string {
+	return text
+}
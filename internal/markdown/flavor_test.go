@@ -0,0 +1,120 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestParseFlavor(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Flavor
+		wantErr  bool
+	}{
+		{name: "default", input: "", expected: GitHubFlavor},
+		{name: "github", input: "github", expected: GitHubFlavor},
+		{name: "azure_devops", input: "azure-devops", expected: AzureDevOpsFlavor},
+		{name: "gitlab", input: "gitlab", expected: GitLabFlavor},
+		{name: "commonmark", input: "commonmark", expected: CommonMarkFlavor},
+		{name: "invalid", input: "bitbucket", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFlavor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFlavor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseFlavor() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlavor_Slug(t *testing.T) {
+	tests := []struct {
+		name     string
+		flavor   Flavor
+		heading  string
+		expected string
+	}{
+		{name: "default", heading: "storage_Config", expected: "storage_config"},
+		{name: "github", flavor: GitHubFlavor, heading: "User Defined Data Types (UDDTs)", expected: "user-defined-data-types-uddts"},
+		{name: "github_hyphens", flavor: GitHubFlavor, heading: "a - b", expected: "a---b"},
+		{name: "gitlab", flavor: GitLabFlavor, heading: "User Defined Data Types (UDDTs)", expected: "user-defined-data-types-uddts"},
+		{name: "gitlab_hyphens", flavor: GitLabFlavor, heading: "a - b", expected: "a-b"},
+		{name: "azure_devops", flavor: AzureDevOpsFlavor, heading: "User Defined Data Types (UDDTs)", expected: "user-defined-data-types-%28uddts%29"},
+		{name: "commonmark", flavor: CommonMarkFlavor, heading: " User  Defined (UDDTs) ", expected: "user-defined-%28uddts%29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flavor.Slug(tt.heading); got != tt.expected {
+				t.Errorf("Slug() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlavor_DocumentLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		flavor   Flavor
+		target   string
+		expected string
+	}{
+		{name: "github", flavor: GitHubFlavor, target: "../vnet/README.md", expected: "[vnet](../vnet/README.md)"},
+		{name: "azure_devops", flavor: AzureDevOpsFlavor, target: "../vnet/README.md", expected: "[vnet](../vnet/README)"},
+		{name: "azure_devops_source_file", flavor: AzureDevOpsFlavor, target: "../types.bicep", expected: "[vnet](../types.bicep)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flavor.DocumentLink("vnet", tt.target); got != tt.expected {
+				t.Errorf("DocumentLink() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlavor_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{name: "plain", code: "'eastus'", expected: "`'eastus'`"},
+		{name: "pipe", code: "a | b", expected: "`a \\| b`"},
+		{name: "backtick", code: "a`b", expected: "``a`b``"},
+		{name: "backticks", code: "a``b`c", expected: "```a``b`c```"},
+		{name: "leading_backtick", code: "`a", expected: "`` `a ``"},
+		{name: "trailing_backticks", code: "a``", expected: "``` a`` ```"},
+	}
+	for _, flavor := range Flavors {
+		for _, tt := range tests {
+			t.Run(string(flavor)+"_"+tt.name, func(t *testing.T) {
+				if got := flavor.Code(tt.code); got != tt.expected {
+					t.Errorf("Code() = %q, expected %q", got, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestFlavor_Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		flavor   Flavor
+		expected string
+	}{
+		{name: "github", flavor: GitHubFlavor, expected: "a \\| _b_<br>c"},
+		{name: "gitlab", flavor: GitLabFlavor, expected: "a \\| _b_<br>c"},
+		{name: "azure_devops", flavor: AzureDevOpsFlavor, expected: "a \\| _b_<br/>c"},
+		{name: "commonmark", flavor: CommonMarkFlavor, expected: "a \\| _b_ c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flavor.Text("a | _b_\r\nc"); got != tt.expected {
+				t.Errorf("Text() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
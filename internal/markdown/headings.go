@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tableOfContentsTitle is the title of the table of contents section.
+const tableOfContentsTitle = "Table of Contents"
+
+// heading is a heading of a document, with its anchor.
+type heading struct {
+	level HeaderType
+	title string
+	slug  string
+}
+
+// headingRegistry records the headings of a document in the order they are emitted,
+// and generates their anchors: the slugs of the renderer (see Renderer.Slug), de-duplicated as renderers do
+// by suffixing "-1", "-2", ... to the slugs of repeated headings (e.g. a user-defined data type named "parameters").
+//
+// Headings must be added in document order, so that their anchors match the ones generated by the renderer;
+// a section that links to one of its headings before emitting it (e.g. "View Properties") adds it up front.
+type headingRegistry struct {
+	renderer    Renderer
+	occurrences map[string]int
+	headings    []heading
+}
+
+// newHeadingRegistry returns an empty heading registry generating the anchors of a renderer.
+func newHeadingRegistry(renderer Renderer) *headingRegistry {
+	return &headingRegistry{renderer: renderer, occurrences: map[string]int{}}
+}
+
+// add records a heading and returns its anchor.
+func (r *headingRegistry) add(level HeaderType, title string) string {
+	base := r.renderer.Slug(title)
+	slug := base
+	for {
+		if _, ok := r.occurrences[slug]; !ok {
+			break
+		}
+		r.occurrences[base]++
+		slug = fmt.Sprintf("%s-%d", base, r.occurrences[base])
+	}
+	r.occurrences[slug] = 0
+	r.headings = append(r.headings, heading{level: level, title: title, slug: slug})
+	return slug
+}
+
+// heading records a heading and returns it.
+func (r *headingRegistry) heading(level HeaderType, title string) string {
+	return r.renderer.Heading(level, title, r.add(level, title))
+}
+
+// table records the heading of a table and returns the heading followed by the table.
+// The headers are rendered as text.
+func (r *headingRegistry) table(title string, level HeaderType, headers []string, rows [][]string) string {
+	return r.heading(level, title) + r.tableBody(headers, rows)
+}
+
+// tableBody returns a table whose heading is already recorded (see add).
+func (r *headingRegistry) tableBody(headers []string, rows [][]string) string {
+	renderedHeaders := make([]string, len(headers))
+	for i, header := range headers {
+		renderedHeaders[i] = r.renderer.Text(header)
+	}
+	return r.renderer.Table(renderedHeaders, rows)
+}
+
+// section records the heading of a section and returns it followed by its content.
+func (r *headingRegistry) section(title, content string) string {
+	return r.heading(H2, title) + content
+}
+
+// generateTableOfContents generates the table of contents of a document: a nested list referencing
+// every section and sub-table heading of the registry, except the title and the table of contents itself,
+// whose heading is already recorded. If there are no such headings, it returns an empty string.
+func generateTableOfContents(headings *headingRegistry) string {
+	var builder strings.Builder
+	var anchor string
+	for _, h := range headings.headings {
+		if h.level == H1 {
+			continue
+		}
+		if h.level == H2 && h.title == tableOfContentsTitle {
+			anchor = h.slug
+			continue
+		}
+		builder.WriteString(headings.renderer.ListItem(int(h.level-H2), headings.renderer.Reference(h.title, h.slug)))
+	}
+	if builder.Len() == 0 {
+		return ""
+	}
+	return headings.renderer.Heading(H2, tableOfContentsTitle, anchor) + builder.String()
+}
@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_headingRegistry_add(t *testing.T) {
+	tests := []struct {
+		name     string
+		flavor   Flavor
+		titles   []string
+		expected []string
+	}{
+		{
+			name:     "unique",
+			flavor:   GitHubFlavor,
+			titles:   []string{"Parameters", "User Defined Data Types (UDDTs)", "storage_Config"},
+			expected: []string{"parameters", "user-defined-data-types-uddts", "storage_config"},
+		},
+		{
+			name:     "duplicates",
+			flavor:   GitHubFlavor,
+			titles:   []string{"Parameters", "parameters", "parameters", "parameters-1"},
+			expected: []string{"parameters", "parameters-1", "parameters-2", "parameters-1-1"},
+		},
+		{
+			name:     "azure_devops",
+			flavor:   AzureDevOpsFlavor,
+			titles:   []string{"User Defined Functions (UDFs)", "user defined functions (udfs)"},
+			expected: []string{"user-defined-functions-%28udfs%29", "user-defined-functions-%28udfs%29-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headings := newHeadingRegistry(tt.flavor)
+			got := make([]string, len(tt.titles))
+			for i, title := range tt.titles {
+				got[i] = headings.add(H2, title)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("add() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_generateTableOfContents(t *testing.T) {
+	headings := newHeadingRegistry(GitHubFlavor)
+	if got := generateTableOfContents(headings); got != "" {
+		t.Errorf("generateTableOfContents() = %q, expected an empty string", got)
+	}
+
+	headings.add(H1, "main.bicep")
+	headings.add(H2, tableOfContentsTitle)
+	headings.add(H2, "Parameters")
+	headings.add(H2, "User Defined Data Types (UDDTs)")
+	headings.add(H3, "parameters")
+	expected := "## Table of Contents\n\n" +
+		"- [Parameters](#parameters)\n" +
+		"- [User Defined Data Types (UDDTs)](#user-defined-data-types-uddts)\n" +
+		"  - [parameters](#parameters-1)\n"
+	if got := generateTableOfContents(headings); got != expected {
+		t.Errorf("generateTableOfContents() = %q, expected %q", got, expected)
+	}
+}
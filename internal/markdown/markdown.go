@@ -4,10 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/christosgalano/bicep-docs/internal/bicep"
 	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
@@ -74,22 +80,9 @@ func NewMarkdownTable(title string, headerType HeaderType, headers []string, row
 }
 
 // String returns the string representation of the MarkdownTable.
-// It generates the table headers and rows and returns them as a single string.
+// It renders the title and the table in GitHub Flavored Markdown, see Renderer.
 func (table *MarkdownTable) String() string {
-	var builder strings.Builder
-
-	// Add title with proper header level
-	builder.WriteString(fmt.Sprintf("%s %s\n\n", table.HeaderType.String(), table.Title))
-
-	// Add table headers
-	builder.WriteString(generateTableHeaders(table.Headers))
-
-	// Add table rows
-	for _, row := range table.Rows {
-		builder.WriteString(generateTableRow(row))
-	}
-
-	return builder.String()
+	return GitHubFlavor.Heading(table.HeaderType, table.Title, "") + GitHubFlavor.Table(table.Headers, table.Rows)
 }
 
 // generateTableHeaders generates the markdown table headers based on the given slice of headers.
@@ -119,12 +112,83 @@ func generateTableRow(row []string) string {
 	return builder.String()
 }
 
+// generateImportsSection generates the imports section of a template: a table of the symbols brought in by
+// its import statements, with their kind and source, and a table of its extension declarations.
+// A local source links to the documentation of the exporting module (the README.md next to a main.bicep file),
+// or to the source file itself.
+// If the template has neither imports nor extensions, it returns an empty string.
+func generateImportsSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
+	var builder strings.Builder
+
+	if len(template.Imports) > 0 {
+		headers := []string{"Symbol", "Kind", "Source"}
+		var rows [][]string
+		for i := range template.Imports {
+			imp := &template.Imports[i]
+			source := importSource(imp, r)
+			if imp.Wildcard != "" && len(imp.Symbols) == 0 {
+				rows = append(rows, []string{r.Text(fmt.Sprintf("* as %s", imp.Wildcard)), "", source})
+				continue
+			}
+			for _, symbol := range imp.Symbols {
+				name := symbol.Name
+				switch {
+				case imp.Wildcard != "":
+					name = imp.Wildcard + "." + symbol.Name
+				case symbol.Alias != "":
+					name = fmt.Sprintf("%s (as %s)", symbol.Name, symbol.Alias)
+				}
+				rows = append(rows, []string{r.Text(name), symbolKindNames[symbol.Kind], source})
+			}
+		}
+		builder.WriteString(headings.table("Imports", H2, headers, rows))
+	}
+
+	if len(template.Extensions) > 0 {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		headers := []string{"Name", "Alias", "Configured"}
+		rows := make([][]string, len(template.Extensions))
+		for i, extension := range template.Extensions {
+			configured := ""
+			if extension.Configurable {
+				configured = flagYes
+			}
+			rows[i] = []string{r.Text(extension.Name), r.Text(extension.Alias), configured}
+		}
+		builder.WriteString(headings.table("Extensions", H2, headers, rows))
+	}
+
+	return builder.String(), nil
+}
+
+// symbolKindNames maps the kinds of imported symbols to their names in the imports table.
+var symbolKindNames = map[types.SymbolKind]string{
+	types.TypeSymbol:     "UDDT",
+	types.FunctionSymbol: "UDF",
+	types.VariableSymbol: "Variable",
+}
+
+// importSource returns the source of an import for the imports table: a link for a local source,
+// to the documentation of the exporting module (e.g. README.md) if it is a main.bicep file,
+// and the registry reference otherwise.
+func importSource(imp *types.Import, r Renderer) string {
+	if !imp.IsLocal() {
+		return r.Code(imp.Source)
+	}
+	if path.Base(imp.Source) == "main.bicep" {
+		return r.DocumentLink(imp.Source, path.Join(path.Dir(imp.Source), "README"+r.FileExtension()))
+	}
+	return r.Link(imp.Source, imp.Source)
+}
+
 // generateModulesSection converts a template's modules into a markdown table.
 // If the template has no modules, it returns an empty string.
 // The table headers are "Symbolic Name", "Source", and "Description".
 // A "Condition" column is added after "Source" if at least one module is conditional.
 // If an error occurs, it is returned along with an empty string.
-func generateModulesSection(template *types.Template) (string, error) {
+func generateModulesSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.Modules) == 0 {
 		return "", nil
 	}
@@ -145,15 +209,13 @@ func generateModulesSection(template *types.Template) (string, error) {
 
 	rows := make([][]string, len(template.Modules))
 	for i, module := range template.Modules {
-		description := strings.ReplaceAll(module.Description, "\r\n", "\n")
-		description = strings.ReplaceAll(description, "\n", "<br>")
-		row := []string{module.SymbolicName, module.Source}
+		row := []string{r.Text(module.SymbolicName), r.Text(module.Source)}
 		if showCondition {
-			row = append(row, formatBicepExpression(module.Condition))
+			row = append(row, formatBicepExpression(module.Condition, r))
 		}
-		rows[i] = append(row, description)
+		rows[i] = append(row, r.Text(module.Description))
 	}
-	return NewMarkdownTable("Modules", H2, headers, rows).String(), nil
+	return headings.table("Modules", H2, headers, rows), nil
 }
 
 // generateResourcesSection converts a template's resources into a markdown table.
@@ -162,7 +224,7 @@ func generateModulesSection(template *types.Template) (string, error) {
 // "Condition", "Retry On", and "Only If Not Exists" columns are added after "Type"
 // if at least one resource uses the corresponding construct.
 // If an error occurs, it is returned along with an empty string.
-func generateResourcesSection(template *types.Template) (string, error) {
+func generateResourcesSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.Resources) == 0 {
 		return "", nil
 	}
@@ -188,24 +250,86 @@ func generateResourcesSection(template *types.Template) (string, error) {
 
 	rows := make([][]string, len(template.Resources))
 	for i := range template.Resources {
-		rows[i] = resourceRow(&template.Resources[i], showCondition, showRetryOn, showOnlyIfNotExists)
+		rows[i] = resourceRow(&template.Resources[i], r, showCondition, showRetryOn, showOnlyIfNotExists)
 	}
-	return NewMarkdownTable("Resources", H2, headers, rows).String(), nil
+	return headings.table("Resources", H2, headers, rows), nil
+}
+
+// generateProvidersSection generates the resource providers section of a template.
+// It summarizes the distinct resource providers of the resources declared in the template,
+// with their resource types and the actions needed to deploy them (write) or to reference them (read, for existing resources),
+// so that the required provider registrations and RBAC permissions are known.
+// If the template has modules, the Microsoft.Resources provider is included for their nested deployments.
+// If the template has no resources and no modules, an empty string is returned.
+func generateProvidersSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
+	type provider struct {
+		types   []string
+		actions []string
+	}
+	providers := map[string]*provider{}
+	seen := map[string]bool{}
+	add := func(namespace, resourceType, action string) {
+		p, ok := providers[namespace]
+		if !ok {
+			p = &provider{}
+			providers[namespace] = p
+		}
+		if !seen[resourceType] {
+			seen[resourceType] = true
+			p.types = append(p.types, resourceType)
+		}
+		if !seen[action] {
+			seen[action] = true
+			p.actions = append(p.actions, action)
+		}
+	}
+
+	for i := range template.Resources {
+		resource := &template.Resources[i]
+		namespace := resource.Provider()
+		if namespace == "" {
+			continue
+		}
+		action := resource.Type + "/write"
+		if resource.Existing {
+			action = resource.Type + "/read"
+		}
+		add(namespace, resource.Type, action)
+	}
+	if len(template.Modules) > 0 {
+		add("Microsoft.Resources", "Microsoft.Resources/deployments", "Microsoft.Resources/deployments/write")
+	}
+	if len(providers) == 0 {
+		return "", nil
+	}
+
+	namespaces := make([]string, 0, len(providers))
+	for namespace := range providers {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	headers := []string{"Provider", "Resource Types", "Actions"}
+	rows := make([][]string, len(namespaces))
+	for i, namespace := range namespaces {
+		p := providers[namespace]
+		sort.Strings(p.types)
+		sort.Strings(p.actions)
+		rows[i] = []string{r.Text(namespace), r.Lines(textLines(p.types, r)), r.Lines(textLines(p.actions, r))}
+	}
+	return headings.table("Resource Providers", H2, headers, rows), nil
 }
 
 // resourceRow builds the markdown table row of a single resource.
 // The condition and decorator columns are included only when the corresponding show flag is set.
-func resourceRow(resource *types.Resource, showCondition, showRetryOn, showOnlyIfNotExists bool) []string {
-	typeLink := fmt.Sprintf("[%s](https://learn.microsoft.com/en-us/azure/templates/%s)", resource.Type, strings.ToLower(resource.Type))
-	description := strings.ReplaceAll(resource.Description, "\r\n", "\n")
-	description = strings.ReplaceAll(description, "\n", "<br>")
-
-	row := []string{resource.SymbolicName, typeLink}
+func resourceRow(resource *types.Resource, r Renderer, showCondition, showRetryOn, showOnlyIfNotExists bool) []string {
+	typeLink := r.Link(resource.Type, "https://learn.microsoft.com/en-us/azure/templates/"+strings.ToLower(resource.Type))
+	row := []string{r.Text(resource.SymbolicName), typeLink}
 	if showCondition {
-		row = append(row, formatBicepExpression(resource.Condition))
+		row = append(row, formatBicepExpression(resource.Condition, r))
 	}
 	if showRetryOn {
-		row = append(row, formatBicepExpression(resource.RetryOn))
+		row = append(row, formatBicepExpression(resource.RetryOn, r))
 	}
 	if showOnlyIfNotExists {
 		onlyIfNotExistsVal := ""
@@ -214,35 +338,42 @@ func resourceRow(resource *types.Resource, showCondition, showRetryOn, showOnlyI
 		}
 		row = append(row, onlyIfNotExistsVal)
 	}
-	return append(row, description)
+	return append(row, r.Text(resource.Description))
 }
 
-// formatBicepExpression formats a Bicep expression for display inside a markdown table cell.
-// Non-empty expressions are rendered as inline code with pipe characters escaped,
+// formatBicepExpression formats a Bicep expression for display inside a table cell.
+// Non-empty expressions are rendered as inline code, which the renderer escapes
 // so that expressions containing "||" do not break the table layout.
-func formatBicepExpression(expression string) string {
+func formatBicepExpression(expression string, r Renderer) string {
 	if expression == "" {
 		return ""
 	}
-	return fmt.Sprintf("`%s`", strings.ReplaceAll(expression, "|", "\\|"))
+	return r.Code(expression)
 }
 
 // generateParametersSection generates the parameters section of a template in markdown format.
 // It takes a pointer to a types.Template as input and returns the generated markdown string and an error, if any.
+// The example values and the custom metadata entries of metadataKeys are shown in columns after the default value,
+// and the deprecated parameters are struck through (see formatName and formatDescription).
+// If groupByCategory is set, the parameters with a category (see types.Metadata.Category) are shown in a sub-table
+// per category, in order of first appearance, after the table of the parameters without one.
 // If the template has no parameters, it returns an empty string and a nil error.
-func generateParametersSection(template *types.Template, showAllDecorators bool) (string, error) { //nolint:gocyclo // This function is complex by design.
+func generateParametersSection(template *types.Template, selection decoratorSelection, metadataKeys []string, groupByCategory bool, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.Parameters) == 0 {
 		return "", nil
 	}
 
 	re := regexp.MustCompile(`([^ ]):([^ ])|([^ ]),([^ ])`)
 
-	// Base headers
+	// Base headers, followed by the metadata columns and the selected decorator columns
 	headers := []string{"Name", "Status", "Type", "Description", "Default"}
-
-	// Add decorator columns if flag is enabled
-	if showAllDecorators {
-		headers = append(headers, "Allowed Values", "Min Length", "Max Length", "Min Value", "Max Value")
+	metadataColumns := metadataHeaders(metadataKeys)
+	headers = append(headers, metadataColumns...)
+	columns := selection.columns(types.AllowedDecorator, types.LengthDecorator, types.ValueDecorator)
+	headers = append(headers, decoratorHeaders(columns)...)
+	dropEmpty := func(rows [][]string) ([]string, [][]string) {
+		tableHeaders, rows := dropEmptyColumns(headers, rows, len(headers)-len(columns), len(columns))
+		return dropEmptyColumns(tableHeaders, rows, 5, len(metadataColumns))
 	}
 
 	rows := make([][]string, len(template.Parameters))
@@ -264,8 +395,7 @@ func generateParametersSection(template *types.Template, showAllDecorators bool)
 				}
 				return s
 			})
-			defaultValue = strings.ReplaceAll(defaultValue, "\r\n", "\n")
-			defaultValue = strings.ReplaceAll(defaultValue, "\n", "<br>")
+			defaultValue = r.Text(defaultValue)
 		case parameter.Nullable:
 			defaultValue = "null"
 		default:
@@ -274,130 +404,142 @@ func generateParametersSection(template *types.Template, showAllDecorators bool)
 
 		parameterStatus := parameter.GetStatus()
 		parameterType := extractType(parameter.Type, parameter.Items)
-		description := extractDescription(parameter.Metadata)
+		description := formatDescription(parameter.Metadata, r)
+		metadata, err := metadataCells(parameter.Metadata, metadataKeys, r)
+		if err != nil {
+			return "", fmt.Errorf("failed to format the metadata of %s: %w", parameter.Name, err)
+		}
 
 		row := []string{
-			parameter.Name,
+			r.Anchor(formatName(parameter.Name, parameter.Metadata, r), ParameterAnchor(parameter.Name)),
 			parameterStatus.String(),
-			parameterType,
+			r.Text(parameterType),
 			description,
 			defaultValue,
 		}
+		row = append(row, metadata...)
+
+		rows[i] = append(row, decoratorCells(columns, &decorators{
+			allowedValues: parameter.AllowedValues,
+			minLength:     parameter.MinLength,
+			maxLength:     parameter.MaxLength,
+			minValue:      parameter.MinValue,
+			maxValue:      parameter.MaxValue,
+			unit:          lengthUnit(parameter.Type, parameter.Items),
+		}, r)...)
+	}
 
-		// Add decorator columns if flag is enabled
-		if showAllDecorators {
-			// Format allowed values
-			allowedValues := ""
-			if len(parameter.AllowedValues) > 0 {
-				values := make([]string, len(parameter.AllowedValues))
-				for j, v := range parameter.AllowedValues {
-					values[j] = fmt.Sprintf("`%v`", v)
-				}
-				allowedValues = strings.Join(values, ", ")
-			}
-
-			// Format constraint values
-			minLength := ""
-			if parameter.MinLength != nil {
-				minLength = fmt.Sprintf("%d", *parameter.MinLength)
-			}
-
-			maxLength := ""
-			if parameter.MaxLength != nil {
-				maxLength = fmt.Sprintf("%d", *parameter.MaxLength)
-			}
-
-			minValue := ""
-			if parameter.MinValue != nil {
-				minValue = fmt.Sprintf("%d", *parameter.MinValue)
+	var categories []string
+	categoryRows := map[string][][]string{}
+	if groupByCategory {
+		var uncategorized [][]string
+		for i := range template.Parameters {
+			category := template.Parameters[i].Metadata.Category()
+			if category == "" {
+				uncategorized = append(uncategorized, rows[i])
+				continue
 			}
-
-			maxValue := ""
-			if parameter.MaxValue != nil {
-				maxValue = fmt.Sprintf("%d", *parameter.MaxValue)
+			if _, ok := categoryRows[category]; !ok {
+				categories = append(categories, category)
 			}
-
-			row = append(row, allowedValues, minLength, maxLength, minValue, maxValue)
+			categoryRows[category] = append(categoryRows[category], rows[i])
 		}
-
-		rows[i] = row
+		rows = uncategorized
+	}
+	if len(categories) == 0 {
+		headers, rows = dropEmpty(rows)
+		return headings.table("Parameters", H2, headers, rows), nil
 	}
 
-	return NewMarkdownTable("Parameters", H2, headers, rows).String(), nil
+	// Parameters without a category, followed by a sub-table per category
+	section := headings.heading(H2, "Parameters")
+	separator := ""
+	if len(rows) > 0 {
+		tableHeaders, tableRows := dropEmpty(rows)
+		section += headings.tableBody(tableHeaders, tableRows)
+		separator = "\n"
+	}
+	for _, category := range categories {
+		tableHeaders, tableRows := dropEmpty(categoryRows[category])
+		section += separator + headings.heading(H3, category) + headings.tableBody(tableHeaders, tableRows)
+		separator = "\n"
+	}
+	return section, nil
 }
 
 // generateOutputsSection generates the outputs section of the template markdown.
 // It takes a pointer to a types.Template and returns a string representation of the outputs section and an error, if any.
+// The example values and the custom metadata entries of metadataKeys are shown in columns after the description,
+// and the deprecated outputs are struck through (see formatName and formatDescription).
 // If the template has no outputs, it returns an empty string and no error.
-func generateOutputsSection(template *types.Template, showAllDecorators bool) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
+func generateOutputsSection(template *types.Template, selection decoratorSelection, metadataKeys []string, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.Outputs) == 0 {
 		return "", nil
 	}
 
 	headers := []string{"Name", "Type", "Description"}
-
-	// Add decorator columns if flag is enabled
-	if showAllDecorators {
-		headers = append(headers, "Min Length", "Max Length", "Min Value", "Max Value")
-	}
+	metadataColumns := metadataHeaders(metadataKeys)
+	headers = append(headers, metadataColumns...)
+	columns := selection.columns(types.LengthDecorator, types.ValueDecorator)
+	headers = append(headers, decoratorHeaders(columns)...)
 
 	rows := make([][]string, len(template.Outputs))
 
 	for i, output := range template.Outputs {
-		row := []string{
-			output.Name,
-			extractType(output.Type, output.Items),
-			extractDescription(output.Metadata),
+		metadata, err := metadataCells(output.Metadata, metadataKeys, r)
+		if err != nil {
+			return "", fmt.Errorf("failed to format the metadata of %s: %w", output.Name, err)
 		}
-
-		// Add decorator columns if flag is enabled
-		if showAllDecorators {
-			// Format constraint values
-			minLength := ""
-			if output.MinLength != nil {
-				minLength = fmt.Sprintf("%d", *output.MinLength)
-			}
-
-			maxLength := ""
-			if output.MaxLength != nil {
-				maxLength = fmt.Sprintf("%d", *output.MaxLength)
-			}
-
-			minValue := ""
-			if output.MinValue != nil {
-				minValue = fmt.Sprintf("%d", *output.MinValue)
-			}
-
-			maxValue := ""
-			if output.MaxValue != nil {
-				maxValue = fmt.Sprintf("%d", *output.MaxValue)
-			}
-
-			row = append(row, minLength, maxLength, minValue, maxValue)
+		row := []string{
+			r.Anchor(formatName(output.Name, output.Metadata, r), OutputAnchor(output.Name)),
+			r.Text(extractType(output.Type, output.Items)),
+			formatDescription(output.Metadata, r),
 		}
-
-		rows[i] = row
+		row = append(row, metadata...)
+
+		rows[i] = append(row, decoratorCells(columns, &decorators{
+			minLength: output.MinLength,
+			maxLength: output.MaxLength,
+			minValue:  output.MinValue,
+			maxValue:  output.MaxValue,
+			unit:      lengthUnit(output.Type, output.Items),
+		}, r)...)
 	}
 
-	return NewMarkdownTable("Outputs", H2, headers, rows).String(), nil
+	headers, rows = dropEmptyColumns(headers, rows, len(headers)-len(columns), len(columns))
+	headers, rows = dropEmptyColumns(headers, rows, 3, len(metadataColumns))
+	return headings.table("Outputs", H2, headers, rows), nil
 }
 
 // generateUserDefinedDataTypesSection generates a markdown table section for user-defined data types (UDDTs) based on the provided template.
 // If there are no user-defined data types in the template, an empty string is returned.
-// The table includes columns for Name, Type, Description, and conditionally Exportable and constraint information.
+// The table includes columns for Name, Type, Description, and Properties, and the metadata columns
+// (the example values and the custom metadata entries of metadataKeys) and selected decorator columns
+// (Sealed and Exportable before Properties, the constraints after it) that are not empty.
 // Each row in the table represents a user-defined data type, with the corresponding values extracted from the template.
+// The deprecated types and properties are struck through (see formatName and formatDescription).
 // The function returns the generated markdown table as a string and any error encountered during the process.
-func generateUserDefinedDataTypesSection(template *types.Template, showAllDecorators bool) (string, error) { //nolint:gocyclo,unparam,funlen // This function is complex by design.
+func generateUserDefinedDataTypesSection(template *types.Template, selection decoratorSelection, metadataKeys []string, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.UserDefinedDataTypes) == 0 {
 		return "", nil
 	}
 
-	// Build headers: Name, Type, Description, [Sealed, Exportable, constraints...], Properties
-	var headers []string
-	if showAllDecorators {
-		headers = []string{"Name", "Type", "Description", "Sealed", "Exportable", "Properties", "Min Length", "Max Length", "Min Value", "Max Value"}
-	} else {
-		headers = []string{"Name", "Type", "Description", "Properties"}
+	// Build headers: Name, Type, Description, [metadata...], [Sealed, Exportable], Properties, [constraints...]
+	metadataColumns := metadataHeaders(metadataKeys)
+	flagColumns := selection.columns(types.SealedDecorator, types.ExportableDecorator)
+	constraintColumns := selection.columns(types.LengthDecorator, types.ValueDecorator)
+	headers := append([]string{"Name", "Type", "Description"}, metadataColumns...)
+	headers = append(headers, decoratorHeaders(flagColumns)...)
+	headers = append(append(headers, "Properties"), decoratorHeaders(constraintColumns)...)
+
+	// The sub-tables of the properties follow the table; their headings are added first, so that the table links to them
+	title := "User Defined Data Types (UDDTs)"
+	anchor := headings.add(H2, title)
+	anchors := make([]string, len(template.UserDefinedDataTypes))
+	for i, dataType := range template.UserDefinedDataTypes {
+		if len(dataType.Properties) > 0 {
+			anchors[i] = headings.add(H3, dataType.Name)
+		}
 	}
 
 	rows := make([][]string, len(template.UserDefinedDataTypes))
@@ -405,113 +547,71 @@ func generateUserDefinedDataTypesSection(template *types.Template, showAllDecora
 	for i, dataType := range template.UserDefinedDataTypes {
 		propertiesColumn := ""
 		if len(dataType.Properties) > 0 {
-			propertiesColumn = fmt.Sprintf("[View Properties](#%s)", strings.ToLower(dataType.Name))
+			propertiesColumn = r.Reference("View Properties", anchors[i])
 		}
 
-		description := extractDescription(dataType.Metadata)
-
-		var row []string
-		if showAllDecorators {
-			sealedVal := ""
-			if dataType.Sealed {
-				sealedVal = flagYes
-			}
-
-			exportableVal := ""
-			if dataType.IsExportable() {
-				exportableVal = flagYes
-			}
-
-			minLength := ""
-			if dataType.MinLength != nil {
-				minLength = fmt.Sprintf("%d", *dataType.MinLength)
-			}
-
-			maxLength := ""
-			if dataType.MaxLength != nil {
-				maxLength = fmt.Sprintf("%d", *dataType.MaxLength)
-			}
-
-			minValue := ""
-			if dataType.MinValue != nil {
-				minValue = fmt.Sprintf("%d", *dataType.MinValue)
-			}
-
-			maxValue := ""
-			if dataType.MaxValue != nil {
-				maxValue = fmt.Sprintf("%d", *dataType.MaxValue)
-			}
-
-			row = []string{dataType.Name, extractType(dataType.Type, dataType.Items), description, sealedVal, exportableVal, propertiesColumn, minLength, maxLength, minValue, maxValue}
-		} else {
-			row = []string{dataType.Name, extractType(dataType.Type, dataType.Items), description, propertiesColumn}
+		dataTypeDecorators := &decorators{
+			minLength:  dataType.MinLength,
+			maxLength:  dataType.MaxLength,
+			minValue:   dataType.MinValue,
+			maxValue:   dataType.MaxValue,
+			sealed:     dataType.Sealed,
+			exportable: dataType.IsExportable(),
+			unit:       lengthUnit(dataType.Type, dataType.Items),
 		}
-
-		rows[i] = row
+		metadata, err := metadataCells(dataType.Metadata, metadataKeys, r)
+		if err != nil {
+			return "", fmt.Errorf("failed to format the metadata of %s: %w", dataType.Name, err)
+		}
+		row := []string{
+			r.Anchor(formatName(dataType.Name, dataType.Metadata, r), TypeAnchor(dataType.Name)),
+			r.Text(extractType(dataType.Type, dataType.Items)),
+			formatDescription(dataType.Metadata, r),
+		}
+		row = append(row, metadata...)
+		row = append(row, decoratorCells(flagColumns, dataTypeDecorators, r)...)
+		row = append(row, propertiesColumn)
+		rows[i] = append(row, decoratorCells(constraintColumns, dataTypeDecorators, r)...)
 	}
 
-	table := NewMarkdownTable("User Defined Data Types (UDDTs)", H2, headers, rows).String()
+	headers, rows = dropEmptyColumns(headers, rows, len(headers)-len(constraintColumns), len(constraintColumns))
+	headers, rows = dropEmptyColumns(headers, rows, 3+len(metadataColumns), len(flagColumns))
+	headers, rows = dropEmptyColumns(headers, rows, 3, len(metadataColumns))
+	table := r.Heading(H2, title, anchor) + headings.tableBody(headers, rows)
 
-	// Sub-tables for properties with conditional decorator columns
-	propertyHeaders := []string{"Name", "Type", "Description"}
+	// Sub-tables for properties, with the metadata columns and the selected decorator columns
+	propertyColumns := selection.columns(types.AllowedDecorator, types.LengthDecorator, types.ValueDecorator)
+	propertyHeaders := append([]string{"Name", "Type", "Description"}, metadataColumns...)
+	propertyHeaders = append(propertyHeaders, decoratorHeaders(propertyColumns)...)
 
-	// Add decorator columns if flag is enabled
-	if showAllDecorators {
-		propertyHeaders = append(propertyHeaders, "Allowed Values", "Min Length", "Max Length", "Min Value", "Max Value")
-	}
-
-	for _, dataType := range template.UserDefinedDataTypes {
+	for k, dataType := range template.UserDefinedDataTypes {
 		if len(dataType.Properties) == 0 {
 			continue
 		}
 		propertyRows := make([][]string, len(dataType.Properties))
 		for i, property := range dataType.Properties {
-			// Base row
-			row := []string{
-				property.Name,
-				extractType(property.Type, property.Items),
-				extractDescription(property.Metadata),
+			metadata, err := metadataCells(property.Metadata, metadataKeys, r)
+			if err != nil {
+				return "", fmt.Errorf("failed to format the metadata of %s.%s: %w", dataType.Name, property.Name, err)
 			}
-
-			// Add decorator columns if flag is enabled
-			if showAllDecorators {
-				// Format allowed values
-				allowedValues := ""
-				if len(property.AllowedValues) > 0 {
-					values := make([]string, len(property.AllowedValues))
-					for j, v := range property.AllowedValues {
-						values[j] = fmt.Sprintf("`%v`", v)
-					}
-					allowedValues = strings.Join(values, ", ")
-				}
-
-				// Format constraint values
-				minLength := ""
-				if property.MinLength != nil {
-					minLength = fmt.Sprintf("%d", *property.MinLength)
-				}
-
-				maxLength := ""
-				if property.MaxLength != nil {
-					maxLength = fmt.Sprintf("%d", *property.MaxLength)
-				}
-
-				minValue := ""
-				if property.MinValue != nil {
-					minValue = fmt.Sprintf("%d", *property.MinValue)
-				}
-
-				maxValue := ""
-				if property.MaxValue != nil {
-					maxValue = fmt.Sprintf("%d", *property.MaxValue)
-				}
-
-				row = append(row, allowedValues, minLength, maxLength, minValue, maxValue)
+			row := []string{
+				formatName(property.Name, property.Metadata, r),
+				r.Text(extractType(property.Type, property.Items)),
+				formatDescription(property.Metadata, r),
 			}
-
-			propertyRows[i] = row
+			row = append(row, metadata...)
+			propertyRows[i] = append(row, decoratorCells(propertyColumns, &decorators{
+				allowedValues: property.AllowedValues,
+				minLength:     property.MinLength,
+				maxLength:     property.MaxLength,
+				minValue:      property.MinValue,
+				maxValue:      property.MaxValue,
+				unit:          lengthUnit(property.Type, property.Items),
+			}, r)...)
 		}
-		table += "\n" + NewMarkdownTable(dataType.Name, H3, propertyHeaders, propertyRows).String()
+		headers, propertyRows := dropEmptyColumns(propertyHeaders, propertyRows, len(propertyHeaders)-len(propertyColumns), len(propertyColumns))
+		headers, propertyRows = dropEmptyColumns(headers, propertyRows, 3, len(metadataColumns))
+		table += "\n" + r.Heading(H3, dataType.Name, anchors[k]) + headings.tableBody(headers, propertyRows)
 	}
 
 	return table, nil
@@ -519,144 +619,387 @@ func generateUserDefinedDataTypesSection(template *types.Template, showAllDecora
 
 // generateUserDefinedFunctionsSection converts a template's user-defined functions into a markdown table.
 // If the template has no user-defined functions, it returns an empty string.
-// The table headers conditionally include "Name", "Description", "Exportable", and "Output Type".
+// The table headers conditionally include "Name", "Signature", "Description", "Exportable", and "Parameters".
+// The signature is the full declaration of the function, e.g. "buildName(prefix string, index int) string".
+// A sub-table of the parameters, with their types and descriptions, is added for each function with parameters.
 // If an error occurs, it is returned along with an empty string.
-func generateUserDefinedFunctionsSection(template *types.Template, showAllDecorators bool) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
+func generateUserDefinedFunctionsSection(template *types.Template, selection decoratorSelection, r Renderer, headings *headingRegistry) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
 	if len(template.UserDefinedFunctions) == 0 {
 		return "", nil
 	}
 
-	// Base headers
-	headers := []string{"Name", "Description", "Output Type"}
-
-	// Add Exportable column if flag is enabled
-	if showAllDecorators {
-		headers = []string{"Name", "Description", "Exportable", "Output Type"}
+	// Base headers, with the Exportable column if it is selected
+	columns := selection.columns(types.ExportableDecorator)
+	headers := append([]string{"Name", "Signature", "Description"}, decoratorHeaders(columns)...)
+	headers = append(headers, "Parameters")
+
+	// The sub-tables of the parameters follow the table; their headings are added first, so that the table links to them
+	title := "User Defined Functions (UDFs)"
+	anchor := headings.add(H2, title)
+	anchors := make([]string, len(template.UserDefinedFunctions))
+	for i := range template.UserDefinedFunctions {
+		if function := &template.UserDefinedFunctions[i]; len(function.Parameters) > 0 {
+			anchors[i] = headings.add(H3, function.Name)
+		}
 	}
 
 	rows := make([][]string, len(template.UserDefinedFunctions))
 
-	for i, function := range template.UserDefinedFunctions {
+	for i := range template.UserDefinedFunctions {
+		function := &template.UserDefinedFunctions[i]
+
 		// Base row
 		row := []string{
-			function.Name,
-			extractDescription(function.Metadata),
+			r.Anchor(r.Text(function.Name), FunctionAnchor(function.Name)),
+			r.Code(functionSignature(function)),
+			extractDescription(function.Metadata, r),
 		}
 
-		// Add Exportable column if flag is enabled
-		if showAllDecorators {
-			exportableVal := ""
-			if function.IsExportable() {
-				exportableVal = flagYes
+		row = append(row, decoratorCells(columns, &decorators{exportable: function.IsExportable()}, r)...)
+
+		// Add Parameters link
+		parametersColumn := ""
+		if len(function.Parameters) > 0 {
+			parametersColumn = r.Reference("View Parameters", anchors[i])
+		}
+		rows[i] = append(row, parametersColumn)
+	}
+
+	headers, rows = dropEmptyColumns(headers, rows, 3, len(columns))
+	table := r.Heading(H2, title, anchor) + headings.tableBody(headers, rows)
+
+	// Sub-tables for parameters
+	parameterHeaders := []string{"Name", "Type", "Description"}
+	for i := range template.UserDefinedFunctions {
+		function := &template.UserDefinedFunctions[i]
+		if len(function.Parameters) == 0 {
+			continue
+		}
+		parameterRows := make([][]string, len(function.Parameters))
+		for j := range function.Parameters {
+			parameter := &function.Parameters[j]
+			parameterType := extractType(parameter.Type, parameter.Items)
+			if parameter.Nullable {
+				parameterType += " (nullable)"
 			}
-			row = append(row, exportableVal)
+			parameterRows[j] = []string{r.Text(parameter.Name), r.Text(parameterType), extractDescription(parameter.Metadata, r)}
 		}
+		table += "\n" + r.Heading(H3, function.Name, anchors[i]) + headings.tableBody(parameterHeaders, parameterRows)
+	}
 
-		// Add Output Type
-		row = append(row, extractType(function.Output.Type, function.Output.Items))
-		rows[i] = row
+	return table, nil
+}
+
+// functionSignature returns the signature of a user-defined function as declared in Bicep,
+// e.g. "buildName(prefix string, index int) string".
+// User-defined data types are referenced by name, and nullable parameters are marked with '?'.
+func functionSignature(function *types.UserDefinedFunction) string {
+	parameters := make([]string, len(function.Parameters))
+	for i := range function.Parameters {
+		parameter := &function.Parameters[i]
+		parameterType := signatureType(parameter.Type, parameter.Items)
+		if parameter.Nullable {
+			parameterType += "?"
+		}
+		parameters[i] = parameter.Name + " " + parameterType
 	}
+	return fmt.Sprintf("%s(%s) %s", function.Name, strings.Join(parameters, ", "), signatureType(function.Output.Type, function.Output.Items))
+}
 
-	return NewMarkdownTable("User Defined Functions (UDFs)", H2, headers, rows).String(), nil
+// signatureType returns a type as written in Bicep, e.g. "string[]" or the name of a user-defined data type.
+func signatureType(t string, items *types.Items) string {
+	return strings.TrimSuffix(extractType(t, items), " (uddt)")
 }
 
 // generateVariablesSection generates the variables section of the markdown document based on the provided template.
 // If the template has no variables, it returns an empty string.
 // Otherwise, it creates a markdown table with the variable names and descriptions.
-// An "Exportable" column is added if the showAllDecorators flag is enabled.
-func generateVariablesSection(template *types.Template, showAllDecorators bool) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
+// An "Exportable" column is added if the exportable decorators are selected and a variable is exportable,
+// and a "Value" column if the showValues flag is enabled (see formatVariableValue).
+func generateVariablesSection(template *types.Template, selection decoratorSelection, showValues bool, r Renderer, headings *headingRegistry) (string, error) {
 	if len(template.Variables) == 0 {
 		return "", nil
 	}
 
-	headers := []string{"Name", "Description"}
-	if showAllDecorators {
-		headers = []string{"Name", "Description", "Exportable"}
+	columns := selection.columns(types.ExportableDecorator)
+	headers := append([]string{"Name", "Description"}, decoratorHeaders(columns)...)
+	if showValues {
+		headers = append(headers, "Value")
 	}
 
 	rows := make([][]string, len(template.Variables))
-	for i, variable := range template.Variables {
-		row := []string{variable.Name, variable.Description}
-		if showAllDecorators {
-			exportableVal := ""
-			if variable.Exportable {
-				exportableVal = flagYes
+	for i := range template.Variables {
+		variable := &template.Variables[i]
+		row := []string{r.Anchor(r.Text(variable.Name), VariableAnchor(variable.Name)), r.Text(variable.Description)}
+		row = append(row, decoratorCells(columns, &decorators{exportable: variable.Exportable}, r)...)
+		if showValues {
+			value, err := formatVariableValue(variable, r)
+			if err != nil {
+				return "", fmt.Errorf("failed to format the value of %s: %w", variable.Name, err)
 			}
-			row = append(row, exportableVal)
+			row = append(row, value)
 		}
 		rows[i] = row
 	}
-	return NewMarkdownTable("Variables", H2, headers, rows).String(), nil
+	headers, rows = dropEmptyColumns(headers, rows, 2, len(columns))
+	return headings.table("Variables", H2, headers, rows), nil
+}
+
+// maxInlineValueLength is the maximum length of a value shown inline in a table cell;
+// longer values are truncated with an expander.
+const maxInlineValueLength = 60
+
+// formatVariableValue formats the value of a variable for a table cell, in Bicep syntax:
+// literal values are pretty-printed, ARM template expressions are converted back to Bicep,
+// and copy loops are shown as for-expressions. A value with an expression that has no Bicep equivalent
+// is shown as it appears in the ARM template. See formatCode for how the value is shown.
+func formatVariableValue(variable *types.Variable, r Renderer) (string, error) {
+	var value string
+	var err error
+	if variable.LoopCount != nil {
+		value, err = bicep.FormatLoop(variable.Name, variable.LoopCount, variable.Value, "")
+	} else {
+		value, err = bicep.FormatValue(variable.Value, "")
+	}
+	if errors.Is(err, bicep.ErrUnsupportedExpression) {
+		value, err = armValue(variable.Value)
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatCode(value, r), nil
+}
+
+// formatBicepValue formats a value decoded from an ARM template (e.g. the example of a parameter)
+// for a table cell, in Bicep syntax; a value with an expression that has no Bicep equivalent
+// is shown as it appears in the ARM template. See formatCode for how the value is shown.
+func formatBicepValue(value any, r Renderer) (string, error) {
+	formatted, err := bicep.FormatValue(value, "")
+	if errors.Is(err, bicep.ErrUnsupportedExpression) {
+		formatted, err = armValue(value)
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatCode(formatted, r), nil
+}
+
+// formatCode formats code for a table cell: short single-line code is shown as inline code,
+// and multiline and long code is collapsed behind its truncated first line (see Renderer.Expandable).
+func formatCode(value string, r Renderer) string {
+	if !strings.Contains(value, "\n") && len(value) <= maxInlineValueLength {
+		return r.Code(value)
+	}
+
+	summary, _, truncated := strings.Cut(value, "\n")
+	if runes := []rune(summary); len(runes) > maxInlineValueLength {
+		summary = string(runes[:maxInlineValueLength])
+		truncated = true
+	}
+	if truncated {
+		summary += " …"
+	}
+	return r.Expandable(summary, value)
+}
+
+// armValue returns a value as it appears in the ARM template: strings (e.g. expressions) as they are,
+// and other values as indented JSON.
+func armValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	jsonValue, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonValue), nil
+}
+
+// generateDiagnosticsSection converts the compiler diagnostics of a template (e.g. linter warnings) into a markdown table.
+// If the template has no diagnostics, it returns an empty string.
+// The table headers are "Severity", "Code", "Location", and "Message".
+// The code links to its documentation when the compiler provided a link.
+func generateDiagnosticsSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) { //nolint:unparam // Ignore the error return value; it is there for consistency.
+	if len(template.Diagnostics) == 0 {
+		return "", nil
+	}
+
+	headers := []string{"Severity", "Code", "Location", "Message"}
+	rows := make([][]string, len(template.Diagnostics))
+	for i := range template.Diagnostics {
+		diagnostic := &template.Diagnostics[i]
+		code := r.Text(diagnostic.Code)
+		if diagnostic.Link != "" {
+			code = r.Link(diagnostic.Code, diagnostic.Link)
+		}
+		location := fmt.Sprintf("%s(%d,%d)", filepath.Base(diagnostic.File), diagnostic.Line, diagnostic.Column)
+		rows[i] = []string{diagnostic.Severity.String(), code, r.Text(location), r.Text(diagnostic.Message)}
+	}
+	return headings.table("Diagnostics", H2, headers, rows), nil
 }
 
 // generateUsageSection generates the usage section for the Bicep module.
 // It takes a pointer to a types.Template object as input and returns a string containing the generated usage section.
-// The usage section includes a basic example of how to use the Bicep module, including both required and optional parameters.
-// The default values for the optional parameters are also included in the example.
-// Note: The default values may contain function calls or references enclosed in square brackets.
-// The function returns an error if there is any issue with marshaling the default values.
-func generateUsageSection(template *types.Template) (string, error) {
+// The usage section includes a basic example of how to use the Bicep module, including both required and optional parameters
+// (see bicep.FormatModuleUsage).
+// The module is referenced by its registry reference if the template has one, with its local path as an alternative.
+// The function returns an error if a default value cannot be formatted.
+func generateUsageSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
+	usage, err := bicep.FormatModuleUsage(template)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(r.Paragraph("Here is a basic example of how to use this Bicep module:") + "\n")
+	builder.WriteString(r.CodeBlock("bicep", usage))
+
+	if template.ModuleReference != "" && template.ModulePath != "" {
+		fmt.Fprintf(&builder, "\nAlternatively, reference the module by its local path: %s.\n", r.Code(bicep.FormatString(template.ModulePath)))
+	}
+
+	return headings.section("Usage", builder.String()), nil
+}
+
+// generateFrontMatter generates the YAML front matter read by static site generators (e.g. MkDocs, Docusaurus):
+// the title of the module (see types.Template.ModuleName), and the description and tags of the template metadata, if any.
+// The values are double-quoted YAML strings.
+func generateFrontMatter(template *types.Template) string {
 	var builder strings.Builder
+	builder.WriteString("---\n")
+	fmt.Fprintf(&builder, "title: %s\n", strconv.Quote(template.ModuleName()))
+	if template.Metadata != nil && template.Metadata.Description != nil && *template.Metadata.Description != "" {
+		fmt.Fprintf(&builder, "description: %s\n", strconv.Quote(*template.Metadata.Description))
+	}
+	if tags := template.Metadata.Tags(); len(tags) > 0 {
+		builder.WriteString("tags:\n")
+		for _, tag := range tags {
+			fmt.Fprintf(&builder, "  - %s\n", strconv.Quote(tag))
+		}
+	}
+	builder.WriteString("---\n\n")
+	return builder.String()
+}
 
-	builder.WriteString("## Usage\n\n")
-	builder.WriteString("Here is a basic example of how to use this Bicep module:\n\n")
-	builder.WriteString("```bicep\n")
-	builder.WriteString("module reference_name 'path_to_module | container_registry_reference' = {\n")
-	builder.WriteString("  name: 'deployment_name'\n")
-	builder.WriteString("  params: {\n")
+// generateDescriptionSection generates the description section of a template.
+// It takes a pointer to a types.Template as input and returns the generated description section as a string.
+// If the template has a non-empty description in its metadata, it will be included in the generated section.
+// Otherwise, an empty string will be returned.
+func generateDescriptionSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
+	if template.Metadata == nil || template.Metadata.Description == nil || *template.Metadata.Description == "" {
+		return "", nil
+	}
+	return headings.section("Description", r.Paragraph(*template.Metadata.Description)), nil
+}
+
+// generateMetadataSection generates the metadata section of a template: a "Key" and "Value" table
+// of its custom metadata entries (e.g. metadata owner = '...'), in declaration order.
+// If the template has no custom metadata entries, it returns an empty string.
+func generateMetadataSection(template *types.Template, r Renderer, headings *headingRegistry) (string, error) {
+	if template.Metadata == nil || len(template.Metadata.Custom) == 0 {
+		return "", nil
+	}
+
+	headers := []string{"Key", "Value"}
+	rows := make([][]string, len(template.Metadata.Custom))
+	for i, item := range template.Metadata.Custom {
+		value, err := formatMetadataValue(item.Value, r)
+		if err != nil {
+			return "", fmt.Errorf("failed to format metadata %s: %w", item.Key, err)
+		}
+		rows[i] = []string{r.Text(item.Key), value}
+	}
+
+	return headings.table("Metadata", H2, headers, rows), nil
+}
+
+// generateMetadataHeader generates the fields shown below the title: a badge for every custom metadata entry
+// of the badge keys, and a "**key:** value" line for every custom metadata entry of the header keys.
+// The keys that are not in the template metadata are ignored.
+func generateMetadataHeader(template *types.Template, badgeKeys, headerKeys []string, r Renderer) (string, error) {
+	var builder strings.Builder
 
-	// Required parameters (without a default value).
-	builder.WriteString("    // Required parameters\n")
-	for _, parameter := range template.Parameters {
-		if parameter.IsRequired() {
-			builder.WriteString(fmt.Sprintf("    %s:\n", parameter.Name))
+	var badges []Badge
+	for _, key := range badgeKeys {
+		value, ok := template.Metadata.GetCustom(key)
+		if !ok {
+			continue
+		}
+		message, err := metadataString(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to format metadata %s: %w", key, err)
 		}
+		badges = append(badges, metadataBadge(key, message))
+	}
+	if len(badges) > 0 {
+		builder.WriteString(r.Badges(badges))
 	}
 
-	// Optional parameters (with a default value).
-	builder.WriteString("\n    // Optional parameters\n")
-	for _, parameter := range template.Parameters {
-		if parameter.IsRequired() {
+	for _, key := range headerKeys {
+		value, ok := template.Metadata.GetCustom(key)
+		if !ok {
 			continue
 		}
-		jsonValue, err := json.MarshalIndent(parameter.DefaultValue, "    ", "  ")
+		formatted, err := formatMetadataValue(value, r)
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal default value: %w", err)
+			return "", fmt.Errorf("failed to format metadata %s: %w", key, err)
 		}
-		defaultValue := string(jsonValue)
+		fmt.Fprintf(&builder, "%s %s\n\n", r.Strong(r.Text(key+":")), formatted)
+	}
 
-		// Remove quotes from keys.
-		re := regexp.MustCompile(`"(\w+)":`)
-		defaultValue = re.ReplaceAllString(defaultValue, "$1:")
+	return builder.String(), nil
+}
 
-		// Replace double quotes with single quotes.
-		defaultValue = strings.ReplaceAll(defaultValue, "\"", "'")
+// metadataBadge returns a shields.io badge image of a metadata entry.
+// A URL value is shown as "link" in the badge, which links to it.
+func metadataBadge(key, value string) Badge {
+	message := value
+	isURL := strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+	if isURL {
+		message = "link"
+	}
 
-		// Remove commas.
-		defaultValue = strings.ReplaceAll(defaultValue, ",", "")
+	// shields.io uses '-' and '_' as separators; they are escaped by doubling them
+	escape := strings.NewReplacer("-", "--", "_", "__")
+	badge := Badge{
+		Label: key,
+		Image: fmt.Sprintf("https://img.shields.io/badge/%s-%s-blue", url.PathEscape(escape.Replace(key)), url.PathEscape(escape.Replace(message))),
+	}
+	if isURL {
+		badge.Link = value
+	}
+	return badge
+}
 
-		builder.WriteString(fmt.Sprintf("    %s: %s\n", parameter.Name, defaultValue))
+// formatMetadataValue formats the value of a metadata entry as text for a table cell (see metadataString).
+func formatMetadataValue(value any, r Renderer) (string, error) {
+	formatted, err := metadataString(value)
+	if err != nil {
+		return "", err
 	}
-	builder.WriteString("  }\n")
-	builder.WriteString("}\n")
-	builder.WriteString("```\n")
-	builder.WriteString("\n> Note: In the default values, strings enclosed in square brackets (e.g. '[resourceGroup().location]' or '[__bicep.function_name(args...)']) represent function calls or references.\n") //nolint:lll // Ignore long line length.
+	return r.Text(formatted), nil
+}
 
-	return builder.String(), nil
+// metadataString returns the value of a metadata entry as a string: strings as they are, and other values as JSON.
+func metadataString(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonValue), nil
 }
 
-// generateDescriptionSection generates the description section of a template.
-// It takes a pointer to a types.Template as input and returns the generated description section as a string.
-// If the template has a non-empty description in its metadata, it will be included in the generated section.
-// Otherwise, an empty string will be returned.
-func generateDescriptionSection(template *types.Template) (string, error) {
-	var builder strings.Builder
-	if template.Metadata != nil && template.Metadata.Description != nil && *template.Metadata.Description != "" {
-		builder.WriteString(fmt.Sprintf("## Description\n\n%s\n", *template.Metadata.Description))
-	} else {
-		builder.WriteString("")
+// generateFooter generates the footer of the documentation, which records the compiler that built the template
+// (see types.Template.Compiler).
+func generateFooter(template *types.Template, r Renderer) string {
+	text := "Generated by bicep-docs."
+	if compiler := template.Compiler(); compiler != "" {
+		text = fmt.Sprintf("Generated by bicep-docs with %s.", compiler)
 	}
-	return builder.String(), nil
+	return fmt.Sprintf("%s\n\n%s\n", r.Rule(), r.Emphasis(r.Text(text)))
 }
 
 // checkFileExists checks if a file exists and is not a directory.
@@ -698,9 +1041,8 @@ func extractType(t string, items *types.Items) string {
 	}
 
 	// Handle UDDTs
-	if strings.HasPrefix(t, "#/definitions/") {
-		split := strings.Split(t, "/")
-		return split[len(split)-1] + " (uddt)"
+	if name, ok := types.DefinitionName(t); ok {
+		return name + " (uddt)"
 	}
 
 	// Handle arrays with item type info
@@ -712,25 +1054,30 @@ func extractType(t string, items *types.Items) string {
 
 		// Handle items with ref (UDDT)
 		if items.Ref != nil {
-			ref := *items.Ref
-			if strings.HasPrefix(ref, "#/definitions/") {
-				split := strings.Split(ref, "/")
-				return split[len(split)-1] + "[] (uddt)"
+			if name, ok := types.DefinitionName(*items.Ref); ok {
+				return name + "[] (uddt)"
 			}
-			return ref + "[]"
+			return *items.Ref + "[]"
 		}
 	}
 
 	return t
 }
 
-// extractDescription extracts the description from the given metadata and returns it.
+// extractDescription extracts the description from the given metadata and returns it as text for a table cell.
 // If the metadata or the description is nil, an empty string is returned.
-func extractDescription(metadata *types.Metadata) string {
-	description := ""
-	if metadata != nil && metadata.Description != nil {
-		description = strings.ReplaceAll(*metadata.Description, "\r\n", "<br>")
-		description = strings.ReplaceAll(description, "\n", "<br>")
+func extractDescription(metadata *types.Metadata, r Renderer) string {
+	if metadata == nil || metadata.Description == nil {
+		return ""
+	}
+	return r.Text(*metadata.Description)
+}
+
+// textLines returns each line as text, for Renderer.Lines.
+func textLines(lines []string, r Renderer) []string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = r.Text(line)
 	}
-	return description
+	return texts
 }
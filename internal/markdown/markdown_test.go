@@ -0,0 +1,108 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_generateDiagnosticsSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *types.Template
+		expected string
+	}{
+		{
+			name:     "no_diagnostics",
+			template: &types.Template{},
+			expected: "",
+		},
+		{
+			name: "warnings",
+			template: &types.Template{
+				Diagnostics: []types.Diagnostic{
+					{
+						File:     "/src/main.bicep",
+						Line:     1,
+						Column:   7,
+						Severity: types.WarningSeverity,
+						Code:     "no-unused-params",
+						Message:  "Parameter \"location\" is declared but never used.",
+						Link:     "https://aka.ms/bicep/linter/no-unused-params",
+					},
+					{
+						File:     "/src/main.bicep",
+						Line:     5,
+						Column:   3,
+						Severity: types.WarningSeverity,
+						Code:     "BCP081",
+						Message:  "Resource type \"a|b\" does not have types available.",
+					},
+				},
+			},
+			expected: "## Diagnostics\n\n" +
+				"| Severity | Code | Location | Message |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| Warning | [no-unused-params](https://aka.ms/bicep/linter/no-unused-params) | main.bicep(1,7) | Parameter \"location\" is declared but never used. |\n" +
+				"| Warning | BCP081 | main.bicep(5,3) | Resource type \"a\\|b\" does not have types available. |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := generateDiagnosticsSection(tt.template, GitHubFlavor, newHeadingRegistry(GitHubFlavor))
+			if err != nil {
+				t.Fatalf("generateDiagnosticsSection() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("generateDiagnosticsSection() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_generateProvidersSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *types.Template
+		expected string
+	}{
+		{
+			name:     "no_resources",
+			template: &types.Template{},
+			expected: "",
+		},
+		{
+			name: "resources_and_modules",
+			template: &types.Template{
+				Modules: []types.Module{
+					{SymbolicName: "network", Source: "./modules/network/main.bicep"},
+				},
+				Resources: []types.Resource{
+					{SymbolicName: "storage", Type: "Microsoft.Storage/storageAccounts"},
+					{SymbolicName: "container", Type: "Microsoft.Storage/storageAccounts/blobServices/containers"},
+					{SymbolicName: "vault", Type: "Microsoft.KeyVault/vaults", Existing: true},
+					{SymbolicName: "other_storage", Type: "Microsoft.Storage/storageAccounts"},
+					{SymbolicName: "child", Type: "subnets"},
+				},
+			},
+			expected: "## Resource Providers\n\n" +
+				"| Provider | Resource Types | Actions |\n" +
+				"| --- | --- | --- |\n" +
+				"| Microsoft.KeyVault | Microsoft.KeyVault/vaults | Microsoft.KeyVault/vaults/read |\n" +
+				"| Microsoft.Resources | Microsoft.Resources/deployments | Microsoft.Resources/deployments/write |\n" +
+				"| Microsoft.Storage | Microsoft.Storage/storageAccounts<br>Microsoft.Storage/storageAccounts/blobServices/containers | " +
+				"Microsoft.Storage/storageAccounts/blobServices/containers/write<br>Microsoft.Storage/storageAccounts/write |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := generateProvidersSection(tt.template, GitHubFlavor, newHeadingRegistry(GitHubFlavor))
+			if err != nil {
+				t.Fatalf("generateProvidersSection() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("generateProvidersSection() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// exampleKey is the key of the custom metadata entry with an example value of a declaration
+// (e.g. @metadata({ example: 'westeurope' })), shown in the "Example" column of its table.
+const exampleKey = "example"
+
+// metadataHeaders returns the headers of the metadata columns of a table: "Example", followed by the keys
+// of the custom metadata entries shown as columns.
+func metadataHeaders(keys []string) []string {
+	return append([]string{"Example"}, keys...)
+}
+
+// metadataCells returns the cells of the metadata columns of a declaration (see metadataHeaders):
+// its example value in Bicep syntax, followed by the values of the custom metadata entries of the keys.
+func metadataCells(metadata *types.Metadata, keys []string, r Renderer) ([]string, error) {
+	cells := make([]string, 1, len(keys)+1)
+	if value, ok := metadata.GetCustom(exampleKey); ok {
+		example, err := formatBicepValue(value, r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format the example: %w", err)
+		}
+		cells[0] = example
+	}
+	for _, key := range keys {
+		value, ok := metadata.GetCustom(key)
+		if !ok {
+			cells = append(cells, "")
+			continue
+		}
+		formatted, err := formatMetadataValue(value, r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format metadata %s: %w", key, err)
+		}
+		cells = append(cells, formatted)
+	}
+	return cells, nil
+}
+
+// formatName returns the name of a declaration as text, struck through if it is deprecated (see types.Metadata.Deprecated).
+func formatName(name string, metadata *types.Metadata, r Renderer) string {
+	if _, deprecated := metadata.Deprecated(); deprecated {
+		return r.Deleted(r.Text(name))
+	}
+	return r.Text(name)
+}
+
+// formatDescription returns the description of a declaration as text for a table cell (see extractDescription).
+// If the declaration is deprecated, the description is preceded by a notice with the reason, if any,
+// e.g. "**Deprecated:** Use sku instead.".
+func formatDescription(metadata *types.Metadata, r Renderer) string {
+	reason, deprecated := metadata.Deprecated()
+	if !deprecated {
+		return extractDescription(metadata, r)
+	}
+
+	notice := r.Strong("Deprecated")
+	if reason != "" {
+		notice = r.Strong("Deprecated:") + " " + r.Text(reason)
+	}
+	if metadata.Description == nil || *metadata.Description == "" {
+		return notice
+	}
+	lines := strings.Split(strings.ReplaceAll(*metadata.Description, "\r\n", "\n"), "\n")
+	return r.Lines(append([]string{notice}, textLines(lines, r)...))
+}
@@ -0,0 +1,67 @@
+package markdown
+
+// Renderer renders the elements of the documentation in a markup language: the Markdown of a Flavor,
+// AsciiDoc, ReStructuredText, or the HTML of the pages of a site (see package site). The sections are built
+// from these elements only, so that every section is available in each markup language.
+//
+// The inline elements (e.g. Text, Code, Link) return markup that is valid within a table cell.
+type Renderer interface {
+	// FileExtension returns the extension of the documentation files, e.g. ".md".
+	FileExtension() string
+
+	// Slug returns the anchor of a heading, before de-duplication (see headingRegistry).
+	Slug(title string) string
+	// Heading returns a heading of a level with its anchor, followed by a blank line.
+	// The level-1 heading is the title of the document.
+	Heading(level HeaderType, title, anchor string) string
+	// Table returns a table of rendered cells, with a header row.
+	Table(headers []string, rows [][]string) string
+	// CodeBlock returns a block of code in a language (e.g. "bicep"); the code ends with a line break.
+	CodeBlock(language, code string) string
+	// ListItem returns an item of a bulleted list, nested at a depth (0 for the top level).
+	ListItem(depth int, text string) string
+	// Badges returns a paragraph of badge images.
+	Badges(badges []Badge) string
+	// Rule returns a thematic break.
+	Rule() string
+	// Paragraph returns a paragraph of prose (e.g. the description of a template), followed by a line break.
+	Paragraph(text string) string
+
+	// Text returns plain text, escaped, with its line breaks preserved.
+	Text(text string) string
+	// Lines returns rendered inline elements on separate lines.
+	Lines(lines []string) string
+	// Code returns inline code.
+	Code(code string) string
+	// Expandable returns a long or multiline code value that is collapsed behind its summary, if supported.
+	Expandable(summary, code string) string
+	// Strong returns strongly emphasized text.
+	Strong(text string) string
+	// Emphasis returns emphasized text.
+	Emphasis(text string) string
+	// Deleted returns struck-through text (e.g. a deprecated name), or the text itself if it is not supported.
+	Deleted(text string) string
+	// Link returns a link to a URL.
+	Link(text, url string) string
+	// DocumentLink returns a link to another documentation file by its relative path (e.g. "../vnet/README.md").
+	DocumentLink(text, target string) string
+	// Reference returns a cross-reference to the anchor of a heading of the same document.
+	Reference(text, anchor string) string
+	// Anchor returns the rendered name of a declaration as the target of an anchor (see ParameterAnchor),
+	// or the name itself if the markup language has no anchors within tables.
+	Anchor(text, anchor string) string
+}
+
+// Anchors of the declarations of a document, e.g. "param-location" (see Renderer.Anchor).
+func ParameterAnchor(name string) string { return "param-" + name }
+func TypeAnchor(name string) string      { return "type-" + name }
+func FunctionAnchor(name string) string  { return "func-" + name }
+func VariableAnchor(name string) string  { return "var-" + name }
+func OutputAnchor(name string) string    { return "output-" + name }
+
+// Badge is a badge image (e.g. of shields.io), with an optional link.
+type Badge struct {
+	Label string
+	Image string
+	Link  string
+}
@@ -0,0 +1,231 @@
+package markdown
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReStructuredText is the Renderer of reStructuredText documents, e.g. the pages of a Sphinx site.
+//
+// The headings are preceded by explicit targets, which the cross-references link to,
+// and the tables are list tables, whose cells can hold any body element.
+// The explicit targets are labels shared by every document of a Sphinx project, so they are prefixed with the
+// slug of the name of the document (e.g. "network-vnet-readme-parameters" for the document "network/vnet/README").
+type ReStructuredText struct {
+	Document string
+}
+
+// rstIndent is the indentation of the content of a list table cell.
+const rstIndent = "       "
+
+// FileExtension returns the extension of reStructuredText files.
+func (ReStructuredText) FileExtension() string {
+	return ".rst"
+}
+
+// Slug returns the identifier of a heading as normalized by Docutils:
+// lowercased, with runs of characters other than letters and digits replaced by a hyphen.
+func (ReStructuredText) Slug(title string) string {
+	var builder strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && builder.Len() > 0 {
+				builder.WriteRune('-')
+			}
+			hyphen = false
+			builder.WriteRune(r)
+		} else {
+			hyphen = true
+		}
+	}
+	return builder.String()
+}
+
+// Heading returns a section title preceded by an explicit target; the level-1 heading is the document title,
+// which is overlined. The titles of the levels are underlined with "=" and "-" respectively.
+func (rst ReStructuredText) Heading(level HeaderType, title, anchor string) string {
+	title = rst.escape(title)
+	width := utf8.RuneCountInString(title)
+	switch level {
+	case H1:
+		line := strings.Repeat("=", width)
+		return fmt.Sprintf("%s\n%s\n%s\n\n", line, title, line)
+	case H3:
+		return fmt.Sprintf(".. _%s:\n\n%s\n%s\n\n", rst.label(anchor), title, strings.Repeat("-", width))
+	default:
+		return fmt.Sprintf(".. _%s:\n\n%s\n%s\n\n", rst.label(anchor), title, strings.Repeat("=", width))
+	}
+}
+
+// Table returns a list table with a header row.
+func (ReStructuredText) Table(headers []string, rows [][]string) string {
+	var builder strings.Builder
+	builder.WriteString(".. list-table::\n   :header-rows: 1\n\n")
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			marker := "     -"
+			if i == 0 {
+				marker = "   * -"
+			}
+			if cell == "" {
+				builder.WriteString(marker + "\n")
+				continue
+			}
+			fmt.Fprintf(&builder, "%s %s\n", marker, indentLines(cell, rstIndent))
+		}
+	}
+	return builder.String()
+}
+
+// CodeBlock returns a code block directive.
+func (ReStructuredText) CodeBlock(language, code string) string {
+	return fmt.Sprintf(".. code-block:: %s\n\n   %s\n", language, indentLines(strings.TrimRight(code, "\n"), "   "))
+}
+
+// ListItem returns a bullet list item, indented by two spaces per depth. Every item is followed by a blank line,
+// which separates it from the items of a nested list.
+func (ReStructuredText) ListItem(depth int, text string) string {
+	return fmt.Sprintf("%s- %s\n\n", strings.Repeat("  ", depth), text)
+}
+
+// Badges returns an image directive for every badge.
+func (ReStructuredText) Badges(badges []Badge) string {
+	var builder strings.Builder
+	for _, badge := range badges {
+		fmt.Fprintf(&builder, ".. image:: %s\n   :alt: %s\n", badge.Image, badge.Label)
+		if badge.Link != "" {
+			fmt.Fprintf(&builder, "   :target: %s\n", badge.Link)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// Rule returns a transition.
+func (ReStructuredText) Rule() string {
+	return "----"
+}
+
+// Paragraph returns the text as it is, so that the inline markup of a description is rendered.
+func (ReStructuredText) Paragraph(text string) string {
+	return text + "\n"
+}
+
+// Text returns a text with its inline markup escaped; a text of several lines is a line block, which preserves them.
+func (rst ReStructuredText) Text(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = rst.escape(line)
+	}
+	return rst.Lines(lines)
+}
+
+// Lines returns the lines as a line block.
+func (ReStructuredText) Lines(lines []string) string {
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	block := make([]string, len(lines))
+	for i, line := range lines {
+		block[i] = strings.TrimRight("| "+line, " ")
+	}
+	return strings.Join(block, "\n")
+}
+
+// Code returns an inline literal. An inline literal cannot contain its own delimiter, and backslashes
+// do not escape it, so code with backticks is the interpreted text of the code role instead, with its
+// backslashes and backticks escaped.
+func (ReStructuredText) Code(code string) string {
+	if strings.Contains(code, "`") {
+		return ":code:`" + strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(code) + "`"
+	}
+	return "``" + code + "``"
+}
+
+// Expandable returns a literal block, as reStructuredText has no collapsible elements.
+func (ReStructuredText) Expandable(_, code string) string {
+	return "::\n\n    " + indentLines(strings.TrimRight(code, "\n"), "    ")
+}
+
+// Strong returns strongly emphasized text.
+func (ReStructuredText) Strong(text string) string {
+	return "**" + text + "**"
+}
+
+// Emphasis returns emphasized text.
+func (ReStructuredText) Emphasis(text string) string {
+	return "*" + text + "*"
+}
+
+// Deleted returns the text itself, as reStructuredText has no strikethrough.
+func (ReStructuredText) Deleted(text string) string {
+	return text
+}
+
+// Link returns an anonymous hyperlink reference to a URL.
+func (rst ReStructuredText) Link(text, url string) string {
+	return fmt.Sprintf("`%s <%s>`__", rst.escapeReference(text), url)
+}
+
+// DocumentLink returns a Sphinx cross-reference to another document, which is addressed without its extension.
+func (rst ReStructuredText) DocumentLink(text, target string) string {
+	return fmt.Sprintf(":doc:`%s <%s>`", rst.escapeReference(text), strings.TrimSuffix(target, path.Ext(target)))
+}
+
+// Reference returns an anonymous hyperlink reference to the explicit target of a heading.
+func (rst ReStructuredText) Reference(text, anchor string) string {
+	return fmt.Sprintf("`%s <%s_>`__", rst.escapeReference(text), rst.label(anchor))
+}
+
+// label returns the explicit target of a heading: its anchor, prefixed with the slug of the document, if any.
+func (rst ReStructuredText) label(anchor string) string {
+	if rst.Document == "" {
+		return anchor
+	}
+	return rst.Slug(rst.Document) + "-" + anchor
+}
+
+// Anchor returns the text itself, as only the headings are labeled.
+func (ReStructuredText) Anchor(text, _ string) string {
+	return text
+}
+
+// escape escapes the inline markup of a line: the characters that start or end inline markup
+// (emphasis, literals, interpreted text, substitutions, and the underscore ending a reference),
+// and a leading character that would start a list.
+func (ReStructuredText) escape(line string) string {
+	var builder strings.Builder
+	runes := []rune(line)
+	for i, r := range runes {
+		switch {
+		case r == '\\' || r == '*' || r == '`' || r == '|':
+			builder.WriteRune('\\')
+		case r == '_' && (i == len(runes)-1 || !(unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1]))):
+			builder.WriteRune('\\')
+		case i == 0 && (r == '-' || r == '+' || r == '#') && (len(runes) == 1 || runes[1] == ' '):
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// escapeReference escapes the text of a hyperlink reference, whose target is delimited by angle brackets.
+func (ReStructuredText) escapeReference(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`", "<", "\\<").Replace(text)
+}
+
+// indentLines indents every line but the first one, leaving the empty lines empty.
+func indentLines(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
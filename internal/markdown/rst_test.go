@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestReStructuredText_Slug(t *testing.T) {
+	tests := []struct {
+		name     string
+		heading  string
+		expected string
+	}{
+		{name: "words", heading: "User Defined Data Types (UDDTs)", expected: "user-defined-data-types-uddts"},
+		{name: "underscores", heading: "storage_Config", expected: "storage-config"},
+		{name: "punctuation", heading: " -a - b. ", expected: "a-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ReStructuredText{}).Slug(tt.heading); got != tt.expected {
+				t.Errorf("Slug() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReStructuredText_Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "plain", text: "The name of the account.", expected: "The name of the account."},
+		{name: "inline markup", text: "*a* `b` |c| d_ e_f", expected: "\\*a\\* \\`b\\` \\|c\\| d\\_ e_f"},
+		{name: "line block", text: "Tiers:\n- Basic\n\n+ Premium", expected: "| Tiers:\n| \\- Basic\n|\n| \\+ Premium"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ReStructuredText{}).Text(tt.text); got != tt.expected {
+				t.Errorf("Text() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReStructuredText_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{name: "plain", code: "'eastus'", expected: "``'eastus'``"},
+		{name: "double_backticks", code: "a``b", expected: ":code:`a\\`\\`b`"},
+		{name: "backslash_and_backtick", code: "a\\`", expected: ":code:`a\\\\\\``"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ReStructuredText{}).Code(tt.code); got != tt.expected {
+				t.Errorf("Code() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
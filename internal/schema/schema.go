@@ -0,0 +1,234 @@
+/*
+Package schema converts the parameters of a Bicep template into a JSON Schema (draft 2020-12) document,
+so that editors and CI pipelines can validate the parameter values of a module before it is deployed.
+
+The schema describes an object with a property for every parameter (e.g. {"location": "westeurope"}),
+and the user-defined data types of the template are converted into definitions ($defs) that the parameters reference.
+*/
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// Dialect is the JSON Schema dialect of the generated schemas.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// defsPrefix is the prefix of a $ref to a definition in a JSON Schema.
+const defsPrefix = "#/$defs/"
+
+// Schema is a JSON Schema. Type is either the name of a JSON type or, for a nullable value,
+// a list of JSON types that includes "null".
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Comment              string             `json:"$comment,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// FileName returns the name of the schema file of a Bicep file, e.g. "main.schema.json" for "main.bicep".
+func FileName(bicepFile string) string {
+	return strings.TrimSuffix(filepath.Base(bicepFile), filepath.Ext(bicepFile)) + ".schema.json"
+}
+
+// New returns the schema of the parameters of a template, titled by the name of its module
+// (see types.Template.ModuleName) and described by its description. The compiler that built the template,
+// if known (see types.Template.Compiler), is recorded in a comment.
+//
+// The required parameters (without a default value and not nullable) are required properties,
+// and the nullable parameters also accept null. The allowed values of a parameter are an enum
+// (of its items, for an array), and its length and value constraints are converted into the keywords
+// of its JSON type. Every user-defined data type is a definition: a sealed object type does not allow
+// additional properties, and its nullable properties are optional; a union of literals (e.g. type env = 'dev' | 'prod')
+// is an enum. The inline object types of parameters
+// (e.g. param config { tier: string }) are converted in the same way.
+func New(template *types.Template) *Schema {
+	schema := &Schema{
+		Dialect:    Dialect,
+		Title:      template.ModuleName(),
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+	if template.Metadata != nil && template.Metadata.Description != nil {
+		schema.Description = *template.Metadata.Description
+	}
+	if compiler := template.Compiler(); compiler != "" {
+		schema.Comment = fmt.Sprintf("Generated by bicep-docs with %s.", compiler)
+	}
+
+	for i := range template.Parameters {
+		parameter := &template.Parameters[i]
+		schema.Properties[parameter.Name] = convertObject(parameter.Constraints(), parameter.Properties, parameter.Sealed)
+		if parameter.IsRequired() {
+			schema.Required = append(schema.Required, parameter.Name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	if len(template.UserDefinedDataTypes) > 0 {
+		schema.Defs = map[string]*Schema{}
+	}
+	for i := range template.UserDefinedDataTypes {
+		dataType := &template.UserDefinedDataTypes[i]
+		schema.Defs[dataType.Name] = convertDataType(dataType)
+	}
+
+	return schema
+}
+
+// Marshal returns the schema as indented JSON, ending with a line break.
+func (s *Schema) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// convertDataType returns the definition of a user-defined data type: an object with its properties,
+// or the schema of its underlying type.
+func convertDataType(dataType *types.UserDefinedDataType) *Schema {
+	return convertObject(dataType.Constraints(), dataType.Properties, dataType.Sealed)
+}
+
+// convertObject returns the schema of a type and its constraints, with the properties of an object type:
+// its non-nullable properties are required, and a sealed object does not allow additional properties.
+func convertObject(c *types.Constraints, properties []types.UserDefinedDataTypeProperty, sealed bool) *Schema {
+	schema := convert(c)
+	if len(properties) > 0 {
+		schema.Properties = map[string]*Schema{}
+		for i := range properties {
+			property := &properties[i]
+			schema.Properties[property.Name] = convert(property.Constraints())
+			if !property.Nullable {
+				schema.Required = append(schema.Required, property.Name)
+			}
+		}
+		sort.Strings(schema.Required)
+	}
+	if sealed {
+		additionalProperties := false
+		schema.AdditionalProperties = &additionalProperties
+	}
+	return schema
+}
+
+// convert returns the schema of a type and its constraints.
+// The metadata entries "deprecated" and "example" (see types.Metadata.Custom) become the deprecated and examples annotations.
+func convert(c *types.Constraints) *Schema {
+	schema := &Schema{}
+	if c.Metadata != nil && c.Metadata.Description != nil {
+		schema.Description = *c.Metadata.Description
+	}
+	_, schema.Deprecated = c.Metadata.Deprecated()
+	if example, ok := c.Metadata.GetCustom("example"); ok {
+		schema.Examples = []any{example}
+	}
+
+	// A user-defined data type is referenced, and a nullable reference also accepts null
+	if _, ok := types.DefinitionName(c.Type); ok {
+		ref := reference(c.Type)
+		if c.Nullable {
+			schema.AnyOf = []*Schema{{Ref: ref}, {Type: "null"}}
+		} else {
+			schema.Ref = ref
+		}
+		return schema
+	}
+
+	typ := jsonType(c.Type)
+	switch typ {
+	case "string":
+		schema.MinLength, schema.MaxLength = c.MinLength, c.MaxLength
+	case "integer":
+		schema.Minimum, schema.Maximum = c.MinValue, c.MaxValue
+	case "array":
+		schema.MinItems, schema.MaxItems = c.MinLength, c.MaxLength
+		schema.Items = itemsSchema(c.Items)
+	}
+
+	// The allowed values of an array are the allowed values of its items
+	if len(c.AllowedValues) > 0 {
+		if typ == "array" {
+			if schema.Items == nil {
+				schema.Items = &Schema{}
+			}
+			schema.Items.Enum = c.AllowedValues
+		} else {
+			schema.Enum = c.AllowedValues
+			if c.Nullable {
+				schema.Enum = append(append([]any(nil), c.AllowedValues...), nil)
+			}
+		}
+	}
+
+	switch {
+	case typ == "":
+		// 'any' and unknown types accept any value, including null
+	case c.Nullable:
+		schema.Type = []string{typ, "null"}
+	default:
+		schema.Type = typ
+	}
+	return schema
+}
+
+// itemsSchema returns the schema of the items of an array, or nil if they are not typed.
+func itemsSchema(items *types.Items) *Schema {
+	switch {
+	case items == nil:
+		return nil
+	case items.Ref != nil:
+		return &Schema{Ref: reference(*items.Ref)}
+	case items.Type != nil && jsonType(*items.Type) != "":
+		return &Schema{Type: jsonType(*items.Type)}
+	default:
+		return nil
+	}
+}
+
+// reference returns the $ref of the definition of a user-defined data type.
+func reference(ref string) string {
+	return defsPrefix + strings.TrimPrefix(ref, types.DefinitionPrefix)
+}
+
+// jsonType returns the JSON type of an ARM template type, or an empty string for 'any' and unknown types.
+func jsonType(armType string) string {
+	switch strings.ToLower(armType) {
+	case "string", "securestring":
+		return "string"
+	case "int":
+		return "integer"
+	case "bool":
+		return "boolean"
+	case "object", "secureobject":
+		return "object"
+	case "array":
+		return "array"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,287 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestFileName(t *testing.T) {
+	if got := FileName("modules/storage/main.bicep"); got != "main.schema.json" {
+		t.Errorf("FileName() = %q, expected %q", got, "main.schema.json")
+	}
+}
+
+func TestNew(t *testing.T) {
+	template := &types.Template{
+		FileName: "main.bicep",
+		Metadata: &types.Metadata{
+			Name:        strPtr("Storage Account"),
+			Description: strPtr("Deploys a storage account."),
+		},
+		Parameters: []types.Parameter{
+			{
+				Name:      "name",
+				Type:      "string",
+				Metadata:  &types.Metadata{Description: strPtr("The name of the storage account.")},
+				MinLength: intPtr(3),
+				MaxLength: intPtr(24),
+			},
+			{Name: "sku", Type: "string", DefaultValue: "Standard_LRS", AllowedValues: []any{"Standard_LRS", "Premium_LRS"}},
+			{Name: "retention", Type: "int", Nullable: true, MinValue: intPtr(1), MaxValue: intPtr(365)},
+			{Name: "config", Type: "#/definitions/config"},
+			{Name: "rules", Type: "array", DefaultValue: []any{}, Items: &types.Items{Ref: strPtr("#/definitions/rule")}, MaxLength: intPtr(10)},
+			{Name: "zones", Type: "array", DefaultValue: []any{}, AllowedValues: []any{"1", "2", "3"}},
+			{Name: "network", Type: "#/definitions/rule", Nullable: true},
+		},
+		UserDefinedDataTypes: []types.UserDefinedDataType{
+			{
+				Name:   "config",
+				Type:   "object",
+				Sealed: true,
+				Properties: []types.UserDefinedDataTypeProperty{
+					{Name: "tier", Type: "string", AllowedValues: []any{"Hot", "Cool"}, Nullable: true},
+					{Name: "enabled", Type: "bool", Metadata: &types.Metadata{Description: strPtr("Whether it is enabled.")}},
+				},
+			},
+			{Name: "rule", Type: "string", MinLength: intPtr(1)},
+		},
+	}
+
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Storage Account",
+  "description": "Deploys a storage account.",
+  "type": "object",
+  "properties": {
+    "config": {
+      "$ref": "#/$defs/config"
+    },
+    "name": {
+      "description": "The name of the storage account.",
+      "type": "string",
+      "minLength": 3,
+      "maxLength": 24
+    },
+    "network": {
+      "anyOf": [
+        {
+          "$ref": "#/$defs/rule"
+        },
+        {
+          "type": "null"
+        }
+      ]
+    },
+    "retention": {
+      "type": [
+        "integer",
+        "null"
+      ],
+      "minimum": 1,
+      "maximum": 365
+    },
+    "rules": {
+      "type": "array",
+      "maxItems": 10,
+      "items": {
+        "$ref": "#/$defs/rule"
+      }
+    },
+    "sku": {
+      "type": "string",
+      "enum": [
+        "Standard_LRS",
+        "Premium_LRS"
+      ]
+    },
+    "zones": {
+      "type": "array",
+      "items": {
+        "enum": [
+          "1",
+          "2",
+          "3"
+        ]
+      }
+    }
+  },
+  "required": [
+    "config",
+    "name"
+  ],
+  "$defs": {
+    "config": {
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "description": "Whether it is enabled.",
+          "type": "boolean"
+        },
+        "tier": {
+          "type": [
+            "string",
+            "null"
+          ],
+          "enum": [
+            "Hot",
+            "Cool",
+            null
+          ]
+        }
+      },
+      "required": [
+        "enabled"
+      ],
+      "additionalProperties": false
+    },
+    "rule": {
+      "type": "string",
+      "minLength": 1
+    }
+  }
+}
+`
+	got, err := New(template).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	if string(got) != expected {
+		t.Errorf("New() = %s, expected %s", got, expected)
+	}
+}
+
+func TestNew_inlineObject(t *testing.T) {
+	var template types.Template
+	arm := `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "parameters": {
+    "config": {
+      "type": "object",
+      "properties": {
+        "tier": {"type": "string", "allowedValues": ["Hot", "Cool"]},
+        "retention": {"type": "int", "nullable": true}
+      },
+      "additionalProperties": false
+    },
+    "tags": {
+      "type": "object",
+      "defaultValue": {}
+    }
+  }
+}`
+	if err := json.Unmarshal([]byte(arm), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := New(&template)
+	sealed := false
+	expected := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"tier":      {Type: "string", Enum: []any{"Hot", "Cool"}},
+			"retention": {Type: []string{"integer", "null"}},
+		},
+		Required:             []string{"tier"},
+		AdditionalProperties: &sealed,
+	}
+	if got := schema.Properties["config"]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("New() config = %+v, expected %+v", got, expected)
+	}
+	if got := schema.Properties["tags"]; !reflect.DeepEqual(got, &Schema{Type: "object"}) {
+		t.Errorf("New() tags = %+v, expected an object without properties", got)
+	}
+}
+
+func Test_convert(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints types.Constraints
+		expected    *Schema
+	}{
+		{name: "any", constraints: types.Constraints{Type: "any", Nullable: true}, expected: &Schema{}},
+		{name: "secure_string", constraints: types.Constraints{Type: "securestring"}, expected: &Schema{Type: "string"}},
+		{name: "secure_object", constraints: types.Constraints{Type: "secureObject"}, expected: &Schema{Type: "object"}},
+		{
+			name:        "typed_items",
+			constraints: types.Constraints{Type: "array", Items: &types.Items{Type: strPtr("int")}, MinLength: intPtr(1)},
+			expected:    &Schema{Type: "array", MinItems: intPtr(1), Items: &Schema{Type: "integer"}},
+		},
+		{name: "untyped_items", constraints: types.Constraints{Type: "array", Items: &types.Items{Type: strPtr("any")}}, expected: &Schema{Type: "array"}},
+		{
+			name: "deprecated_example",
+			constraints: types.Constraints{Type: "string", Metadata: &types.Metadata{Custom: []types.MetadataItem{
+				{Key: "example", Value: "westeurope"},
+				{Key: "deprecated", Value: "Use region instead."},
+			}}},
+			expected: &Schema{Type: "string", Deprecated: true, Examples: []any{"westeurope"}},
+		},
+		{
+			name: "reference_example",
+			constraints: types.Constraints{Type: "#/definitions/config", Metadata: &types.Metadata{Custom: []types.MetadataItem{
+				{Key: "example", Value: map[string]any{"tier": "Hot"}},
+			}}},
+			expected: &Schema{Ref: "#/$defs/config", Examples: []any{map[string]any{"tier": "Hot"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convert(&tt.constraints); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("convert() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNew_unionDataType(t *testing.T) {
+	var template types.Template
+	arm := `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "definitions": {
+    "env": {
+      "type": "string",
+      "allowedValues": ["dev", "prod"]
+    }
+  },
+  "parameters": {
+    "environment": {
+      "$ref": "#/definitions/env"
+    }
+  }
+}`
+	if err := json.Unmarshal([]byte(arm), &template); err != nil {
+		t.Fatal(err)
+	}
+	template.FileName = "modules/network/main.bicep"
+
+	schema := New(&template)
+	if schema.Title != "network" {
+		t.Errorf("New() title = %q, expected the module name %q", schema.Title, "network")
+	}
+	if got := schema.Properties["environment"]; !reflect.DeepEqual(got, &Schema{Ref: "#/$defs/env"}) {
+		t.Errorf("New() environment = %+v, expected a $ref to the env definition", got)
+	}
+	if got := schema.Defs["env"]; !reflect.DeepEqual(got, &Schema{Type: "string", Enum: []any{"dev", "prod"}}) {
+		t.Errorf("New() env = %+v, expected an enum of the literals", got)
+	}
+}
+
+func TestNew_compiler(t *testing.T) {
+	template := &types.Template{FileName: "main.bicep", CompilerVersion: "Bicep CLI 0.38.3"}
+	if got, expected := New(template).Comment, "Generated by bicep-docs with Bicep CLI 0.38.3."; got != expected {
+		t.Errorf("New() comment = %q, expected %q", got, expected)
+	}
+	if got := New(&types.Template{FileName: "main.bicep"}).Comment; got != "" {
+		t.Errorf("New() comment = %q, expected none for an unknown compiler", got)
+	}
+}
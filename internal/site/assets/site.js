@@ -0,0 +1,82 @@
+// Client-side search over the entries of search-index.js: the modules and their parameters,
+// user-defined data types, user-defined functions, variables, and outputs.
+(function () {
+  "use strict";
+
+  var maxResults = 50;
+  var root = document.body.getAttribute("data-root") || "";
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var entries = window.searchIndex || [];
+  if (!input || !results) {
+    return;
+  }
+
+  function matches(entry, terms) {
+    var text = (entry.name + " " + (entry.description || "")).toLowerCase();
+    return terms.every(function (term) {
+      return text.indexOf(term) !== -1;
+    });
+  }
+
+  function item(entry) {
+    var li = document.createElement("li");
+    var link = document.createElement("a");
+    link.href = root + entry.url;
+    link.textContent = entry.name;
+    li.appendChild(link);
+
+    var kind = document.createElement("span");
+    kind.className = "kind";
+    kind.textContent = entry.kind === "module" ? "module" : entry.kind + " in " + entry.module;
+    li.appendChild(kind);
+
+    if (entry.description) {
+      var description = document.createElement("span");
+      description.className = "description";
+      description.textContent = entry.description;
+      li.appendChild(description);
+    }
+    return li;
+  }
+
+  function search() {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    if (terms.length === 0) {
+      results.hidden = true;
+      return;
+    }
+
+    var found = entries.filter(function (entry) {
+      return matches(entry, terms);
+    });
+    found.slice(0, maxResults).forEach(function (entry) {
+      results.appendChild(item(entry));
+    });
+    if (found.length === 0) {
+      var empty = document.createElement("li");
+      empty.textContent = "No results";
+      results.appendChild(empty);
+    }
+    results.hidden = false;
+  }
+
+  input.addEventListener("input", search);
+  input.addEventListener("keydown", function (event) {
+    if (event.key === "Escape") {
+      input.value = "";
+      search();
+    } else if (event.key === "Enter") {
+      var first = results.querySelector("a");
+      if (first) {
+        window.location.href = first.href;
+      }
+    }
+  });
+  document.addEventListener("click", function (event) {
+    if (!results.contains(event.target) && event.target !== input) {
+      results.hidden = true;
+    }
+  });
+})();
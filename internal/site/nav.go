@@ -0,0 +1,79 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// navNode is a node of the navigation tree of the site: a directory of the repository,
+// which is linked to the page of its module if it has one.
+type navNode struct {
+	name     string
+	dir      string
+	page     bool
+	children []*navNode
+}
+
+// newNavTree returns the navigation tree of the pages, sorted by directory.
+// The module at the root of the repository, if any, is the first node under its title.
+func newNavTree(pages []Page) *navNode {
+	root := &navNode{}
+	for i := range pages {
+		page := &pages[i]
+		if page.Dir == "." {
+			root.children = append(root.children, &navNode{name: page.Template.ModuleName(), dir: page.Dir, page: true})
+			continue
+		}
+		node := root
+		segments := strings.Split(page.Dir, "/")
+		for j, segment := range segments {
+			node = node.child(segment, strings.Join(segments[:j+1], "/"))
+		}
+		node.page = true
+	}
+	return root
+}
+
+// child returns the child node of a directory, adding it if needed.
+func (n *navNode) child(name, dir string) *navNode {
+	for _, child := range n.children {
+		if child.dir == dir {
+			return child
+		}
+	}
+	child := &navNode{name: name, dir: dir}
+	n.children = append(n.children, child)
+	return child
+}
+
+// render returns the navigation tree as nested HTML lists, with the links relative to the root path of a page,
+// and the page of the current directory highlighted.
+func (n *navNode) render(current, root string) template.HTML {
+	var builder strings.Builder
+	n.write(&builder, current, root)
+	return template.HTML(builder.String()) //nolint:gosec // The names and links are escaped.
+}
+
+func (n *navNode) write(builder *strings.Builder, current, root string) {
+	if len(n.children) == 0 {
+		return
+	}
+	builder.WriteString("<ul>")
+	for _, child := range n.children {
+		builder.WriteString("<li>")
+		name := template.HTMLEscapeString(child.name)
+		href := template.HTMLEscapeString(root + pageURL(child.dir))
+		switch {
+		case child.page && child.dir == current:
+			fmt.Fprintf(builder, `<a class="current" href="%s" aria-current="page">%s</a>`, href, name)
+		case child.page:
+			fmt.Fprintf(builder, `<a href="%s">%s</a>`, href, name)
+		default:
+			fmt.Fprintf(builder, `<span>%s</span>`, name)
+		}
+		child.write(builder, current, root)
+		builder.WriteString("</li>")
+	}
+	builder.WriteString("</ul>")
+}
@@ -0,0 +1,150 @@
+package site
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+)
+
+// renderer is the markdown.Renderer of the HTML of a page of the site, so that the pages have the same sections
+// as the generated documentation. dir is the directory of the module of the page (see Page),
+// and known contains the directories of every module of the site, so that local modules are linked to their pages.
+type renderer struct {
+	dir   string
+	known map[string]bool
+}
+
+// FileExtension returns the extension of the pages.
+func (renderer) FileExtension() string {
+	return ".html"
+}
+
+// Slug returns the ID of a heading, as generated by GitHub for Markdown headings.
+func (renderer) Slug(title string) string {
+	return markdown.GitHubFlavor.Slug(title)
+}
+
+// Heading returns a heading with an ID, linked to itself. The level-1 heading is omitted,
+// as the title of a page is part of its layout.
+func (renderer) Heading(level markdown.HeaderType, title, anchor string) string {
+	if level == markdown.H1 {
+		return ""
+	}
+	id := html.EscapeString(anchor)
+	return fmt.Sprintf("<h%d id=\"%s\"><a class=\"anchor\" href=\"#%s\">%s</a></h%d>\n", level, id, id, html.EscapeString(title), level)
+}
+
+// Table returns a table with a header row.
+func (renderer) Table(headers []string, rows [][]string) string {
+	var builder strings.Builder
+	builder.WriteString("<table>\n<thead><tr>")
+	for _, header := range headers {
+		fmt.Fprintf(&builder, "<th>%s</th>", header)
+	}
+	builder.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range rows {
+		builder.WriteString("<tr>")
+		for _, cell := range row {
+			fmt.Fprintf(&builder, "<td>%s</td>", cell)
+		}
+		builder.WriteString("</tr>\n")
+	}
+	builder.WriteString("</tbody>\n</table>\n")
+	return builder.String()
+}
+
+// CodeBlock returns a preformatted block of code.
+func (renderer) CodeBlock(language, code string) string {
+	return fmt.Sprintf("<pre><code class=\"language-%s\">%s</code></pre>\n", language, html.EscapeString(code))
+}
+
+// ListItem returns an item of a list of its own, indented by its depth, e.g. in the table of contents.
+func (renderer) ListItem(depth int, text string) string {
+	return fmt.Sprintf("<ul class=\"depth-%d\"><li>%s</li></ul>\n", depth, text)
+}
+
+// Badges returns a paragraph of the badge images.
+func (renderer) Badges(badges []markdown.Badge) string {
+	images := make([]string, len(badges))
+	for i, badge := range badges {
+		images[i] = fmt.Sprintf("<img src=\"%s\" alt=\"%s\">", html.EscapeString(badge.Image), html.EscapeString(badge.Label))
+		if badge.Link != "" {
+			images[i] = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(badge.Link), images[i])
+		}
+	}
+	return "<p>" + strings.Join(images, " ") + "</p>\n"
+}
+
+// Rule returns a thematic break.
+func (renderer) Rule() string {
+	return "<hr>"
+}
+
+// Paragraph returns a paragraph of escaped text, whose line breaks are preserved by the stylesheet.
+func (renderer) Paragraph(text string) string {
+	return "<p class=\"text\">" + html.EscapeString(text) + "</p>\n"
+}
+
+// Text returns escaped text, with its line breaks preserved.
+func (renderer) Text(text string) string {
+	return strings.ReplaceAll(html.EscapeString(strings.ReplaceAll(text, "\r\n", "\n")), "\n", "<br>")
+}
+
+// Lines returns the lines separated by line breaks.
+func (renderer) Lines(lines []string) string {
+	return strings.Join(lines, "<br>")
+}
+
+// Code returns inline code.
+func (renderer) Code(code string) string {
+	return "<code>" + html.EscapeString(code) + "</code>"
+}
+
+// Expandable returns the code in a disclosure widget, collapsed behind its summary.
+func (renderer) Expandable(summary, code string) string {
+	return fmt.Sprintf("<details><summary><code>%s</code></summary><pre><code>%s</code></pre></details>", html.EscapeString(summary), html.EscapeString(code))
+}
+
+// Strong returns strongly emphasized text.
+func (renderer) Strong(text string) string {
+	return "<strong>" + text + "</strong>"
+}
+
+// Emphasis returns emphasized text.
+func (renderer) Emphasis(text string) string {
+	return "<em>" + text + "</em>"
+}
+
+// Deleted returns struck-through text.
+func (renderer) Deleted(text string) string {
+	return "<del>" + text + "</del>"
+}
+
+// Link returns a link to a URL.
+func (renderer) Link(text, url string) string {
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(url), html.EscapeString(text))
+}
+
+// DocumentLink returns a link to the page of the module documented by a relative path (e.g. "../vnet/README.html"),
+// or the text itself if the module is not part of the site.
+func (r renderer) DocumentLink(text, target string) string {
+	dir := path.Join(r.dir, path.Dir(target))
+	if dir == r.dir || !r.known[dir] {
+		return r.Text(text)
+	}
+	return r.Link(text, rootPath(r.dir)+pageURL(dir))
+}
+
+// Reference returns a link to the anchor of a heading of the page.
+func (r renderer) Reference(text, anchor string) string {
+	return r.Link(text, "#"+anchor)
+}
+
+// Anchor returns the name of a declaration with an ID, linked to itself, e.g. for the results of the search.
+func (renderer) Anchor(text, anchor string) string {
+	id := html.EscapeString(anchor)
+	return fmt.Sprintf("<a class=\"anchor\" id=\"%s\" href=\"#%s\">%s</a>", id, id, text)
+}
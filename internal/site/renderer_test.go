@@ -0,0 +1,58 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+)
+
+func Test_renderer_Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "plain", text: "The name of the account.", expected: "The name of the account."},
+		{name: "html", text: "a < b & 'c'", expected: "a &lt; b &amp; &#39;c&#39;"},
+		{name: "lines", text: "first\r\nsecond", expected: "first<br>second"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (renderer{}).Text(tt.text); got != tt.expected {
+				t.Errorf("Text() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_renderer_DocumentLink(t *testing.T) {
+	r := renderer{dir: "network/vnet", known: map[string]bool{".": true, "network/vnet": true, "shared": true}}
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{name: "module", target: "../../shared/README.html", expected: `<a href="../../shared/index.html">source</a>`},
+		{name: "root", target: "../../README.html", expected: `<a href="../../module.html">source</a>`},
+		{name: "unknown", target: "../subnet/README.html", expected: "source"},
+		{name: "same", target: "README.html", expected: "source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.DocumentLink("source", tt.target); got != tt.expected {
+				t.Errorf("DocumentLink() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_renderer_Heading(t *testing.T) {
+	r := renderer{}
+	if got := r.Heading(markdown.H1, "Network", "network"); got != "" {
+		t.Errorf("Heading() = %q, expected the title to be omitted", got)
+	}
+	expected := "<h3 id=\"a-b\"><a class=\"anchor\" href=\"#a-b\">A &lt;B&gt;</a></h3>\n"
+	if got := r.Heading(markdown.H3, "A <B>", "a-b"); got != expected {
+		t.Errorf("Heading() = %q, expected %q", got, expected)
+	}
+}
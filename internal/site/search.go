@@ -0,0 +1,56 @@
+package site
+
+import (
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// searchEntry is an entry of the client-side search: a module or one of its declarations,
+// with the URL of its anchor relative to the root of the site (see markdown.Renderer.Anchor).
+type searchEntry struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Module      string `json:"module"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// searchEntries returns the search entries of a module: the module itself, and its parameters,
+// user-defined data types, user-defined functions, variables, and outputs.
+func searchEntries(page *Page) []searchEntry {
+	tmpl := page.Template
+	title := tmpl.ModuleName()
+	url := pageURL(page.Dir)
+
+	entries := []searchEntry{{Name: title, Kind: "module", Module: title, Description: moduleDescription(tmpl), URL: url}}
+	add := func(name, kind, id string, metadata *types.Metadata) {
+		entries = append(entries, searchEntry{Name: name, Kind: kind, Module: title, Description: descriptionOf(metadata), URL: url + "#" + id})
+	}
+	for i := range tmpl.Parameters {
+		add(tmpl.Parameters[i].Name, "parameter", markdown.ParameterAnchor(tmpl.Parameters[i].Name), tmpl.Parameters[i].Metadata)
+	}
+	for i := range tmpl.UserDefinedDataTypes {
+		add(tmpl.UserDefinedDataTypes[i].Name, "type", markdown.TypeAnchor(tmpl.UserDefinedDataTypes[i].Name), tmpl.UserDefinedDataTypes[i].Metadata)
+	}
+	for i := range tmpl.UserDefinedFunctions {
+		add(tmpl.UserDefinedFunctions[i].Name, "function", markdown.FunctionAnchor(tmpl.UserDefinedFunctions[i].Name), tmpl.UserDefinedFunctions[i].Metadata)
+	}
+	for i := range tmpl.Variables {
+		variable := &tmpl.Variables[i]
+		entries = append(entries, searchEntry{
+			Name: variable.Name, Kind: "variable", Module: title, Description: variable.Description, URL: url + "#" + markdown.VariableAnchor(variable.Name),
+		})
+	}
+	for i := range tmpl.Outputs {
+		add(tmpl.Outputs[i].Name, "output", markdown.OutputAnchor(tmpl.Outputs[i].Name), tmpl.Outputs[i].Metadata)
+	}
+	return entries
+}
+
+// descriptionOf returns the description of metadata, if any.
+func descriptionOf(metadata *types.Metadata) string {
+	if metadata != nil && metadata.Description != nil {
+		return *metadata.Description
+	}
+	return ""
+}
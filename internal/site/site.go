@@ -0,0 +1,257 @@
+/*
+Package site generates a static HTML documentation site for a repository of Bicep modules:
+a page for every module, a repository index, a navigation tree, and a client-side search
+over the names and descriptions of the modules and their declarations.
+
+The site is self-contained: its stylesheet, script, and search index are written next to its pages,
+and it can be browsed from the file system without a web server.
+*/
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/markdown"
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// pageTemplate is the layout of every page of the site.
+var pageTemplate = template.Must(template.ParseFS(assets, "assets/page.html"))
+
+// Files of the site.
+const (
+	indexFile       = "index.html"      // indexFile is the repository index, and the page of each module in its directory
+	rootModuleFile  = "module.html"     // rootModuleFile is the page of the module at the root of the repository
+	searchIndexFile = "search-index.js" // searchIndexFile defines the entries of the client-side search
+)
+
+// assetFiles are the embedded assets copied to the root of the site.
+var assetFiles = []string{"site.css", "site.js"}
+
+// Page is a documented module of the site: a template and the slash-separated path of the directory
+// of its Bicep file relative to the root of the repository (e.g. "network/vnet", or "." for the root).
+type Page struct {
+	Dir      string
+	Template *types.Template
+}
+
+// Options contains the settings that control the generation of a site.
+//
+// Title is the title of the site, shown on every page; it defaults to "Bicep modules".
+// Sections contains the sections of each module page, in order, as in the generated documentation (see markdown.Options);
+// the pages also have a navigation tree of the modules.
+// Decorators contains the groups of decorators shown in the columns of the tables; a column that is empty in every row is omitted.
+// CompactConstraints controls whether the length and value constraints are merged into a single "Constraints" column.
+// MetadataColumns contains the keys of the custom metadata entries of the parameters, outputs, and user-defined data types
+// (and their properties) shown as columns of their tables, after the "Example" column of their example values;
+// a column that is empty in every row is omitted.
+// GroupByCategory controls whether the parameters are grouped in a sub-table per category,
+// the value of their custom metadata entry "category".
+// VariableValues controls whether the values of the variables are included in the variables table.
+type Options struct {
+	Title              string
+	Sections           []types.Section
+	Decorators         []types.Decorator
+	CompactConstraints bool
+	MetadataColumns    []string
+	GroupByCategory    bool
+	VariableValues     bool
+}
+
+// pageView is the content of a page of the site: its title and its body, rendered by the renderer of the site.
+// Root is the relative path from the page to the root of the site (e.g. "../../"),
+// which prefixes the links to the shared assets and to the other pages.
+type pageView struct {
+	SiteTitle string
+	Title     string
+	Root      string
+	Nav       template.HTML
+	Body      template.HTML
+}
+
+// Create writes the site of the pages to a directory: an index.html page listing every module,
+// an index.html page in the directory of each module (module.html for a module at the root),
+// the search index, and the shared assets. The directory is created if it does not exist,
+// and existing files are overwritten.
+//
+// An error is returned if there are no pages, or if the directory of a page is invalid or documented twice.
+func Create(dir string, pages []Page, opts Options) error {
+	if len(pages) == 0 {
+		return errors.New("no modules to document")
+	}
+	if opts.Title == "" {
+		opts.Title = "Bicep modules"
+	}
+
+	// Sort the pages by directory, so that the navigation tree and the index are in a stable order
+	pages = append([]Page(nil), pages...)
+	known := make(map[string]bool, len(pages))
+	for i := range pages {
+		page := &pages[i]
+		if page.Template == nil {
+			return fmt.Errorf("the module %q has no template", page.Dir)
+		}
+		page.Dir = path.Clean(filepath.ToSlash(page.Dir))
+		if page.Dir == ".." || strings.HasPrefix(page.Dir, "../") || path.IsAbs(page.Dir) {
+			return fmt.Errorf("the directory %q of the module is outside of the repository", page.Dir)
+		}
+		if known[page.Dir] {
+			return fmt.Errorf("the module in %q is documented twice", page.Dir)
+		}
+		known[page.Dir] = true
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Dir < pages[j].Dir })
+
+	nav := newNavTree(pages)
+	var entries []searchEntry
+
+	// Module pages
+	for i := range pages {
+		page := &pages[i]
+		view, err := newModulePage(page, known, opts)
+		if err != nil {
+			return fmt.Errorf("failed to render the module in %q: %w", page.Dir, err)
+		}
+		view.Nav = nav.render(page.Dir, view.Root)
+		if err := writePage(filepath.Join(dir, filepath.FromSlash(pageFile(page.Dir))), view); err != nil {
+			return err
+		}
+		entries = append(entries, searchEntries(page)...)
+	}
+
+	// Repository index
+	index := newIndexPage(pages, opts)
+	index.Nav = nav.render("", "")
+	if err := writePage(filepath.Join(dir, indexFile), index); err != nil {
+		return err
+	}
+
+	// Search index and assets
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to write the search index: %w", err)
+	}
+	searchIndex := append([]byte("window.searchIndex = "), data...)
+	searchIndex = append(searchIndex, ";\n"...)
+	if err := writeFile(filepath.Join(dir, searchIndexFile), searchIndex); err != nil {
+		return err
+	}
+	for _, name := range assetFiles {
+		content, err := fs.ReadFile(assets, "assets/"+name)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newModulePage returns the page of a module, whose body has the sections of its documentation
+// in the order of opts.Sections (see markdown.Render). Sections without content are omitted.
+// known contains the directories of every module of the site, so that local modules are linked to their pages.
+func newModulePage(page *Page, known map[string]bool, opts Options) (*pageView, error) {
+	body, err := markdown.Render(page.Template, markdown.Options{
+		Sections:           opts.Sections,
+		Decorators:         opts.Decorators,
+		CompactConstraints: opts.CompactConstraints,
+		MetadataColumns:    opts.MetadataColumns,
+		GroupByCategory:    opts.GroupByCategory,
+		VariableValues:     opts.VariableValues,
+		Renderer:           renderer{dir: page.Dir, known: known},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pageView{
+		SiteTitle: opts.Title,
+		Title:     page.Template.ModuleName(),
+		Root:      rootPath(page.Dir),
+		Body:      template.HTML(body), //nolint:gosec // The renderer escapes the content of the template.
+	}, nil
+}
+
+// newIndexPage returns the repository index: a table of the modules with their paths and descriptions.
+func newIndexPage(pages []Page, opts Options) *pageView {
+	r := renderer{}
+	rows := make([][]string, len(pages))
+	for i := range pages {
+		page := &pages[i]
+		rows[i] = []string{r.Link(page.Template.ModuleName(), pageURL(page.Dir)), r.Code(page.Dir), r.Text(moduleDescription(page.Template))}
+	}
+	headers := []string{r.Text("Module"), r.Text("Path"), r.Text("Description")}
+	return &pageView{
+		SiteTitle: opts.Title,
+		Title:     opts.Title,
+		Body:      template.HTML(r.Heading(markdown.H2, "Modules", "modules") + r.Table(headers, rows)), //nolint:gosec // The cells are escaped.
+	}
+}
+
+// moduleDescription returns the description of the metadata of a template, if any.
+func moduleDescription(tmpl *types.Template) string {
+	if tmpl.Metadata != nil && tmpl.Metadata.Description != nil {
+		return *tmpl.Metadata.Description
+	}
+	return ""
+}
+
+// pageFile returns the path of the page of a module relative to the root of the site.
+func pageFile(dir string) string {
+	if dir == "." {
+		return rootModuleFile
+	}
+	return dir + "/" + indexFile
+}
+
+// pageURL returns the URL of the page of a module relative to the root of the site.
+func pageURL(dir string) string {
+	segments := strings.Split(pageFile(dir), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// rootPath returns the relative path from the page of a module to the root of the site, e.g. "../../" for "network/vnet".
+func rootPath(dir string) string {
+	if dir == "." {
+		return ""
+	}
+	return strings.Repeat("../", strings.Count(dir, "/")+1)
+}
+
+// writePage renders a page to a file.
+func writePage(file string, view *pageView) error {
+	var buffer bytes.Buffer
+	if err := pageTemplate.Execute(&buffer, view); err != nil {
+		return fmt.Errorf("failed to render %s: %w", file, err)
+	}
+	return writeFile(file, buffer.Bytes())
+}
+
+// writeFile writes a file of the site, creating its directory if needed.
+func writeFile(file string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create the directory of %s: %w", file, err)
+	}
+	if err := os.WriteFile(file, content, 0o644); err != nil { //nolint:gosec // The documentation is not sensitive.
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func TestCreate(t *testing.T) {
+	name := "Network"
+	description := "Deploys the network."
+	parameterDescription := "The address prefix of the network."
+	pages := []Page{
+		{
+			Dir: "network/vnet",
+			Template: &types.Template{
+				FileName: "network/vnet/main.bicep",
+				Metadata: &types.Metadata{Name: &name, Description: &description},
+				Parameters: []types.Parameter{
+					{Name: "addressPrefix", Type: "string", Metadata: &types.Metadata{Description: &parameterDescription}},
+					{Name: "subnets", Type: "array", Items: &types.Items{Ref: stringPtr("#/definitions/subnetType")}, DefaultValue: []any{}},
+				},
+				UserDefinedDataTypes: []types.UserDefinedDataType{
+					{
+						Name:       "subnetType",
+						Type:       "object",
+						Properties: []types.UserDefinedDataTypeProperty{{Name: "name", Type: "string"}},
+					},
+				},
+				Outputs: []types.Output{{Name: "id", Type: "string"}},
+			},
+		},
+		{
+			Dir: ".",
+			Template: &types.Template{
+				FileName: "main.bicep",
+				Imports: []types.Import{{
+					Source:  "./network/vnet/main.bicep",
+					Symbols: []types.ImportedSymbol{{Name: "subnetType", Kind: types.TypeSymbol}},
+				}},
+				Resources: []types.Resource{{SymbolicName: "rg", Type: "Microsoft.Resources/resourceGroups"}},
+			},
+		},
+	}
+	opts := Options{Sections: []types.Section{
+		types.DescriptionSection, types.ImportsSection, types.ProvidersSection, types.ParametersSection, types.UserDefinedDataTypesSection, types.OutputsSection,
+	}}
+
+	dir := t.TempDir()
+	if err := Create(dir, pages, opts); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{
+			file: "index.html",
+			expected: []string{
+				`<title>Bicep modules</title>`,
+				`<a href="module.html">main.bicep</a>`,
+				`<a href="network/vnet/index.html">Network</a>`,
+				`<td>Deploys the network.</td>`,
+			},
+		},
+		{
+			file: "module.html",
+			expected: []string{
+				`<link rel="stylesheet" href="site.css">`,
+				`<a class="current" href="module.html" aria-current="page">main.bicep</a>`,
+				`<td><a href="network/vnet/index.html">./network/vnet/main.bicep</a></td>`,
+				`<h2 id="resource-providers"><a class="anchor" href="#resource-providers">Resource Providers</a></h2>`,
+				`<td>Microsoft.Resources/resourceGroups</td>`,
+			},
+		},
+		{
+			file: "network/vnet/index.html",
+			expected: []string{
+				`<title>Network · Bicep modules</title>`,
+				`<link rel="stylesheet" href="../../site.css">`,
+				`<body data-root="../../">`,
+				`<li><span>network</span><ul><li><a class="current" href="../../network/vnet/index.html" aria-current="page">vnet</a></li></ul></li>`,
+				`<p class="text">Deploys the network.</p>`,
+				`<tr><td><a class="anchor" id="param-addressPrefix" href="#param-addressPrefix">addressPrefix</a></td><td>Required</td>`,
+				`<td>subnetType[] (uddt)</td>`,
+				`<td>[]</td>`,
+				`<a class="anchor" id="type-subnetType" href="#type-subnetType">subnetType</a>`,
+				`<h3 id="subnettype"><a class="anchor" href="#subnettype">subnetType</a></h3>`,
+				`<td><a href="#subnettype">View Properties</a></td>`,
+				`<a class="anchor" id="output-id" href="#output-id">id</a>`,
+			},
+		},
+		{
+			file: "search-index.js",
+			expected: []string{
+				`window.searchIndex = [`,
+				`{"name":"main.bicep","kind":"module","module":"main.bicep","url":"module.html"}`,
+				`{"name":"addressPrefix","kind":"parameter","module":"Network","description":"The address prefix of the network.","url":"network/vnet/index.html#param-addressPrefix"}`,
+				`"url":"network/vnet/index.html#type-subnetType"`,
+				`"url":"network/vnet/index.html#output-id"`,
+			},
+		},
+		{file: "site.css"},
+		{file: "site.js"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.file)))
+			if err != nil {
+				t.Fatalf("Create() did not write %s: %v", tt.file, err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(content), expected) {
+					t.Errorf("%s does not contain %s", tt.file, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate_decorators(t *testing.T) {
+	pages := []Page{
+		{
+			Dir: ".",
+			Template: &types.Template{
+				FileName: "main.bicep",
+				Parameters: []types.Parameter{
+					{Name: "name", Type: "string", MinLength: intPtr(3), MaxLength: intPtr(24)},
+					{Name: "sku", Type: "string", AllowedValues: []any{"Basic", "Standard"}},
+				},
+				Outputs: []types.Output{{Name: "id", Type: "string", MinValue: intPtr(1)}},
+			},
+		},
+	}
+	opts := Options{
+		Sections:   []types.Section{types.ParametersSection, types.OutputsSection},
+		Decorators: []types.Decorator{types.LengthDecorator},
+	}
+
+	dir := t.TempDir()
+	if err := Create(dir, pages, opts); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "module.html"))
+	if err != nil {
+		t.Fatalf("Create() did not write module.html: %v", err)
+	}
+	if !strings.Contains(string(content), "<td>3</td><td>24</td>") {
+		t.Errorf("module.html does not contain the length decorators")
+	}
+	if strings.Contains(string(content), "<th>Allowed Values</th>") || strings.Contains(string(content), "<th>Min Value</th>") {
+		t.Errorf("module.html contains decorators that are not selected")
+	}
+	// The outputs have no length decorators, so only the parameters table has the length columns
+	if count := strings.Count(string(content), "<th>Min Length</th>"); count != 1 {
+		t.Errorf("module.html has %d Min Length columns, expected 1", count)
+	}
+}
+
+func TestCreate_compactConstraints(t *testing.T) {
+	pages := []Page{
+		{
+			Dir: ".",
+			Template: &types.Template{
+				FileName:   "main.bicep",
+				Parameters: []types.Parameter{{Name: "name", Type: "string", MinLength: intPtr(3), MaxLength: intPtr(24)}},
+			},
+		},
+	}
+	opts := Options{
+		Sections:           []types.Section{types.ParametersSection},
+		Decorators:         []types.Decorator{types.LengthDecorator},
+		CompactConstraints: true,
+	}
+
+	dir := t.TempDir()
+	if err := Create(dir, pages, opts); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "module.html"))
+	if err != nil {
+		t.Fatalf("Create() did not write module.html: %v", err)
+	}
+	if !strings.Contains(string(content), "<th>Constraints</th>") || !strings.Contains(string(content), "<td>3–24 chars</td>") {
+		t.Errorf("module.html does not contain the compact constraints")
+	}
+	if strings.Contains(string(content), "<th>Min Length</th>") {
+		t.Errorf("module.html contains the length columns")
+	}
+}
+
+func TestCreate_groupByCategory(t *testing.T) {
+	networking := &types.Metadata{Custom: []types.MetadataItem{{Key: "category", Value: "Virtual Network"}}}
+	pages := []Page{
+		{
+			Dir: ".",
+			Template: &types.Template{
+				FileName: "main.bicep",
+				Parameters: []types.Parameter{
+					{Name: "location", Type: "string"},
+					{Name: "subnetId", Type: "string", Metadata: networking},
+					{Name: "vnetName", Type: "string", Metadata: networking},
+				},
+			},
+		},
+	}
+	opts := Options{Sections: []types.Section{types.ParametersSection}, GroupByCategory: true}
+
+	dir := t.TempDir()
+	if err := Create(dir, pages, opts); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "module.html"))
+	if err != nil {
+		t.Fatalf("Create() did not write module.html: %v", err)
+	}
+	if !strings.Contains(string(content), `<h3 id="virtual-network">`) {
+		t.Errorf("module.html does not contain the sub-table of the category")
+	}
+	if count := strings.Count(string(content), "<table"); count != 2 {
+		t.Errorf("module.html has %d tables, expected 2", count)
+	}
+	location, subnet := strings.Index(string(content), `id="param-location"`), strings.Index(string(content), `id="param-subnetId"`)
+	if location < 0 || subnet < 0 || location > subnet {
+		t.Errorf("module.html does not list the parameters without a category first")
+	}
+}
+
+func TestCreate_declarationMetadata(t *testing.T) {
+	pages := []Page{
+		{
+			Dir: ".",
+			Template: &types.Template{
+				FileName: "main.bicep",
+				Parameters: []types.Parameter{
+					{
+						Name: "location",
+						Type: "string",
+						Metadata: &types.Metadata{Custom: []types.MetadataItem{
+							{Key: "example", Value: "westeurope"},
+							{Key: "owner", Value: "platform"},
+						}},
+					},
+					{Name: "skuName", Type: "string", Metadata: &types.Metadata{Custom: []types.MetadataItem{{Key: "deprecated", Value: "Use sku instead."}}}},
+				},
+				Outputs: []types.Output{{Name: "id", Type: "string"}},
+			},
+		},
+	}
+	opts := Options{Sections: []types.Section{types.ParametersSection, types.OutputsSection}, MetadataColumns: []string{"owner", "docsLink"}}
+
+	dir := t.TempDir()
+	if err := Create(dir, pages, opts); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "module.html"))
+	if err != nil {
+		t.Fatalf("Create() did not write module.html: %v", err)
+	}
+	content := string(data)
+	for _, expected := range []string{
+		"<th>Example</th><th>owner</th>",
+		"<code>&#39;westeurope&#39;</code>",
+		"<del>skuName</del>",
+		"<strong>Deprecated:</strong> Use sku instead.",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("module.html does not contain %q", expected)
+		}
+	}
+	// The docsLink column is empty in every row, and so are the metadata columns of the outputs
+	if count := strings.Count(content, "<th>Example</th>"); count != 1 || strings.Contains(content, "<th>docsLink</th>") {
+		t.Errorf("module.html has %d Example columns, expected 1, and no docsLink column", count)
+	}
+}
+
+func TestCreate_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pages    []Page
+		expected string
+	}{
+		{name: "no_pages", expected: "no modules to document"},
+		{name: "no_template", pages: []Page{{Dir: "storage"}}, expected: "has no template"},
+		{name: "outside", pages: []Page{{Dir: "../storage", Template: &types.Template{}}}, expected: "outside of the repository"},
+		{
+			name:     "duplicate",
+			pages:    []Page{{Dir: "storage", Template: &types.Template{}}, {Dir: "storage/", Template: &types.Template{}}},
+			expected: "documented twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Create(t.TempDir(), tt.pages, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Create() error = %v, expected to contain = %s", err, tt.expected)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -2,53 +2,149 @@ package template
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
 )
 
-// BuildBicepTemplate compiles a Bicep file into an ARM template, stored in the temporary directory.
+// errStdoutUnsupported is returned by runCommand when the compiler does not recognize the '--stdout' option.
+var errStdoutUnsupported = errors.New("the compiler does not support '--stdout'")
+
+// stdoutUnsupported records that the compiler lacks '--stdout', so that later builds
+// go straight to the file-based fallback.
+var stdoutUnsupported atomic.Bool
+
+// BuildBicepTemplate compiles a Bicep file into an ARM template and returns its JSON content.
 // It checks for the 'bicep' or 'az' commands to perform the build.
-// The function returns the path to the generated ARM template file, or an error if the build fails or the necessary commands are not found.
-func BuildBicepTemplate(bicepFile string) (string, error) {
+//
+// The ARM template is captured in memory from the compiler's '--stdout' output.
+// For compilers that lack '--stdout', it falls back to writing the ARM template to a temporary file,
+// which is read and removed immediately.
+//
+// The function also returns the diagnostics (e.g. linter warnings) reported by the compiler,
+// or an error if the build fails or the necessary commands are not found.
+// If the compiler reports diagnostics for a failed build, the error is a *DiagnosticsError.
+//
+// The compiler process is killed when ctx is canceled or its deadline expires;
+// in that case ctx.Err() is wrapped in the returned error.
+func BuildBicepTemplate(ctx context.Context, bicepFile string) ([]byte, []types.Diagnostic, error) {
 	// Validate file extension
-	basename := filepath.Base(bicepFile)
-	filename := strings.TrimSuffix(basename, filepath.Ext(basename))
-	extension := filepath.Ext(basename)
-	if extension != ".bicep" {
-		return "", fmt.Errorf("file extension must be '.bicep'")
+	if filepath.Ext(bicepFile) != ".bicep" {
+		return nil, nil, fmt.Errorf("file extension must be '.bicep'")
 	}
 
-	// ARM template file path
-	tmp := os.TempDir()
-	armFile := filepath.Join(tmp, fmt.Sprintf("%s_%s.json", filename, uuid.New().String()))
+	command, err := detectBuildCommand()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buildWithCommand(ctx, command, bicepFile)
+}
 
-	// Build Bicep template into an ARM template
-	// 1. If 'bicep' exists, run 'bicep build'
-	// 2. If 'bicep' does not exist, check if 'az' exists
-	// 3. If 'az' exists, run 'az bicep build'
-	// 4. If 'az' does not exist, return an error
-	var cmd *exec.Cmd
+// detectBuildCommand returns the build command of the first compiler found in the system's PATH:
+//  1. If 'bicep' exists, 'bicep build'
+//  2. If 'bicep' does not exist, check if 'az' exists
+//  3. If 'az' exists, 'az bicep build'
+//  4. If 'az' does not exist, return an error
+func detectBuildCommand() ([]string, error) {
 	switch {
 	case commandExists("bicep"):
-		cmd = exec.Command("bicep", "build", bicepFile, "--outfile", armFile)
+		return bicepBuildCommand("bicep"), nil
 	case commandExists("az"):
-		cmd = exec.Command("az", "bicep", "build", "--file", bicepFile, "--outfile", armFile)
+		return azBuildCommand("az"), nil
 	default:
-		return "", fmt.Errorf("error processing %s: neither 'bicep' nor 'az' commands were found", bicepFile)
+		return nil, errors.New("neither 'bicep' nor 'az' commands were found")
+	}
+}
+
+// bicepBuildCommand returns the build command of the Bicep CLI at the given path.
+func bicepBuildCommand(bicep string) []string {
+	return []string{bicep, "build"}
+}
+
+// azBuildCommand returns the build command of the Azure CLI at the given path.
+func azBuildCommand(az string) []string {
+	return []string{az, "bicep", "build", "--file"}
+}
+
+// buildWithCommand compiles a Bicep file with the given build command, to which the Bicep file is appended.
+// See BuildBicepTemplate for the handling of the output, diagnostics, and cancellation.
+func buildWithCommand(ctx context.Context, command []string, bicepFile string) ([]byte, []types.Diagnostic, error) {
+	armTemplate, diagnostics, err := runBuild(ctx, append(command[:len(command):len(command)], bicepFile), bicepFile)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("build canceled: %w", ctx.Err())
+		}
+		return nil, nil, err
+	}
+
+	return armTemplate, diagnostics, nil
+}
+
+// runBuild runs a build command that ends with the file to compile (a Bicep or a parameters file)
+// and returns its JSON output: from '--stdout', or from a temporary '--outfile' for compilers that lack '--stdout'.
+func runBuild(ctx context.Context, command []string, file string) ([]byte, []types.Diagnostic, error) {
+	var output []byte
+	var diagnostics []types.Diagnostic
+	var err error
+	if !stdoutUnsupported.Load() {
+		output, diagnostics, err = buildToStdout(ctx, command)
+		if errors.Is(err, errStdoutUnsupported) {
+			stdoutUnsupported.Store(true)
+		}
+	}
+	if stdoutUnsupported.Load() {
+		output, diagnostics, err = buildToFile(ctx, command, file)
 	}
+	return output, diagnostics, err
+}
+
+// buildToStdout runs the build command with the '--stdout' option and returns the ARM template written to stdout.
+func buildToStdout(ctx context.Context, command []string) ([]byte, []types.Diagnostic, error) {
+	args := append(command[1:len(command):len(command)], "--stdout")
+	cmd := exec.CommandContext(ctx, command[0], args...)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
 
-	// Run the command and handle any errors
-	if err := runCommand(cmd); err != nil {
-		return "", err
+	diagnostics, err := runCommand(cmd)
+	if err != nil {
+		return nil, nil, err
 	}
+	return stdout.Bytes(), diagnostics, nil
+}
+
+// buildToFile runs the build command with the '--outfile' option, pointing to a uuid-named file
+// in the temporary directory, and returns the content of that file.
+// The file is removed before returning, whether the build succeeded or not.
+func buildToFile(ctx context.Context, command []string, bicepFile string) ([]byte, []types.Diagnostic, error) {
+	basename := filepath.Base(bicepFile)
+	filename := strings.TrimSuffix(basename, filepath.Ext(basename))
+	armFile := filepath.Join(os.TempDir(), fmt.Sprintf("%s_%s.json", filename, uuid.New().String()))
+	defer os.Remove(armFile)
 
-	return armFile, nil
+	args := append(command[1:len(command):len(command)], "--outfile", armFile)
+	cmd := exec.CommandContext(ctx, command[0], args...)
+
+	diagnostics, err := runCommand(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	armTemplate, err := os.ReadFile(armFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read ARM template: %w", err)
+	}
+	return armTemplate, diagnostics, nil
 }
 
 // commandExists checks if a command exists in the system's PATH.
@@ -58,23 +154,38 @@ func commandExists(cmd string) bool {
 	return err == nil
 }
 
-// runCommand executes the given command and returns an error if the command fails.
-// It captures the error message from stderr and returns it as an error.
-func runCommand(cmd *exec.Cmd) error {
+// runCommand executes the given command and returns the diagnostics it reported on stderr.
+// If the command fails, the diagnostics are returned as a *DiagnosticsError;
+// if stderr contains no diagnostics, the first line containing "Error" is returned instead.
+// If the command rejected the '--stdout' option, errStdoutUnsupported is returned.
+func runCommand(cmd *exec.Cmd) ([]types.Diagnostic, error) {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
+	configureCommand(cmd)
 	err := cmd.Run()
+	diagnostics := parseDiagnostics(stderr.String())
 	if err != nil {
+		if len(diagnostics) > 0 {
+			return nil, &DiagnosticsError{Diagnostics: diagnostics}
+		}
+
+		// The Bicep CLI reports 'Unrecognized parameter "--stdout"',
+		// the Azure CLI 'unrecognized arguments: --stdout'.
+		output := strings.ToLower(stderr.String())
+		if strings.Contains(output, "unrecognized") && strings.Contains(output, "--stdout") {
+			return nil, errStdoutUnsupported
+		}
+
 		// Extract the error message from stderr
 		errorLines := strings.Split(stderr.String(), "\n")
 		for _, line := range errorLines {
 			if strings.Contains(line, "Error") {
-				return errors.New(line)
+				return nil, errors.New(line)
 			}
 		}
-		return fmt.Errorf("failed to run command: %w", err)
+		return nil, fmt.Errorf("failed to run command: %w", err)
 	}
 
-	return nil
+	return diagnostics, nil
 }
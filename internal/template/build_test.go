@@ -1,7 +1,12 @@
 package template
 
 import (
+	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -45,24 +50,86 @@ func TestBuildBicepTemplate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, err := BuildBicepTemplate(tt.args.bicepFile)
+			if !tt.wantErr && !commandExists("bicep") && !commandExists("az") {
+				t.Skip("neither 'bicep' nor 'az' commands were found")
+			}
+			armTemplate, _, err := BuildBicepTemplate(context.Background(), tt.args.bicepFile)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("BuildBicepTemplate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if err != nil {
-				if err := os.Remove(path); err == nil {
-					t.Fatalf("BuildBicepTemplate() did create file %s", path)
-				}
-			} else {
-				if err := os.Remove(path); err != nil {
-					t.Fatalf("BuildBicepTemplate() did not create file %s", path)
+				if armTemplate != nil {
+					t.Fatalf("BuildBicepTemplate() returned an ARM template along with error %v", err)
 				}
+			} else if !json.Valid(armTemplate) {
+				t.Fatalf("BuildBicepTemplate() returned invalid JSON %q", armTemplate)
 			}
 		})
 	}
 }
 
+func TestBuildBicepTemplate_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	armTemplate, _, err := BuildBicepTemplate(ctx, "./testdata/basic.bicep")
+	if err == nil {
+		t.Fatalf("BuildBicepTemplate() error = nil, want an error for a canceled context")
+	}
+	if armTemplate != nil {
+		t.Fatalf("BuildBicepTemplate() returned an ARM template for a canceled context")
+	}
+}
+
+func TestBuildBicepTemplate_stdoutFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+
+	// Fake Bicep CLI that rejects '--stdout' and writes the ARM template to the '--outfile' path.
+	script := `#!/bin/sh
+for arg in "$@"; do
+  if [ "$arg" = "--stdout" ]; then
+    echo 'Unrecognized parameter "--stdout"' >&2
+    exit 1
+  fi
+done
+while [ "$#" -gt 0 ]; do
+  if [ "$1" = "--outfile" ]; then
+    echo '{"parameters": {}}' > "$2"
+  fi
+  shift
+done
+`
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "bicep"), []byte(script), 0o700); err != nil { //nolint:gosec // The fake compiler must be executable.
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Cleanup(func() { stdoutUnsupported.Store(false) })
+
+	armTemplate, _, err := BuildBicepTemplate(context.Background(), "./testdata/basic.bicep")
+	if err != nil {
+		t.Fatalf("BuildBicepTemplate() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(armTemplate)); got != `{"parameters": {}}` {
+		t.Fatalf("BuildBicepTemplate() = %q, want the ARM template written to the output file", got)
+	}
+	if !stdoutUnsupported.Load() {
+		t.Fatalf("BuildBicepTemplate() did not record that '--stdout' is unsupported")
+	}
+
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("BuildBicepTemplate() left %d files in the temporary directory", len(entries))
+	}
+}
+
 func Test_commandExists(t *testing.T) {
 	type args struct {
 		cmd string
@@ -0,0 +1,261 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// Compiler compiles Bicep files into ARM templates.
+//
+// Build returns the JSON content of the ARM template and the diagnostics (e.g. linter warnings)
+// reported by the compiler. If the compiler reports diagnostics for a failed build,
+// the error is a *DiagnosticsError. Build may be called concurrently.
+//
+// Version returns the version of the compiler, to be checked against the minimum supported versions.
+//
+// Close releases the resources held by the compiler (e.g. a long-running process).
+type Compiler interface {
+	Build(ctx context.Context, bicepFile string) ([]byte, []types.Diagnostic, error)
+	Version(ctx context.Context) (Version, error)
+	Close() error
+}
+
+// ParamsValidator is implemented by the compilers that can validate a parameters file (.bicepparam)
+// by compiling it together with the Bicep file it references.
+//
+// ValidateParams returns the diagnostics reported by the compiler.
+// If the parameters file is invalid, the error is a *DiagnosticsError.
+type ParamsValidator interface {
+	ValidateParams(ctx context.Context, paramsFile string) ([]types.Diagnostic, error)
+}
+
+// Backend is an enum that represents the way a Compiler runs the Bicep CLI.
+// The backend can be either "exec" or "jsonrpc".
+type Backend string
+
+const (
+	ExecBackend    Backend = "exec"    // ExecBackend runs one compiler process per Bicep file
+	JSONRPCBackend Backend = "jsonrpc" // JSONRPCBackend compiles every Bicep file through one long-running 'bicep jsonrpc' process
+)
+
+func (b Backend) String() string {
+	return string(b)
+}
+
+// ParseBackendFromString converts a string to its corresponding Backend enum value.
+func ParseBackendFromString(str string) (Backend, error) {
+	switch Backend(str) {
+	case ExecBackend:
+		return ExecBackend, nil
+	case JSONRPCBackend:
+		return JSONRPCBackend, nil
+	default:
+		return "", fmt.Errorf("invalid build backend: %q", str)
+	}
+}
+
+// Names of the compilers accepted by NewCompiler, besides an explicit path to a Bicep or Azure CLI binary.
+const (
+	AutoCompiler  = ""      // AutoCompiler uses 'bicep' if it exists, otherwise 'az'
+	BicepCompiler = "bicep" // BicepCompiler uses the Bicep CLI from the system's PATH
+	AzCompiler    = "az"    // AzCompiler uses the Azure CLI from the system's PATH
+	NoCompiler    = "none"  // NoCompiler reads pre-built ARM templates instead of compiling, see FixtureCompiler
+)
+
+// NewCompiler returns the Compiler for the given compiler name and backend.
+//
+// The compiler is either one of AutoCompiler, BicepCompiler, AzCompiler, and NoCompiler,
+// or an explicit path to a Bicep CLI binary (or to an Azure CLI binary named 'az').
+// The backend is ignored by NoCompiler. The JSONRPCBackend needs a Bicep CLI binary;
+// with AzCompiler, the copy installed by the Azure CLI ('az bicep install') is used.
+func NewCompiler(compiler string, backend Backend) (Compiler, error) {
+	if compiler == NoCompiler {
+		return &FixtureCompiler{}, nil
+	}
+
+	switch backend {
+	case ExecBackend:
+		switch compiler {
+		case AutoCompiler:
+			return &ExecCompiler{}, nil
+		case BicepCompiler, AzCompiler:
+			path, err := exec.LookPath(compiler)
+			if err != nil {
+				return nil, fmt.Errorf("the '%s' command was not found", compiler)
+			}
+			if compiler == AzCompiler {
+				return &ExecCompiler{Command: azBuildCommand(path)}, nil
+			}
+			return &ExecCompiler{Command: bicepBuildCommand(path)}, nil
+		default:
+			if err := checkCompilerPath(compiler); err != nil {
+				return nil, err
+			}
+			if isAzBinary(compiler) {
+				return &ExecCompiler{Command: azBuildCommand(compiler)}, nil
+			}
+			return &ExecCompiler{Command: bicepBuildCommand(compiler)}, nil
+		}
+	case JSONRPCBackend:
+		switch compiler {
+		case AutoCompiler:
+			return &JSONRPCCompiler{}, nil
+		case BicepCompiler:
+			path, err := exec.LookPath(compiler)
+			if err != nil {
+				return nil, fmt.Errorf("the '%s' command was not found", compiler)
+			}
+			return &JSONRPCCompiler{Path: path}, nil
+		case AzCompiler:
+			path, err := azureCLIBicepPath()
+			if err != nil {
+				return nil, err
+			}
+			return &JSONRPCCompiler{Path: path}, nil
+		default:
+			if err := checkCompilerPath(compiler); err != nil {
+				return nil, err
+			}
+			if isAzBinary(compiler) {
+				return nil, fmt.Errorf("the %s backend requires a Bicep CLI binary, not %q", JSONRPCBackend, compiler)
+			}
+			return &JSONRPCCompiler{Path: compiler}, nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid build backend: %q", backend)
+	}
+}
+
+// checkCompilerPath checks that an explicit compiler path points to an existing file.
+func checkCompilerPath(path string) error {
+	f, err := os.Stat(path)
+	if err != nil || f.IsDir() {
+		return fmt.Errorf("invalid compiler %q: expected bicep, az, none, or the path to a Bicep CLI binary", path)
+	}
+	return nil
+}
+
+// isAzBinary reports whether the path points to the Azure CLI (az, az.cmd, az.exe) rather than the Bicep CLI.
+func isAzBinary(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	return strings.TrimSuffix(name, filepath.Ext(name)) == "az"
+}
+
+// ExecCompiler is the default Compiler.
+// It runs the Bicep CLI once per Bicep file.
+//
+// Command is the build command to which the Bicep file is appended
+// (e.g. ["/opt/bicep/bicep", "build"] or ["az", "bicep", "build", "--file"]).
+// If Command is empty, 'bicep' or 'az' is detected on every build, see BuildBicepTemplate.
+type ExecCompiler struct {
+	Command []string
+}
+
+// Build compiles a Bicep file by running the Bicep CLI.
+func (c *ExecCompiler) Build(ctx context.Context, bicepFile string) ([]byte, []types.Diagnostic, error) {
+	if len(c.Command) == 0 {
+		return BuildBicepTemplate(ctx, bicepFile)
+	}
+	if filepath.Ext(bicepFile) != ".bicep" {
+		return nil, nil, fmt.Errorf("file extension must be '.bicep'")
+	}
+	return buildWithCommand(ctx, c.Command, bicepFile)
+}
+
+// ValidateParams compiles a parameters file with 'bicep build-params' (or 'az bicep build-params'),
+// discarding the resulting parameters. As for Build, compilers that lack '--stdout' write them to a temporary file.
+func (c *ExecCompiler) ValidateParams(ctx context.Context, paramsFile string) ([]types.Diagnostic, error) {
+	if filepath.Ext(paramsFile) != ".bicepparam" {
+		return nil, fmt.Errorf("file extension must be '.bicepparam'")
+	}
+	command := c.Command
+	if len(command) == 0 {
+		var err error
+		if command, err = detectBuildCommand(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Replace the 'build' subcommand with 'build-params', keeping the other arguments (e.g. '--file')
+	paramsCommand := append(make([]string, 0, len(command)+1), command[0])
+	for _, arg := range command[1:] {
+		if arg == "build" {
+			arg = "build-params"
+		}
+		paramsCommand = append(paramsCommand, arg)
+	}
+	paramsCommand = append(paramsCommand, paramsFile)
+
+	_, diagnostics, err := runBuild(ctx, paramsCommand, paramsFile)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("validation canceled: %w", ctx.Err())
+		}
+		return nil, err
+	}
+	return diagnostics, nil
+}
+
+// Version returns the version of the Bicep CLI, and of the Azure CLI if the build runs through it.
+func (c *ExecCompiler) Version(ctx context.Context) (Version, error) {
+	command := c.Command
+	if len(command) == 0 {
+		var err error
+		if command, err = detectBuildCommand(); err != nil {
+			return Version{}, err
+		}
+	}
+	return commandVersion(ctx, command)
+}
+
+// Close is a no-op; no process outlives a build.
+func (c *ExecCompiler) Close() error {
+	return nil
+}
+
+// FixtureCompiler is a Compiler that does not compile anything.
+// It maps every Bicep file to a pre-built (e.g. checked-in) ARM template and returns its content,
+// so that the documentation can be generated, and tested, without the Bicep CLI.
+//
+// The ARM template of "dir/name.bicep" is "dir/name.json", or "Dir/name.json" if Dir is set.
+type FixtureCompiler struct {
+	Dir string
+}
+
+// Build returns the content of the ARM template mapped to the Bicep file.
+func (c *FixtureCompiler) Build(_ context.Context, bicepFile string) ([]byte, []types.Diagnostic, error) {
+	if filepath.Ext(bicepFile) != ".bicep" {
+		return nil, nil, fmt.Errorf("file extension must be '.bicep'")
+	}
+
+	dir := c.Dir
+	if dir == "" {
+		dir = filepath.Dir(bicepFile)
+	}
+	name := strings.TrimSuffix(filepath.Base(bicepFile), ".bicep") + ".json"
+	armTemplate, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("no pre-built ARM template %q", filepath.Join(dir, name))
+		}
+		return nil, nil, err
+	}
+	return armTemplate, nil, nil
+}
+
+// Version returns the zero Version: the compiler of the pre-built ARM templates is unknown.
+func (c *FixtureCompiler) Version(_ context.Context) (Version, error) {
+	return Version{}, nil
+}
+
+// Close is a no-op.
+func (c *FixtureCompiler) Close() error {
+	return nil
+}
@@ -0,0 +1,242 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseBackendFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    Backend
+		wantErr bool
+	}{
+		{name: "exec", str: "exec", want: ExecBackend},
+		{name: "jsonrpc", str: "jsonrpc", want: JSONRPCBackend},
+		{name: "invalid", str: "batch", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBackendFromString(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBackendFromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBackendFromString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCompiler(t *testing.T) {
+	bicep := filepath.Join(t.TempDir(), "bicep")
+	if err := os.WriteFile(bicep, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	az := filepath.Join(t.TempDir(), "az")
+	if err := os.WriteFile(az, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		compiler string
+		backend  Backend
+		want     Compiler
+		wantErr  bool
+	}{
+		{name: "exec", compiler: AutoCompiler, backend: ExecBackend, want: &ExecCompiler{}},
+		{name: "jsonrpc", compiler: AutoCompiler, backend: JSONRPCBackend, want: &JSONRPCCompiler{}},
+		{name: "none", compiler: NoCompiler, backend: ExecBackend, want: &FixtureCompiler{}},
+		{name: "none_jsonrpc", compiler: NoCompiler, backend: JSONRPCBackend, want: &FixtureCompiler{}},
+		{name: "bicep_path", compiler: bicep, backend: ExecBackend, want: &ExecCompiler{Command: []string{bicep, "build"}}},
+		{name: "az_path", compiler: az, backend: ExecBackend, want: &ExecCompiler{Command: []string{az, "bicep", "build", "--file"}}},
+		{name: "bicep_path_jsonrpc", compiler: bicep, backend: JSONRPCBackend, want: &JSONRPCCompiler{Path: bicep}},
+		{name: "az_path_jsonrpc", compiler: az, backend: JSONRPCBackend, wantErr: true},
+		{name: "non_existent_path", compiler: filepath.Join(t.TempDir(), "bicep"), backend: ExecBackend, wantErr: true},
+		{name: "directory_path", compiler: t.TempDir(), backend: ExecBackend, wantErr: true},
+		{name: "invalid_backend", compiler: AutoCompiler, backend: Backend("batch"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCompiler(tt.compiler, tt.backend)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCompiler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewCompiler() = %#v, want %#v", got, tt.want)
+			}
+			if err := got.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFixtureCompiler_Build(t *testing.T) {
+	tests := []struct {
+		name      string
+		compiler  *FixtureCompiler
+		bicepFile string
+		wantFile  string
+		wantErr   bool
+	}{
+		{
+			name:      "next_to_bicep_file",
+			compiler:  &FixtureCompiler{},
+			bicepFile: "./testdata/basic.bicep",
+			wantFile:  "./testdata/basic.json",
+		},
+		{
+			name:      "from_directory",
+			compiler:  &FixtureCompiler{Dir: "./testdata"},
+			bicepFile: "./path/to/loops.bicep",
+			wantFile:  "./testdata/loops.json",
+		},
+		{
+			name:      "missing_arm_template",
+			compiler:  &FixtureCompiler{},
+			bicepFile: "./testdata/invalid.bicep",
+			wantErr:   true,
+		},
+		{
+			name:      "invalid_file_extension",
+			compiler:  &FixtureCompiler{},
+			bicepFile: "./testdata/basic.json",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			armTemplate, diagnostics, err := tt.compiler.Build(context.Background(), tt.bicepFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FixtureCompiler.Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want, err := os.ReadFile(tt.wantFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(armTemplate, want) {
+				t.Errorf("FixtureCompiler.Build() returned the content of another file than %s", tt.wantFile)
+			}
+			if diagnostics != nil {
+				t.Errorf("FixtureCompiler.Build() diagnostics = %v, want nil", diagnostics)
+			}
+		})
+	}
+}
+
+func TestExecCompiler_ValidateParams(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+
+	// Fake Bicep CLI that records its arguments and fails for "invalid.bicepparam".
+	dir := t.TempDir()
+	bicep := filepath.Join(dir, "bicep")
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+echo "$@" > "` + argsFile + `"
+case "$2" in
+  *invalid.bicepparam)
+    echo "$2(3,7) : Error BCP033: Expected a value of type \"int\"." >&2
+    exit 1
+    ;;
+esac
+`
+	if err := os.WriteFile(bicep, []byte(script), 0o700); err != nil { //nolint:gosec // The fake compiler must be executable.
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		paramsFile string
+		wantArgs   string
+		wantErr    string
+	}{
+		{name: "valid_file", paramsFile: "main.example.bicepparam", wantArgs: "build-params main.example.bicepparam --stdout\n"},
+		{name: "invalid_file", paramsFile: "invalid.bicepparam", wantErr: "invalid.bicepparam(3,7) : Error BCP033"},
+		{name: "invalid_extension", paramsFile: "main.bicep", wantErr: "file extension must be '.bicepparam'"},
+	}
+	compiler := &ExecCompiler{Command: bicepBuildCommand(bicep)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compiler.ValidateParams(context.Background(), tt.paramsFile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ExecCompiler.ValidateParams() error = %v, expected to contain = %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecCompiler.ValidateParams() unexpected error = %v", err)
+			}
+			args, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(args) != tt.wantArgs {
+				t.Errorf("ExecCompiler.ValidateParams() ran with %q, want %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExecCompiler_ValidateParams_stdoutFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+
+	// Fake Bicep CLI that rejects '--stdout' and records the arguments of the '--outfile' fallback.
+	dir := t.TempDir()
+	bicep := filepath.Join(dir, "bicep")
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+for arg in "$@"; do
+  if [ "$arg" = "--stdout" ]; then
+    echo 'Unrecognized parameter "--stdout"' >&2
+    exit 1
+  fi
+done
+echo "$1 $2 $3" > "` + argsFile + `"
+echo '{"parameters": {}}' > "$4"
+`
+	if err := os.WriteFile(bicep, []byte(script), 0o700); err != nil { //nolint:gosec // The fake compiler must be executable.
+		t.Fatal(err)
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Cleanup(func() { stdoutUnsupported.Store(false) })
+
+	compiler := &ExecCompiler{Command: bicepBuildCommand(bicep)}
+	if _, err := compiler.ValidateParams(context.Background(), "main.example.bicepparam"); err != nil {
+		t.Fatalf("ExecCompiler.ValidateParams() error = %v", err)
+	}
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "build-params main.example.bicepparam --outfile\n"; string(args) != want {
+		t.Errorf("ExecCompiler.ValidateParams() ran with %q, want %q", args, want)
+	}
+
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("ExecCompiler.ValidateParams() left %d files in the temporary directory", len(entries))
+	}
+}
@@ -0,0 +1,104 @@
+package template
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// diagnosticRegex matches a diagnostic in the format emitted by the Bicep CLI:
+//
+//	/path/main.bicep(3,7) : Error BCP018: Expected the "=" character at this location. [https://aka.ms/bicep/core-diagnostics#BCP018]
+//
+// The end of the range (e.g. "(3,7)-(3,12)") and the trailing documentation link are optional.
+var diagnosticRegex = regexp.MustCompile(
+	`^(.+?)\((\d+),(\d+)\)(?:-\((\d+),(\d+)\))?\s*:\s*(Error|Warning|Info)\s+([^\s:]+)\s*:\s*(.*?)(?:\s+\[(\S+)\])?\s*$`,
+)
+
+// DiagnosticsError is returned when the Bicep compiler fails with one or more diagnostics.
+// It carries every diagnostic reported by the compiler, including warnings.
+type DiagnosticsError struct {
+	Diagnostics []types.Diagnostic
+}
+
+// Error returns the error diagnostics, one per line.
+// If the compiler reported no error diagnostics, all diagnostics are returned.
+func (e *DiagnosticsError) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics))
+	for i := range e.Diagnostics {
+		if e.Diagnostics[i].Severity == types.ErrorSeverity {
+			lines = append(lines, e.Diagnostics[i].String())
+		}
+	}
+	if len(lines) == 0 {
+		for i := range e.Diagnostics {
+			lines = append(lines, e.Diagnostics[i].String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Errors returns only the diagnostics with error severity.
+func (e *DiagnosticsError) Errors() []types.Diagnostic {
+	return filterDiagnostics(e.Diagnostics, types.ErrorSeverity)
+}
+
+// Warnings returns only the diagnostics with warning severity.
+func (e *DiagnosticsError) Warnings() []types.Diagnostic {
+	return filterDiagnostics(e.Diagnostics, types.WarningSeverity)
+}
+
+// parseDiagnostics parses the output of the Bicep CLI and returns the diagnostics it contains.
+// Lines that are not diagnostics (e.g. Azure CLI upgrade notices) are ignored.
+func parseDiagnostics(output string) []types.Diagnostic {
+	var diagnostics []types.Diagnostic
+	for _, line := range strings.Split(output, "\n") {
+		if diagnostic := parseDiagnostic(strings.TrimRight(line, "\r")); diagnostic != nil {
+			diagnostics = append(diagnostics, *diagnostic)
+		}
+	}
+	return diagnostics
+}
+
+// parseDiagnostic parses a single line of the Bicep CLI output and returns a pointer to a types.Diagnostic.
+// If the line does not match the diagnostic format, it returns nil.
+func parseDiagnostic(line string) *types.Diagnostic {
+	matches := diagnosticRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return nil
+	}
+
+	diagnostic := &types.Diagnostic{
+		File:     matches[1],
+		Line:     atoi(matches[2]),
+		Column:   atoi(matches[3]),
+		Severity: types.DiagnosticSeverity(matches[6]),
+		Code:     matches[7],
+		Message:  matches[8],
+		Link:     matches[9],
+	}
+	if matches[4] != "" {
+		diagnostic.EndLine = atoi(matches[4])
+		diagnostic.EndColumn = atoi(matches[5])
+	}
+	return diagnostic
+}
+
+// filterDiagnostics returns the diagnostics with the given severity.
+func filterDiagnostics(diagnostics []types.Diagnostic, severity types.DiagnosticSeverity) []types.Diagnostic {
+	var filtered []types.Diagnostic
+	for i := range diagnostics {
+		if diagnostics[i].Severity == severity {
+			filtered = append(filtered, diagnostics[i])
+		}
+	}
+	return filtered
+}
+
+// atoi converts a string of digits, already validated by a regular expression, to an int.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s) //nolint:errcheck // The input is validated by the regular expression.
+	return n
+}
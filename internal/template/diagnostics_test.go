@@ -0,0 +1,94 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_parseDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []types.Diagnostic
+	}{
+		{
+			name: "errors_and_warnings",
+			output: "/src/main.bicep(3,7) : Error BCP018: Expected the \"=\" character at this location. [https://aka.ms/bicep/core-diagnostics#BCP018]\r\n" +
+				"/src/main.bicep(1,7) : Warning no-unused-params: Parameter \"location\" is declared but never used. [https://aka.ms/bicep/linter/no-unused-params]\n" +
+				"WARNING: A new Bicep release is available: v0.40.2. Upgrade now by running \"az bicep upgrade\".\n",
+			want: []types.Diagnostic{
+				{
+					File:     "/src/main.bicep",
+					Line:     3,
+					Column:   7,
+					Severity: types.ErrorSeverity,
+					Code:     "BCP018",
+					Message:  "Expected the \"=\" character at this location.",
+					Link:     "https://aka.ms/bicep/core-diagnostics#BCP018",
+				},
+				{
+					File:     "/src/main.bicep",
+					Line:     1,
+					Column:   7,
+					Severity: types.WarningSeverity,
+					Code:     "no-unused-params",
+					Message:  "Parameter \"location\" is declared but never used.",
+					Link:     "https://aka.ms/bicep/linter/no-unused-params",
+				},
+			},
+		},
+		{
+			name:   "range_without_link",
+			output: `C:\src\my module\main.bicep(10,1)-(10,12) : Info BCP999: Something to know.`,
+			want: []types.Diagnostic{
+				{
+					File:      `C:\src\my module\main.bicep`,
+					Line:      10,
+					Column:    1,
+					EndLine:   10,
+					EndColumn: 12,
+					Severity:  types.InfoSeverity,
+					Code:      "BCP999",
+					Message:   "Something to know.",
+				},
+			},
+		},
+		{
+			name:   "no_diagnostics",
+			output: "Unhandled exception. System.IO.IOException: The process cannot access the file.",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDiagnostics(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiagnostics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsError_Error(t *testing.T) {
+	err := &DiagnosticsError{
+		Diagnostics: []types.Diagnostic{
+			{File: "main.bicep", Line: 1, Column: 7, Severity: types.WarningSeverity, Code: "no-unused-params", Message: "Parameter \"a\" is declared but never used."},
+			{File: "main.bicep", Line: 3, Column: 7, Severity: types.ErrorSeverity, Code: "BCP018", Message: "Expected the \"=\" character at this location."},
+			{File: "main.bicep", Line: 4, Column: 1, Severity: types.ErrorSeverity, Code: "BCP007", Message: "This declaration type is not recognized."},
+		},
+	}
+
+	want := "main.bicep(3,7) : Error BCP018: Expected the \"=\" character at this location.\n" +
+		"main.bicep(4,1) : Error BCP007: This declaration type is not recognized."
+	if got := err.Error(); got != want {
+		t.Errorf("DiagnosticsError.Error() = %q, want %q", got, want)
+	}
+	if got := len(err.Errors()); got != 2 {
+		t.Errorf("len(DiagnosticsError.Errors()) = %d, want 2", got)
+	}
+	if got := err.Warnings(); len(got) != 1 || !strings.HasPrefix(got[0].Message, "Parameter") {
+		t.Errorf("DiagnosticsError.Warnings() = %+v, want the unused parameter warning", got)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package template
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// waitDelay bounds the time to wait for the output pipes of a killed compiler process to close.
+const waitDelay = 5 * time.Second
+
+// configureCommand starts the compiler in its own process group and, on cancellation,
+// kills the whole group. 'az bicep build' runs the Bicep CLI as a child process,
+// which would otherwise outlive the killed 'az' process.
+func configureCommand(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = waitDelay
+}
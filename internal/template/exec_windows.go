@@ -0,0 +1,18 @@
+//go:build windows
+
+package template
+
+import (
+	"os/exec"
+	"time"
+)
+
+// waitDelay bounds the time to wait for the output pipes of a killed compiler process to close.
+const waitDelay = 5 * time.Second
+
+// configureCommand bounds the time to wait for a killed compiler process.
+// On Windows the process is killed by exec.CommandContext; its children
+// are released once the output pipes are closed after waitDelay.
+func configureCommand(cmd *exec.Cmd) {
+	cmd.WaitDelay = waitDelay
+}
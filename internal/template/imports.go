@@ -0,0 +1,239 @@
+package template
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+var (
+	namedImportRegex    = regexp.MustCompile(`^import\s*\{(.*)\}\s*from\s+'([^']+)'`)
+	wildcardImportRegex = regexp.MustCompile(`^import\s+\*\s+as\s+(\S+)\s+from\s+'([^']+)'`)
+	extensionRegex      = regexp.MustCompile(`^extension\s+('[^']+'|\S+)`)
+	extensionAliasRegex = regexp.MustCompile(`\bas\s+(\S+)\s*$`)
+	exportRegex         = regexp.MustCompile(`^@(sys\.)?export\(\s*\)`)
+)
+
+// parseBicepImports parses a Bicep template file and extracts its import statements and extension declarations.
+// The kinds of the imported symbols are not resolved; see resolveImportedSymbols.
+func parseBicepImports(bicepFile string) ([]types.Import, []types.Extension, error) {
+	file, err := os.Open(bicepFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var imports []types.Import
+	var extensions []types.Extension
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Skip comment
+		skipped, err := skipComment(line, scanner)
+		if err != nil {
+			return nil, nil, err
+		}
+		if skipped {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "import"):
+			if imp := parseImport(readStatement(line, scanner)); imp != nil {
+				imports = append(imports, *imp)
+			}
+		case strings.HasPrefix(line, "extension"):
+			if extension := parseExtension(readStatement(line, scanner)); extension != nil {
+				extensions = append(extensions, *extension)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return imports, extensions, nil
+}
+
+// readStatement returns a statement that starts on the given line, joining the following lines into a single line
+// until its brackets are balanced (e.g. a multiline import, extension configuration, or decorator).
+func readStatement(line string, scanner *bufio.Scanner) string {
+	statement := strings.TrimSpace(line)
+	depth := bracketDepth(statement)
+	for depth > 0 && scanner.Scan() {
+		line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		statement += " " + line
+		depth += bracketDepth(line)
+	}
+	return statement
+}
+
+// bracketDepth returns the number of opening brackets minus the number of closing brackets of a line.
+func bracketDepth(line string) int {
+	depth := 0
+	for _, r := range line {
+		switch r {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		}
+	}
+	return depth
+}
+
+// parseImport parses an import statement and returns a pointer to a types.Import struct.
+// If the statement is not a named or wildcard import, it returns nil.
+func parseImport(statement string) *types.Import {
+	if matches := wildcardImportRegex.FindStringSubmatch(statement); matches != nil {
+		return &types.Import{Source: matches[2], Wildcard: matches[1]}
+	}
+
+	matches := namedImportRegex.FindStringSubmatch(statement)
+	if matches == nil {
+		return nil
+	}
+	// The symbols are separated by commas or newlines (joined by spaces), e.g. "a, b as c" or "a b as c"
+	imp := &types.Import{Source: matches[2]}
+	fields := strings.Fields(strings.ReplaceAll(matches[1], ",", " "))
+	for i := 0; i < len(fields); i++ {
+		symbol := types.ImportedSymbol{Name: fields[i]}
+		if i+2 < len(fields) && fields[i+1] == "as" {
+			symbol.Alias = fields[i+2]
+			i += 2
+		}
+		imp.Symbols = append(imp.Symbols, symbol)
+	}
+	return imp
+}
+
+// parseExtension parses an extension declaration and returns a pointer to a types.Extension struct.
+// If the statement is not an extension declaration, it returns nil.
+func parseExtension(statement string) *types.Extension {
+	matches := extensionRegex.FindStringSubmatch(statement)
+	if matches == nil {
+		return nil
+	}
+	extension := &types.Extension{
+		Name:         strings.Trim(matches[1], "'"),
+		Configurable: strings.Contains(statement, " with "),
+	}
+	if aliasMatches := extensionAliasRegex.FindStringSubmatch(statement); aliasMatches != nil {
+		extension.Alias = aliasMatches[1]
+	}
+	return extension
+}
+
+// resolveImportedSymbols resolves the kinds of the symbols imported by a template.
+//
+// The symbols of a local source are resolved from the declarations exported by the source file,
+// which also lists the symbols of wildcard imports. The other symbols are resolved from the
+// user-defined data types, user-defined functions, and variables of the template with the same name.
+func resolveImportedSymbols(template *types.Template) {
+	for i := range template.Imports {
+		imp := &template.Imports[i]
+
+		var exports []types.ImportedSymbol
+		if imp.IsLocal() {
+			exports = parseExports(filepath.Join(filepath.Dir(template.FileName), imp.Source))
+		}
+
+		if imp.Wildcard != "" {
+			imp.Symbols = exports
+			continue
+		}
+
+		for j := range imp.Symbols {
+			symbol := &imp.Symbols[j]
+			for _, export := range exports {
+				if export.Name == symbol.Name {
+					symbol.Kind = export.Kind
+				}
+			}
+			if symbol.Kind == "" {
+				symbol.Kind = templateSymbolKind(template, symbol)
+			}
+		}
+	}
+}
+
+// templateSymbolKind returns the kind of the declaration of a template under which an imported symbol is available.
+// It returns an empty SymbolKind if there is none.
+func templateSymbolKind(template *types.Template, symbol *types.ImportedSymbol) types.SymbolKind {
+	name := symbol.Name
+	if symbol.Alias != "" {
+		name = symbol.Alias
+	}
+	for i := range template.UserDefinedDataTypes {
+		if template.UserDefinedDataTypes[i].Name == name {
+			return types.TypeSymbol
+		}
+	}
+	for i := range template.UserDefinedFunctions {
+		if template.UserDefinedFunctions[i].Name == name {
+			return types.FunctionSymbol
+		}
+	}
+	for i := range template.Variables {
+		if template.Variables[i].Name == name {
+			return types.VariableSymbol
+		}
+	}
+	return ""
+}
+
+// parseExports parses a Bicep file and returns the types, functions, and variables
+// it exports with the @export() decorator, in declaration order.
+// It returns nil if the file cannot be read.
+func parseExports(bicepFile string) []types.ImportedSymbol {
+	file, err := os.Open(bicepFile)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var exports []types.ImportedSymbol
+	exported := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if skipped, err := skipComment(line, scanner); err != nil || skipped {
+			continue
+		}
+		if parseDescription(line, scanner) != nil {
+			continue
+		}
+
+		switch {
+		case exportRegex.MatchString(line):
+			exported = true
+			continue
+		case strings.HasPrefix(line, "@"):
+			readStatement(line, scanner)
+			continue
+		}
+
+		if exported {
+			if matches := typeRegex.FindStringSubmatch(line); matches != nil {
+				exports = append(exports, types.ImportedSymbol{Name: matches[1], Kind: types.TypeSymbol})
+			} else if matches := funcRegex.FindStringSubmatch(line); matches != nil {
+				exports = append(exports, types.ImportedSymbol{Name: matches[1], Kind: types.FunctionSymbol})
+			} else if matches := variableRegex.FindStringSubmatch(line); matches != nil {
+				exports = append(exports, types.ImportedSymbol{Name: matches[1], Kind: types.VariableSymbol})
+			}
+		}
+		exported = false
+	}
+	return exports
+}
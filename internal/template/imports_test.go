@@ -0,0 +1,101 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_parseBicepImports(t *testing.T) {
+	template := &types.Template{
+		FileName:             "testdata/imports.bicep",
+		UserDefinedDataTypes: []types.UserDefinedDataType{{Name: "subnetType"}},
+	}
+
+	var err error
+	template.Imports, template.Extensions, err = parseBicepImports(template.FileName)
+	if err != nil {
+		t.Fatalf("parseBicepImports() error = %v", err)
+	}
+	resolveImportedSymbols(template)
+
+	wantImports := []types.Import{
+		{
+			Source: "shared/main.bicep",
+			Symbols: []types.ImportedSymbol{
+				{Name: "storageSku", Kind: types.TypeSymbol},
+				{Name: "buildName", Alias: "name", Kind: types.FunctionSymbol},
+			},
+		},
+		{
+			Source: "shared/types.bicep",
+			Symbols: []types.ImportedSymbol{
+				{Name: "tagsType", Kind: types.TypeSymbol},
+				{Name: "defaultTags", Kind: types.VariableSymbol},
+			},
+		},
+		{
+			Source:   "shared/types.bicep",
+			Wildcard: "shared",
+			Symbols: []types.ImportedSymbol{
+				{Name: "tagsType", Kind: types.TypeSymbol},
+				{Name: "defaultTags", Kind: types.VariableSymbol},
+			},
+		},
+		{
+			Source:  "br/modules:network/types:1.0.0",
+			Symbols: []types.ImportedSymbol{{Name: "subnetType", Kind: types.TypeSymbol}},
+		},
+		{
+			Source:   "br:myacr.azurecr.io/bicep/common:2.0.0",
+			Wildcard: "common",
+		},
+	}
+	if !reflect.DeepEqual(template.Imports, wantImports) {
+		t.Errorf("parseBicepImports() imports = %+v, want %+v", template.Imports, wantImports)
+	}
+
+	wantExtensions := []types.Extension{
+		{Name: "microsoftGraphV1"},
+		{Name: "br:mcr.microsoft.com/bicep/extensions/microsoftgraph/v1.0:0.1.8-preview", Alias: "graph"},
+		{Name: "kubernetes", Alias: "k8s", Configurable: true},
+	}
+	if !reflect.DeepEqual(template.Extensions, wantExtensions) {
+		t.Errorf("parseBicepImports() extensions = %+v, want %+v", template.Extensions, wantExtensions)
+	}
+}
+
+func Test_parseImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      *types.Import
+	}{
+		{
+			name:      "named",
+			statement: "import {a, b as c} from 'shared.bicep'",
+			want: &types.Import{
+				Source:  "shared.bicep",
+				Symbols: []types.ImportedSymbol{{Name: "a"}, {Name: "b", Alias: "c"}},
+			},
+		},
+		{
+			name:      "wildcard",
+			statement: "import * as shared from 'shared.bicep'",
+			want:      &types.Import{Source: "shared.bicep", Wildcard: "shared"},
+		},
+		{
+			name:      "legacy_provider_import",
+			statement: "import 'az@1.0.0'",
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseImport(tt.statement); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseImport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
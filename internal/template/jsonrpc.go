@@ -0,0 +1,440 @@
+package template
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// JSON-RPC methods exposed by 'bicep jsonrpc'.
+const (
+	compileMethod       = "bicep/compile"       // compileMethod compiles a Bicep file
+	compileParamsMethod = "bicep/compileParams" // compileParamsMethod compiles a parameters file (.bicepparam)
+	versionMethod       = "bicep/version"       // versionMethod returns the version of the Bicep CLI
+)
+
+// JSONRPCCompiler is a Compiler that keeps one 'bicep jsonrpc --stdio' process alive
+// and compiles every Bicep file through it, so that the .NET startup cost is paid only once.
+//
+// Path is the path of the Bicep CLI binary; if empty, it is located by findBicepBinary.
+// The process is started on the first build and stopped by Close.
+// Builds may be issued concurrently; they are multiplexed over the same process.
+type JSONRPCCompiler struct {
+	Path string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *rpcClient
+}
+
+// compileRequest is the request of the 'bicep/compile' method.
+type compileRequest struct {
+	Path string `json:"path"`
+}
+
+// compileResponse is the response of the 'bicep/compile' method.
+type compileResponse struct {
+	Success     bool                `json:"success"`
+	Diagnostics []compileDiagnostic `json:"diagnostics"`
+	Contents    *string             `json:"contents"`
+}
+
+// compileParamsRequest is the request of the 'bicep/compileParams' method.
+type compileParamsRequest struct {
+	Path               string         `json:"path"`
+	ParameterOverrides map[string]any `json:"parameterOverrides"`
+}
+
+// compileParamsResponse is the response of the 'bicep/compileParams' method.
+type compileParamsResponse struct {
+	Success     bool                `json:"success"`
+	Diagnostics []compileDiagnostic `json:"diagnostics"`
+	Parameters  *string             `json:"parameters"`
+}
+
+// compileDiagnostic is a diagnostic of the 'bicep/compile' response.
+// The positions of its range are 0-based.
+type compileDiagnostic struct {
+	Source string `json:"source"`
+	Range  struct {
+		Start rpcPosition `json:"start"`
+		End   rpcPosition `json:"end"`
+	} `json:"range"`
+	Level   string `json:"level"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcPosition is a 0-based position in a file.
+type rpcPosition struct {
+	Line int `json:"line"`
+	Char int `json:"char"`
+}
+
+// versionResponse is the response of the 'bicep/version' method.
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+// Build compiles a Bicep file through the long-running 'bicep jsonrpc' process,
+// starting it if needed.
+func (c *JSONRPCCompiler) Build(ctx context.Context, bicepFile string) ([]byte, []types.Diagnostic, error) {
+	if filepath.Ext(bicepFile) != ".bicep" {
+		return nil, nil, fmt.Errorf("file extension must be '.bicep'")
+	}
+	path, err := filepath.Abs(bicepFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := c.start()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response compileResponse
+	if err := client.call(ctx, compileMethod, compileRequest{Path: path}, &response); err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("build canceled: %w", ctx.Err())
+		}
+		return nil, nil, err
+	}
+
+	diagnostics := convertDiagnostics(bicepFile, path, response.Diagnostics)
+	if !response.Success || response.Contents == nil {
+		if len(diagnostics) == 0 {
+			return nil, nil, errors.New("compilation failed")
+		}
+		return nil, nil, &DiagnosticsError{Diagnostics: diagnostics}
+	}
+	return []byte(*response.Contents), diagnostics, nil
+}
+
+// ValidateParams compiles a parameters file through the long-running 'bicep jsonrpc' process,
+// starting it if needed, and discards the resulting parameters.
+func (c *JSONRPCCompiler) ValidateParams(ctx context.Context, paramsFile string) ([]types.Diagnostic, error) {
+	if filepath.Ext(paramsFile) != ".bicepparam" {
+		return nil, fmt.Errorf("file extension must be '.bicepparam'")
+	}
+	path, err := filepath.Abs(paramsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := c.start()
+	if err != nil {
+		return nil, err
+	}
+
+	var response compileParamsResponse
+	request := compileParamsRequest{Path: path, ParameterOverrides: map[string]any{}}
+	if err := client.call(ctx, compileParamsMethod, request, &response); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("validation canceled: %w", ctx.Err())
+		}
+		return nil, err
+	}
+
+	diagnostics := convertDiagnostics(paramsFile, path, response.Diagnostics)
+	if !response.Success || response.Parameters == nil {
+		if len(diagnostics) == 0 {
+			return nil, errors.New("compilation failed")
+		}
+		return nil, &DiagnosticsError{Diagnostics: diagnostics}
+	}
+	return diagnostics, nil
+}
+
+// convertDiagnostics converts the diagnostics of a 'bicep jsonrpc' response, whose positions are 0-based.
+// The file of a diagnostic is its source (e.g. a module referenced by the compiled file), or the compiled file,
+// as given, if the source is unknown or is the compiled file itself (its absolute path).
+func convertDiagnostics(file, path string, compileDiagnostics []compileDiagnostic) []types.Diagnostic {
+	diagnostics := make([]types.Diagnostic, len(compileDiagnostics))
+	for i, d := range compileDiagnostics {
+		source := file
+		if d.Source != "" && d.Source != path {
+			source = d.Source
+		}
+		diagnostics[i] = types.Diagnostic{
+			File:      source,
+			Line:      d.Range.Start.Line + 1,
+			Column:    d.Range.Start.Char + 1,
+			EndLine:   d.Range.End.Line + 1,
+			EndColumn: d.Range.End.Char + 1,
+			Severity:  types.DiagnosticSeverity(d.Level),
+			Code:      d.Code,
+			Message:   d.Message,
+		}
+	}
+	return diagnostics
+}
+
+// Version returns the version of the Bicep CLI running the 'bicep jsonrpc' process, starting it if needed.
+func (c *JSONRPCCompiler) Version(ctx context.Context) (Version, error) {
+	client, err := c.start()
+	if err != nil {
+		return Version{}, err
+	}
+
+	var response versionResponse
+	if err := client.call(ctx, versionMethod, struct{}{}, &response); err != nil {
+		return Version{}, err
+	}
+	version, err := parseVersion(response.Version)
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to read the Bicep CLI version: %w", err)
+	}
+	return Version{Bicep: version}, nil
+}
+
+// Close stops the 'bicep jsonrpc' process, if it was started.
+func (c *JSONRPCCompiler) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cmd == nil {
+		return nil
+	}
+
+	// Closing stdin lets the server exit gracefully; kill it if it does not.
+	c.client.close()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.cmd.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(waitDelay):
+		_ = c.cmd.Process.Kill()
+		<-done
+	}
+	c.cmd, c.client = nil, nil
+	return nil
+}
+
+// start starts the 'bicep jsonrpc --stdio' process, unless it is already running,
+// and returns the client connected to it.
+func (c *JSONRPCCompiler) start() (*rpcClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	bicep := c.Path
+	if bicep == "" {
+		var err error
+		if bicep, err = findBicepBinary(); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.Command(bicep, "jsonrpc", "--stdio")
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start 'bicep jsonrpc': %w", err)
+	}
+
+	c.cmd = cmd
+	c.client = newRPCClient(stdout, stdin)
+	return c.client, nil
+}
+
+// findBicepBinary returns the path of the Bicep CLI: either 'bicep' from the system's PATH
+// or the copy installed by the Azure CLI.
+func findBicepBinary() (string, error) {
+	if path, err := exec.LookPath("bicep"); err == nil {
+		return path, nil
+	}
+	return azureCLIBicepPath()
+}
+
+// azureCLIBicepPath returns the path of the Bicep CLI installed by the Azure CLI ('az bicep install') in ~/.azure/bin.
+func azureCLIBicepPath() (string, error) {
+	name := "bicep"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, ".azure", "bin", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("the jsonrpc backend requires the 'bicep' command or the Bicep CLI installed by the Azure CLI")
+}
+
+// rpcClient is a JSON-RPC 2.0 client that frames its messages with Content-Length headers,
+// the format used by 'bicep jsonrpc'.
+// Responses are dispatched to the pending calls by a background reader, so that calls can be concurrent.
+type rpcClient struct {
+	writeMu sync.Mutex
+	w       io.WriteCloser
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan rpcResponse
+	err     error // set once the connection is broken
+}
+
+// rpcRequest is a JSON-RPC 2.0 request.
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newRPCClient returns a client that writes requests to w and reads responses from r.
+func newRPCClient(r io.Reader, w io.WriteCloser) *rpcClient {
+	c := &rpcClient{
+		w:       w,
+		pending: make(map[int]chan rpcResponse),
+	}
+	go c.readLoop(bufio.NewReader(r))
+	return c
+}
+
+// call sends a request and decodes the result of its response into result.
+// It returns when the response is received, ctx is done, or the connection is broken.
+func (c *rpcClient) call(ctx context.Context, method string, params, result any) error {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	responses := make(chan rpcResponse, 1)
+	c.pending[id] = responses
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	_, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	c.writeMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case response, ok := <-responses:
+		if !ok {
+			return c.err
+		}
+		if response.Error != nil {
+			return fmt.Errorf("%s failed: %s", method, response.Error.Message)
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// close closes the request stream, which tells the server to exit.
+func (c *rpcClient) close() {
+	c.w.Close()
+}
+
+// readLoop reads the responses and dispatches them to the pending calls until the stream ends.
+// When it ends, every pending and future call fails.
+func (c *rpcClient) readLoop(r *bufio.Reader) {
+	var err error
+	for {
+		var body []byte
+		body, err = readMessage(r)
+		if err != nil {
+			break
+		}
+
+		var response rpcResponse
+		if json.Unmarshal(body, &response) != nil || response.ID == nil {
+			continue // Ignore notifications and malformed messages
+		}
+
+		c.mu.Lock()
+		if responses, ok := c.pending[*response.ID]; ok {
+			responses <- response
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	c.err = fmt.Errorf("'bicep jsonrpc' connection closed: %w", err)
+	for id, responses := range c.pending {
+		close(responses)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+}
+
+// readMessage reads one message framed with a Content-Length header and returns its body.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break // End of the headers
+		}
+		name, value, found := strings.Cut(line, ":")
+		if found && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header %q", line)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,258 @@
+package template
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// fakeJSONRPCServer serves 'bicep/compile', 'bicep/compileParams', and 'bicep/version' requests like 'bicep jsonrpc --stdio'.
+// Files named "invalid.bicep" or "invalid.bicepparam" fail to compile, files named "hang.bicep" never get a response,
+// and any other method returns a JSON-RPC error.
+func fakeJSONRPCServer(t *testing.T, r io.Reader, w io.WriteCloser) {
+	t.Helper()
+	var writeMu sync.Mutex
+	reader := bufio.NewReader(r)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			w.Close()
+			return
+		}
+
+		var request struct {
+			ID     int            `json:"id"`
+			Method string         `json:"method"`
+			Params compileRequest `json:"params"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request %q: %v", body, err)
+			continue
+		}
+
+		var response string
+		switch {
+		case request.Method == versionMethod:
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"version":"0.38.33"}}`, request.ID)
+		case request.Method == compileParamsMethod && filepath.Base(request.Params.Path) == "invalid.bicepparam":
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"success":false,"diagnostics":[`+
+				`{"source":%q,"range":{"start":{"line":2,"char":6},"end":{"line":2,"char":10}},"level":"Error","code":"BCP033","message":"Expected a value of type \"int\" but the provided value is of type \"'x'\"."}]}}`, request.ID, request.Params.Path)
+		case request.Method == compileParamsMethod:
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"success":true,"diagnostics":[],"parameters":"{\"parameters\":{}}"}}`, request.ID)
+		case request.Method != compileMethod:
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"method not found"}}`, request.ID)
+		case filepath.Base(request.Params.Path) == "hang.bicep":
+			continue
+		case filepath.Base(request.Params.Path) == "invalid.bicep":
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"success":false,"diagnostics":[`+
+				`{"source":%q,"range":{"start":{"line":2,"char":6},"end":{"line":2,"char":7}},"level":"Error","code":"BCP018","message":"Expected the \"=\" character at this location."}]}}`, request.ID, request.Params.Path)
+		default:
+			response = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"success":true,"diagnostics":[`+
+				`{"source":%q,"range":{"start":{"line":0,"char":6},"end":{"line":0,"char":14}},"level":"Warning","code":"no-unused-params","message":"Parameter is unused."}],`+
+				`"contents":"{\"parameters\":{}}"}}`, request.ID, request.Params.Path)
+		}
+
+		writeMu.Lock()
+		fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(response), response)
+		writeMu.Unlock()
+	}
+}
+
+// newFakeJSONRPCCompiler returns a JSONRPCCompiler connected to a fakeJSONRPCServer.
+func newFakeJSONRPCCompiler(t *testing.T) *JSONRPCCompiler {
+	t.Helper()
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	go fakeJSONRPCServer(t, serverReader, serverWriter)
+	return &JSONRPCCompiler{client: newRPCClient(clientReader, clientWriter)}
+}
+
+func TestJSONRPCCompiler_Build(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	tests := []struct {
+		name            string
+		bicepFile       string
+		wantTemplate    string
+		wantDiagnostics []types.Diagnostic
+		wantErr         bool
+	}{
+		{
+			name:         "valid_file",
+			bicepFile:    "testdata/basic.bicep",
+			wantTemplate: `{"parameters":{}}`,
+			wantDiagnostics: []types.Diagnostic{
+				{
+					File:      "testdata/basic.bicep",
+					Line:      1,
+					Column:    7,
+					EndLine:   1,
+					EndColumn: 15,
+					Severity:  types.WarningSeverity,
+					Code:      "no-unused-params",
+					Message:   "Parameter is unused.",
+				},
+			},
+		},
+		{
+			name:      "invalid_file",
+			bicepFile: "testdata/invalid.bicep",
+			wantErr:   true,
+		},
+		{
+			name:      "invalid_file_extension",
+			bicepFile: "testdata/main.md",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			armTemplate, diagnostics, err := compiler.Build(context.Background(), tt.bicepFile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSONRPCCompiler.Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(armTemplate) != tt.wantTemplate {
+				t.Errorf("JSONRPCCompiler.Build() template = %q, want %q", armTemplate, tt.wantTemplate)
+			}
+			if tt.wantDiagnostics != nil && !reflect.DeepEqual(diagnostics, tt.wantDiagnostics) {
+				t.Errorf("JSONRPCCompiler.Build() diagnostics = %+v, want %+v", diagnostics, tt.wantDiagnostics)
+			}
+		})
+	}
+}
+
+func Test_convertDiagnostics(t *testing.T) {
+	var compileDiagnostics []compileDiagnostic
+	if err := json.Unmarshal([]byte(`[
+		{"source":"","range":{"start":{"line":0,"char":0},"end":{"line":0,"char":1}},"level":"Warning","code":"a","message":"unknown source"},
+		{"source":"/repo/main.bicep","range":{"start":{"line":1,"char":2},"end":{"line":1,"char":4}},"level":"Warning","code":"b","message":"compiled file"},
+		{"source":"/repo/modules/storage.bicep","range":{"start":{"line":4,"char":0},"end":{"line":4,"char":3}},"level":"Error","code":"c","message":"module"}
+	]`), &compileDiagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := convertDiagnostics("./main.bicep", "/repo/main.bicep", compileDiagnostics)
+	var files []string
+	for _, d := range diagnostics {
+		files = append(files, d.File)
+	}
+	if expected := []string{"./main.bicep", "./main.bicep", "/repo/modules/storage.bicep"}; !reflect.DeepEqual(files, expected) {
+		t.Errorf("convertDiagnostics() files = %q, want %q", files, expected)
+	}
+	if got := diagnostics[2]; got.Line != 5 || got.Column != 1 || got.EndColumn != 4 || got.Severity != types.ErrorSeverity {
+		t.Errorf("convertDiagnostics() module diagnostic = %+v, want 1-based positions and the Error severity", got)
+	}
+}
+
+func TestJSONRPCCompiler_Build_diagnosticsError(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	_, _, err := compiler.Build(context.Background(), "testdata/invalid.bicep")
+	var diagnosticsErr *DiagnosticsError
+	if !errors.As(err, &diagnosticsErr) {
+		t.Fatalf("JSONRPCCompiler.Build() error = %v, want a *DiagnosticsError", err)
+	}
+	want := "testdata/invalid.bicep(3,7) : Error BCP018: Expected the \"=\" character at this location."
+	if got := diagnosticsErr.Error(); got != want {
+		t.Errorf("DiagnosticsError.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestJSONRPCCompiler_Build_concurrent(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := compiler.Build(context.Background(), "testdata/basic.bicep"); err != nil {
+				t.Errorf("JSONRPCCompiler.Build() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestJSONRPCCompiler_Build_canceled(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+
+	_, _, err := compiler.Build(ctx, "testdata/hang.bicep")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("JSONRPCCompiler.Build() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestJSONRPCCompiler_Version(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	got, err := compiler.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if want := (Version{Bicep: "0.38.33"}); got != want {
+		t.Errorf("Version() = %v, want %v", got, want)
+	}
+}
+
+func TestJSONRPCCompiler_ValidateParams(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+	defer compiler.client.close()
+
+	tests := []struct {
+		name       string
+		paramsFile string
+		wantErr    string
+	}{
+		{name: "valid_file", paramsFile: "main.example.bicepparam"},
+		{name: "invalid_file", paramsFile: "invalid.bicepparam", wantErr: "invalid.bicepparam(3,7) : Error BCP033"},
+		{name: "invalid_extension", paramsFile: "main.bicep", wantErr: "file extension must be '.bicepparam'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compiler.ValidateParams(context.Background(), tt.paramsFile)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateParams() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateParams() error = %v, expected to contain = %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_rpcClient_call(t *testing.T) {
+	compiler := newFakeJSONRPCCompiler(t)
+
+	var result any
+	if err := compiler.client.call(context.Background(), "bicep/unknown", struct{}{}, &result); err == nil {
+		t.Errorf("rpcClient.call() error = nil, want the JSON-RPC error of an unknown method")
+	}
+
+	// Once the server has exited, every call fails instead of blocking.
+	compiler.client.close()
+	if err := compiler.client.call(context.Background(), compileMethod, compileRequest{Path: "main.bicep"}, &result); err == nil {
+		t.Errorf("rpcClient.call() error = nil, want an error on a closed connection")
+	}
+}
@@ -0,0 +1,226 @@
+package template
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// Regular expressions to find the declarations of Bicep templates.
+var (
+	declarationRegex = regexp.MustCompile(`^(param|type|var|output|func|module|resource)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	propertyRegex    = regexp.MustCompile(`^\s*(?:'((?:[^'\\]|\\.)*)'|([A-Za-z_][A-Za-z0-9_]*))\??\s*:`)
+	decoratorRegex   = regexp.MustCompile(`^\s*@[A-Za-z_][A-Za-z0-9_.]*\s*\(`)
+)
+
+// propertyKeyword is the keyword of the declarations of the properties of user-defined data types,
+// whose names are "type.property".
+const propertyKeyword = "property"
+
+// declaration identifies a declaration of a Bicep template by its keyword (e.g. "param") and its name.
+type declaration struct {
+	keyword string
+	name    string
+}
+
+// scanDeclarationLines returns the lines (starting at 1) of the declarations of a Bicep template:
+// the top-level declarations (parameters, types, variables, outputs, functions, modules, and resources),
+// and the properties of the object types.
+// Comments and multi-line strings are skipped; a declaration must start at the beginning of its line,
+// possibly after decorators (e.g. "@secure() param password string").
+func scanDeclarationLines(r io.Reader) (map[declaration]int, error) {
+	lines := map[declaration]int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+
+	number := 0
+	inComment, inString := false, false
+	objectType, depth := "", 0
+	for scanner.Scan() {
+		number++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inComment:
+			inComment = !strings.Contains(line, "*/")
+			continue
+		case inString:
+			inString = strings.Count(line, "'''")%2 == 0
+			continue
+		case strings.HasPrefix(trimmed, "//"):
+			continue
+		case strings.HasPrefix(trimmed, "/*"):
+			inComment = !strings.Contains(trimmed[2:], "*/")
+			continue
+		case strings.Count(line, "'''")%2 == 1:
+			inString = true
+			continue
+		}
+
+		if depth == 0 {
+			if strings.HasPrefix(line, "@") {
+				line = strings.TrimLeft(stripDecorators(line), " \t")
+			}
+			matches := declarationRegex.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			lines[declaration{keyword: matches[1], name: matches[2]}] = number
+			if matches[1] != "type" {
+				continue
+			}
+			objectType = matches[2]
+		}
+
+		// The properties of an object type are at the first level of its braces,
+		// possibly on the line of the declaration (e.g. "type tier = { name: string, size: int }")
+		var names []string
+		names, depth = objectProperties(line, depth)
+		for _, name := range names {
+			lines[declaration{keyword: propertyKeyword, name: objectType + "." + name}] = number
+		}
+		if depth <= 0 {
+			objectType, depth = "", 0
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// objectProperties returns the names of the properties declared in a line of an object type, and the number
+// of braces and brackets open after the line, given the number open before it.
+// The properties are at the first level of the braces, separated by commas or line breaks,
+// and may be preceded by decorators; the ones of nested objects are skipped.
+func objectProperties(line string, depth int) ([]string, int) {
+	var names []string
+	var segment strings.Builder
+	// A segment is a property only if it starts at the first level
+	property := depth == 1
+	flush := func() {
+		if property {
+			if matches := propertyRegex.FindStringSubmatch(stripDecorators(segment.String())); matches != nil {
+				names = append(names, matches[1]+matches[2])
+			}
+		}
+		segment.Reset()
+		property = depth == 1
+	}
+
+	inString := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(line):
+			segment.WriteString(line[i : i+2])
+			i++
+			continue
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			flush()
+			return names, depth
+		case c == '{' || c == '[':
+			if depth++; depth == 1 {
+				// The opening brace of the object starts its first property
+				segment.Reset()
+				property = true
+				continue
+			}
+		case c == '}' || c == ']':
+			if depth--; depth == 0 {
+				flush()
+				continue
+			}
+		case c == ',' && depth == 1:
+			flush()
+			continue
+		}
+		if depth > 0 {
+			segment.WriteByte(c)
+		}
+	}
+	flush()
+	return names, depth
+}
+
+// stripDecorators returns a text without its leading decorators, e.g. "@secure() param password string"
+// becomes " param password string". A decorator whose arguments do not end on the same line is kept.
+func stripDecorators(text string) string {
+	for {
+		match := decoratorRegex.FindStringIndex(text)
+		if match == nil {
+			return text
+		}
+		end := closingParenthesis(text, match[1]-1)
+		if end < 0 {
+			return text
+		}
+		text = text[end+1:]
+	}
+}
+
+// closingParenthesis returns the index of the parenthesis closing the one at the given index,
+// ignoring the ones within strings, or -1 if it is not closed.
+func closingParenthesis(text string, open int) int {
+	depth := 0
+	inString := false
+	for i := open; i < len(text); i++ {
+		switch c := text[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// scanDeclarationLinesFromFile returns the lines of the declarations of a Bicep file, see scanDeclarationLines.
+func scanDeclarationLinesFromFile(bicepFile string) (map[declaration]int, error) {
+	file, err := os.Open(bicepFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return scanDeclarationLines(file)
+}
+
+// setDeclarationLines sets the lines of the declarations of a template, see scanDeclarationLines.
+func setDeclarationLines(template *types.Template, lines map[declaration]int) {
+	for i := range template.Modules {
+		template.Modules[i].Line = lines[declaration{"module", template.Modules[i].SymbolicName}]
+	}
+	for i := range template.Resources {
+		template.Resources[i].Line = lines[declaration{"resource", template.Resources[i].SymbolicName}]
+	}
+	for i := range template.Parameters {
+		template.Parameters[i].Line = lines[declaration{"param", template.Parameters[i].Name}]
+	}
+	for i := range template.UserDefinedDataTypes {
+		dataType := &template.UserDefinedDataTypes[i]
+		dataType.Line = lines[declaration{"type", dataType.Name}]
+		for j := range dataType.Properties {
+			dataType.Properties[j].Line = lines[declaration{propertyKeyword, dataType.Name + "." + dataType.Properties[j].Name}]
+		}
+	}
+	for i := range template.Variables {
+		template.Variables[i].Line = lines[declaration{"var", template.Variables[i].Name}]
+	}
+	for i := range template.Outputs {
+		template.Outputs[i].Line = lines[declaration{"output", template.Outputs[i].Name}]
+	}
+	for i := range template.UserDefinedFunctions {
+		template.UserDefinedFunctions[i].Line = lines[declaration{"func", template.UserDefinedFunctions[i].Name}]
+	}
+}
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func Test_scanDeclarationLines(t *testing.T) {
+	bicep := `// A comment
+targetScope = 'resourceGroup'
+
+/*
+param commented string
+*/
+@description('''
+The name of the storage account.
+param inString string
+''')
+param name string
+
+@sealed()
+type config = {
+  @description('The tier { of the account.')
+  tier: 'Hot' | 'Cool'
+  'retention-days'?: int
+  network: {
+    subnet: string
+  }
+}[]
+
+type zone = int
+
+var prefix = 'st'
+
+func buildName(suffix string) string => '${prefix}${suffix}'
+
+resource account 'Microsoft.Storage/storageAccounts@2023-05-01' = {
+  name: name
+}
+
+module network './network.bicep' = {
+  name: 'network'
+}
+
+output id string = account.id
+
+@secure() param password string
+
+@export() @sealed() type tier = { name: string, 'max-size'?: int, limits: { cpu: int, memory: int } }
+
+@description('The tags.') type tags = {
+  @description('The owner, e.g. \'team\'.') owner: string, project: string
+}
+`
+	want := map[declaration]int{
+		{"param", "name"}:                          11,
+		{"type", "config"}:                         14,
+		{propertyKeyword, "config.tier"}:           16,
+		{propertyKeyword, "config.retention-days"}: 17,
+		{propertyKeyword, "config.network"}:        18,
+		{"type", "zone"}:                           23,
+		{"var", "prefix"}:                          25,
+		{"func", "buildName"}:                      27,
+		{"resource", "account"}:                    29,
+		{"module", "network"}:                      33,
+		{"output", "id"}:                           37,
+		{"param", "password"}:                      39,
+		{"type", "tier"}:                           41,
+		{propertyKeyword, "tier.name"}:             41,
+		{propertyKeyword, "tier.max-size"}:         41,
+		{propertyKeyword, "tier.limits"}:           41,
+		{"type", "tags"}:                           43,
+		{propertyKeyword, "tags.owner"}:            44,
+		{propertyKeyword, "tags.project"}:          44,
+	}
+	got, err := scanDeclarationLines(strings.NewReader(bicep))
+	if err != nil {
+		t.Fatalf("scanDeclarationLines() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanDeclarationLines() = %v, want %v", got, want)
+	}
+}
+
+func TestParseTemplates_declarationLines(t *testing.T) {
+	template, err := ParseTemplates("testdata/export_variables.bicep", "testdata/export_variables.json")
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+
+	lines := map[string]int{}
+	for _, variable := range template.Variables {
+		lines[variable.Name] = variable.Line
+	}
+	want := map[string]int{"namePrefix": 6, "defaultTags": 9, "internalValue": 14}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("ParseTemplates() variable lines = %v, want %v", lines, want)
+	}
+	if len(template.Outputs) != 1 || template.Outputs[0].Line != 16 {
+		t.Errorf("ParseTemplates() outputs = %+v, want internal at line 16", template.Outputs)
+	}
+
+	template.SortBy(types.SourceSort)
+	var names []string
+	for _, variable := range template.Variables {
+		names = append(names, variable.Name)
+	}
+	if want := []string{"namePrefix", "defaultTags", "internalValue"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Template.SortBy(SourceSort) variables = %v, want %v", names, want)
+	}
+}
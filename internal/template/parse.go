@@ -7,9 +7,9 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
-	"sort"
 	"strings"
 
 	"github.com/christosgalano/bicep-docs/internal/types"
@@ -19,6 +19,7 @@ import (
 var (
 	moduleRegex                    = regexp.MustCompile(`^module\s+(\S+)\s+'(\S+)'`)
 	resourceRegex                  = regexp.MustCompile(`^resource\s+(\S+)\s+'(\S+)'`)
+	existingResourceRegex          = regexp.MustCompile(`^resource\s+\S+\s+'\S+'\s+existing\b`)
 	typeRegex                      = regexp.MustCompile(`^type\s+(\S+)\s+`)
 	funcRegex                      = regexp.MustCompile(`^func\s+(\S+)\s*\(`)
 	outputRegex                    = regexp.MustCompile(`^output\s+(\S+)\s+`)
@@ -35,23 +36,57 @@ var (
 // It takes the paths to the Bicep file and ARM file as input parameters.
 // The function returns a pointer to the types.Template struct and an error, if any.
 func ParseTemplates(bicepFile, armFile string) (*types.Template, error) {
+	file, err := os.Open(armFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ARM template: failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseTemplate(bicepFile, file)
+}
+
+// ParseTemplate parses the Bicep template and the corresponding ARM template, read from armTemplate,
+// and returns a populated types.Template struct.
+// It is used with the in-memory output of BuildBicepTemplate, e.g. ParseTemplate(bicepFile, bytes.NewReader(arm)).
+// The function returns a pointer to the types.Template struct and an error, if any.
+func ParseTemplate(bicepFile string, armTemplate io.Reader) (*types.Template, error) {
 	var err error
 	var template types.Template
 	template.FileName = bicepFile
 
 	// Parse Bicep template
 	var variables []types.Variable
-	template.Modules, template.Resources, variables, err = parseBicepTemplate(bicepFile)
+	var functionDescriptions map[string]string
+	template.Modules, template.Resources, variables, functionDescriptions, err = parseBicepTemplate(bicepFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Bicep modules: %w", err)
 	}
 
 	// Parse ARM template
-	err = parseArmTemplate(armFile, &template)
+	err = parseArmTemplate(armTemplate, &template)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ARM template: %w", err)
 	}
 
+	// Apply the descriptions of the Bicep source to the user-defined functions without one in the ARM template
+	for i := range template.UserDefinedFunctions {
+		function := &template.UserDefinedFunctions[i]
+		description, ok := functionDescriptions[function.Name]
+		if !ok || (function.Metadata != nil && function.Metadata.Description != nil && *function.Metadata.Description != "") {
+			continue
+		}
+		if function.Metadata == nil {
+			function.Metadata = &types.Metadata{}
+		}
+		function.Metadata.Description = &description
+	}
+
+	// Parse Bicep imports and extensions
+	template.Imports, template.Extensions, err = parseBicepImports(bicepFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Bicep imports: %w", err)
+	}
+
 	// Handle variables that might be optimized away in ARM template
 	if len(variables) > 0 {
 		// If we found variables in Bicep but none in ARM, use the Bicep ones
@@ -75,22 +110,25 @@ func ParseTemplates(bicepFile, armFile string) (*types.Template, error) {
 		}
 	}
 
-	return &template, nil
-}
+	resolveImportedSymbols(&template)
 
-// parseArmTemplate parses the specified ARM template file and populates the provided template struct.
-// It opens the JSON file, decodes the ARM template into the template struct, and returns any errors encountered.
-func parseArmTemplate(armFile string, template *types.Template) error {
-	// Open JSON file
-	file, err := os.Open(armFile)
+	// Record the lines of the declarations, and sort them by name; see types.Template.SortBy for the other orders
+	lines, err := scanDeclarationLinesFromFile(bicepFile)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to parse Bicep declarations: %w", err)
 	}
-	defer file.Close()
+	setDeclarationLines(&template, lines)
+	template.Sort()
+
+	return &template, nil
+}
 
+// parseArmTemplate decodes the ARM template read from r into the provided template struct.
+// It returns any errors encountered.
+func parseArmTemplate(r io.Reader, template *types.Template) error {
 	// Decode ARM template into Template struct
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&template)
+	decoder := json.NewDecoder(r)
+	err := decoder.Decode(&template)
 	if err != nil {
 		return err
 	}
@@ -98,12 +136,13 @@ func parseArmTemplate(armFile string, template *types.Template) error {
 	return nil
 }
 
-// parseBicepTemplate parses a Bicep template file and extracts the modules, resources, and variables defined in the file.
-// It returns the parsed modules, resources, variables, and any error encountered during parsing.
-func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []types.Variable, error) {
+// parseBicepTemplate parses a Bicep template file and extracts the modules, resources, and variables defined in the file,
+// and the descriptions of its user-defined functions by name.
+// It returns the parsed modules, resources, variables, function descriptions, and any error encountered during parsing.
+func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []types.Variable, map[string]string, error) {
 	file, err := os.Open(bicepFile)
 	if err != nil {
-		return []types.Module{}, []types.Resource{}, []types.Variable{}, err
+		return []types.Module{}, []types.Resource{}, []types.Variable{}, map[string]string{}, err
 	}
 	defer file.Close()
 
@@ -111,6 +150,7 @@ func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []t
 	modules := []types.Module{}
 	resources := []types.Resource{}
 	variables := []types.Variable{}
+	functionDescriptions := map[string]string{}
 
 	var description *string
 	var line string
@@ -126,7 +166,7 @@ func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []t
 		// Skip comment
 		skipped, err := skipComment(line, scanner)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		if skipped {
 			continue
@@ -144,7 +184,16 @@ func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []t
 			continue
 		}
 
-		// Ignore the description of parameters, outputs, types, and variables
+		// Record the description of user-defined functions
+		if matches := funcRegex.FindStringSubmatch(line); matches != nil {
+			if pending.description != "" {
+				functionDescriptions[matches[1]] = pending.description
+			}
+			pending = pendingDeclaration{}
+			continue
+		}
+
+		// Ignore the description of parameters, outputs, and types
 		if ignoreDescription(line) {
 			pending = pendingDeclaration{}
 			continue
@@ -158,20 +207,10 @@ func parseBicepTemplate(bicepFile string) ([]types.Module, []types.Resource, []t
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	// Sort the resource symbolic names
-	sort.SliceStable(resources, func(i, j int) bool {
-		return resources[i].SymbolicName < resources[j].SymbolicName
-	})
-
-	// Sort the module symbolic names
-	sort.SliceStable(modules, func(i, j int) bool {
-		return modules[i].SymbolicName < modules[j].SymbolicName
-	})
-
-	return modules, resources, variables, err
+	return modules, resources, variables, functionDescriptions, err
 }
 
 // parseDescription parses a line of text and returns a pointer to the description.
@@ -285,6 +324,7 @@ func parseResource(line string) *types.Resource {
 			SymbolicName: matches[1],
 			Type:         resourceType,
 			Condition:    parseCondition(line),
+			Existing:     existingResourceRegex.MatchString(line),
 		}
 	}
 	return nil
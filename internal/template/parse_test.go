@@ -506,6 +506,7 @@ func Test_parseBicepTemplate(t *testing.T) {
 		wantModules   []types.Module
 		wantResources []types.Resource
 		wantVariables []types.Variable
+		wantFunctions map[string]string
 		wantErr       bool
 	}{
 		{
@@ -551,6 +552,9 @@ func Test_parseBicepTemplate(t *testing.T) {
 					Description: "This is a described variable.",
 				},
 			},
+			wantFunctions: map[string]string{
+				"getDefaultTags": "This is a user defined function.",
+			},
 			wantErr: false,
 		},
 		{
@@ -577,7 +581,7 @@ func Test_parseBicepTemplate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			gotModules, gotResources, gotVariables, err := parseBicepTemplate(tt.args.bicepFile)
+			gotModules, gotResources, gotVariables, gotFunctions, err := parseBicepTemplate(tt.args.bicepFile)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseBicepTemplate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -589,6 +593,11 @@ func Test_parseBicepTemplate(t *testing.T) {
 			compareModules(t, gotModules, tt.wantModules)
 			compareResources(t, gotResources, tt.wantResources)
 			compareVariables(t, gotVariables, tt.wantVariables)
+			if len(gotFunctions) != 0 || len(tt.wantFunctions) != 0 {
+				if !reflect.DeepEqual(gotFunctions, tt.wantFunctions) {
+					t.Errorf("parseBicepTemplate() functions = %v, want %v", gotFunctions, tt.wantFunctions)
+				}
+			}
 		})
 	}
 }
@@ -732,6 +741,17 @@ func Test_parseResource(t *testing.T) {
 				Condition:    "deploy && environment == 'prod'",
 			},
 		},
+		{
+			name: "existing_key_vault",
+			args: args{
+				line: "resource vault 'Microsoft.KeyVault/vaults@2023-07-01' existing = {",
+			},
+			want: &types.Resource{
+				SymbolicName: "vault",
+				Type:         "Microsoft.KeyVault/vaults",
+				Existing:     true,
+			},
+		},
 		{
 			name: "invalid_resource",
 			args: args{
@@ -1119,3 +1139,21 @@ func compareMetadata(t *testing.T, got, want *types.Metadata) {
 		}
 	}
 }
+
+func TestParseTemplates_functions(t *testing.T) {
+	template, err := ParseTemplates("testdata/func_description.bicep", "testdata/func_description.json")
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+	if len(template.UserDefinedFunctions) != 1 {
+		t.Fatalf("ParseTemplates() functions = %+v, want 1 function", template.UserDefinedFunctions)
+	}
+
+	function := template.UserDefinedFunctions[0]
+	if function.Metadata == nil || function.Metadata.Description == nil || *function.Metadata.Description != "This is a user defined function." {
+		t.Errorf("ParseTemplates() function metadata = %+v, want the description of the Bicep source", function.Metadata)
+	}
+	if len(function.Parameters) != 1 || function.Parameters[0].Name != "env" || function.Parameters[0].Type != "string" {
+		t.Errorf("ParseTemplates() function parameters = %+v, want [env string]", function.Parameters)
+	}
+}
@@ -0,0 +1,151 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Minimum versions of the compilers, below which the ARM templates they produce are misread
+// (e.g. 'any' types or the metadata of exported variables).
+const (
+	MinBicepVersion    = "0.38.3"
+	MinAzureCLIVersion = "2.77.0"
+)
+
+// versionRegex matches the first semantic version (e.g. "0.38.33") in the output of a version command.
+var versionRegex = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// Version is the version of the tools used by a Compiler.
+//
+// Bicep is the version of the Bicep CLI (e.g. "0.38.33").
+// AzureCLI is the version of the Azure CLI (e.g. "2.77.0"); it is empty unless the Bicep CLI is run through the Azure CLI.
+// The zero value means that the version is unknown, e.g. for a FixtureCompiler.
+type Version struct {
+	Bicep    string
+	AzureCLI string
+}
+
+// String returns the version in a human-readable format, e.g. "Bicep CLI 0.38.33 (Azure CLI 2.77.0)".
+func (v Version) String() string {
+	if v.Bicep == "" {
+		return ""
+	}
+	if v.AzureCLI == "" {
+		return "Bicep CLI " + v.Bicep
+	}
+	return fmt.Sprintf("Bicep CLI %s (Azure CLI %s)", v.Bicep, v.AzureCLI)
+}
+
+// Check returns an error if the Bicep CLI or the Azure CLI is older than the minimum supported version.
+// An unknown version passes the check.
+func (v Version) Check() error {
+	if v.AzureCLI != "" && compareVersions(v.AzureCLI, MinAzureCLIVersion) < 0 {
+		return fmt.Errorf("unsupported Azure CLI version %s: version %s or later is required (run 'az upgrade')",
+			v.AzureCLI, MinAzureCLIVersion)
+	}
+	if v.Bicep != "" && compareVersions(v.Bicep, MinBicepVersion) < 0 {
+		upgrade := "bicep upgrade"
+		if v.AzureCLI != "" {
+			upgrade = "az bicep upgrade"
+		}
+		return fmt.Errorf("unsupported Bicep CLI version %s: version %s or later is required (run '%s')",
+			v.Bicep, MinBicepVersion, upgrade)
+	}
+	return nil
+}
+
+// parseVersion returns the first semantic version found in the output of a version command.
+func parseVersion(output string) (string, error) {
+	version := versionRegex.FindString(output)
+	if version == "" {
+		return "", fmt.Errorf("no version found in %q", strings.TrimSpace(output))
+	}
+	return version, nil
+}
+
+// compareVersions compares two versions made of dot-separated numbers.
+// It returns -1 if a < b, 0 if a == b, and 1 if a > b. Missing components are treated as 0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i]) //nolint:errcheck // Non-numeric components are treated as 0.
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i]) //nolint:errcheck // Non-numeric components are treated as 0.
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// commandVersion returns the version of the compiler run by the given build command
+// (see bicepBuildCommand and azBuildCommand).
+func commandVersion(ctx context.Context, command []string) (Version, error) {
+	if len(command) > 1 && command[1] == "bicep" {
+		az := command[0]
+		output, err := runVersionCommand(ctx, az, "version", "--output", "json")
+		if err != nil {
+			return Version{}, err
+		}
+		var azVersion struct {
+			AzureCLI string `json:"azure-cli"`
+		}
+		if err := json.Unmarshal(output, &azVersion); err != nil || azVersion.AzureCLI == "" {
+			return Version{}, fmt.Errorf("failed to read the Azure CLI version from %q", strings.TrimSpace(string(output)))
+		}
+
+		output, err = runVersionCommand(ctx, az, "bicep", "version")
+		if err != nil {
+			return Version{}, err
+		}
+		bicep, err := parseVersion(string(output))
+		if err != nil {
+			return Version{}, fmt.Errorf("failed to read the Bicep CLI version: %w", err)
+		}
+		return Version{Bicep: bicep, AzureCLI: azVersion.AzureCLI}, nil
+	}
+
+	output, err := runVersionCommand(ctx, command[0], "--version")
+	if err != nil {
+		return Version{}, err
+	}
+	bicep, err := parseVersion(string(output))
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to read the Bicep CLI version: %w", err)
+	}
+	return Version{Bicep: bicep}, nil
+}
+
+// runVersionCommand runs a version command and returns its stdout.
+func runVersionCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	configureCommand(cmd)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("version check canceled: %w", ctx.Err())
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.New(message)
+		}
+		return nil, fmt.Errorf("failed to run '%s %s': %w", name, strings.Join(args, " "), err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestVersion_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		version Version
+		wantErr bool
+	}{
+		{name: "unknown", version: Version{}},
+		{name: "minimum_bicep", version: Version{Bicep: "0.38.3"}},
+		{name: "newer_bicep", version: Version{Bicep: "0.39.26"}},
+		{name: "older_bicep", version: Version{Bicep: "0.26.54"}, wantErr: true},
+		{name: "minimum_az", version: Version{Bicep: "0.38.33", AzureCLI: "2.77.0"}},
+		{name: "older_az", version: Version{Bicep: "0.38.33", AzureCLI: "2.61.0"}, wantErr: true},
+		{name: "older_bicep_through_az", version: Version{Bicep: "0.30.23", AzureCLI: "2.77.0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.version.Check(); (err != nil) != tt.wantErr {
+				t.Errorf("Version.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		version Version
+		want    string
+	}{
+		{name: "unknown", version: Version{}, want: ""},
+		{name: "bicep", version: Version{Bicep: "0.38.33"}, want: "Bicep CLI 0.38.33"},
+		{name: "az", version: Version{Bicep: "0.38.33", AzureCLI: "2.77.0"}, want: "Bicep CLI 0.38.33 (Azure CLI 2.77.0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.version.String(); got != tt.want {
+				t.Errorf("Version.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "bicep", output: "Bicep CLI version 0.38.33 (8e3a0b3b1a)\n", want: "0.38.33"},
+		{name: "plain", output: "0.38.33", want: "0.38.33"},
+		{name: "no_version", output: "command not found", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVersion(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "0.38.3", b: "0.38.3", want: 0},
+		{a: "0.38.33", b: "0.38.3", want: 1},
+		{a: "0.9.1", b: "0.38.3", want: -1},
+		{a: "1.0", b: "0.38.3", want: 1},
+		{a: "2.77", b: "2.77.0", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecCompiler_Version(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compilers are shell scripts")
+	}
+
+	// Fake Bicep CLI and Azure CLI that only answer version commands.
+	binDir := t.TempDir()
+	bicep := filepath.Join(binDir, "bicep")
+	az := filepath.Join(binDir, "az")
+	scripts := map[string]string{
+		bicep: "#!/bin/sh\necho 'Bicep CLI version 0.38.33 (8e3a0b3b1a)'\n",
+		az: `#!/bin/sh
+if [ "$1" = "version" ]; then
+  echo '{"azure-cli": "2.77.0", "azure-cli-core": "2.77.0", "extensions": {}}'
+else
+  echo 'Bicep CLI version 0.37.4 (a2f6b3c1d0)'
+fi
+`,
+	}
+	for path, script := range scripts {
+		if err := os.WriteFile(path, []byte(script), 0o700); err != nil { //nolint:gosec // The fake compiler must be executable.
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		compiler *ExecCompiler
+		want     Version
+	}{
+		{name: "bicep", compiler: &ExecCompiler{Command: bicepBuildCommand(bicep)}, want: Version{Bicep: "0.38.33"}},
+		{name: "az", compiler: &ExecCompiler{Command: azBuildCommand(az)}, want: Version{Bicep: "0.37.4", AzureCLI: "2.77.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.compiler.Version(context.Background())
+			if err != nil {
+				t.Fatalf("ExecCompiler.Version() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExecCompiler.Version() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
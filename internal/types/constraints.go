@@ -0,0 +1,72 @@
+package types
+
+import "strings"
+
+// DefinitionPrefix is the prefix of a $ref to a user-defined data type in an ARM template, e.g. "#/definitions/subnet".
+const DefinitionPrefix = "#/definitions/"
+
+// DefinitionName returns the name of the user-defined data type referenced by a type or a $ref
+// (e.g. "subnet" for "#/definitions/subnet"), and whether it is such a reference.
+func DefinitionName(ref string) (string, bool) {
+	return strings.CutPrefix(ref, DefinitionPrefix)
+}
+
+// Constraints is the type and the constraints shared by parameters, user-defined data types, and their properties:
+// the type (or the $ref of a user-defined data type), the items of an array, the nullable flag,
+// the allowed values, the length and value constraints, and the metadata.
+type Constraints struct {
+	Type          string
+	Items         *Items
+	Nullable      bool
+	AllowedValues []any
+	MinLength     *int
+	MaxLength     *int
+	MinValue      *int
+	MaxValue      *int
+	Metadata      *Metadata
+}
+
+// Constraints returns the type and the constraints of the parameter.
+func (p *Parameter) Constraints() *Constraints {
+	return &Constraints{
+		Type:          p.Type,
+		Items:         p.Items,
+		Nullable:      p.Nullable,
+		AllowedValues: p.AllowedValues,
+		MinLength:     p.MinLength,
+		MaxLength:     p.MaxLength,
+		MinValue:      p.MinValue,
+		MaxValue:      p.MaxValue,
+		Metadata:      p.Metadata,
+	}
+}
+
+// Constraints returns the underlying type and the constraints of the user-defined data type.
+func (u *UserDefinedDataType) Constraints() *Constraints {
+	return &Constraints{
+		Type:          u.Type,
+		Items:         u.Items,
+		Nullable:      u.Nullable,
+		AllowedValues: u.AllowedValues,
+		MinLength:     u.MinLength,
+		MaxLength:     u.MaxLength,
+		MinValue:      u.MinValue,
+		MaxValue:      u.MaxValue,
+		Metadata:      u.Metadata,
+	}
+}
+
+// Constraints returns the type and the constraints of the property.
+func (p *UserDefinedDataTypeProperty) Constraints() *Constraints {
+	return &Constraints{
+		Type:          p.Type,
+		Items:         p.Items,
+		Nullable:      p.Nullable,
+		AllowedValues: p.AllowedValues,
+		MinLength:     p.MinLength,
+		MaxLength:     p.MaxLength,
+		MinValue:      p.MinValue,
+		MaxValue:      p.MaxValue,
+		Metadata:      p.Metadata,
+	}
+}
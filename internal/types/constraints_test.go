@@ -0,0 +1,63 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefinitionName(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+		ok       bool
+	}{
+		{ref: "#/definitions/subnet", expected: "subnet", ok: true},
+		{ref: "string", expected: "string", ok: false},
+		{ref: "", expected: "", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, ok := DefinitionName(tt.ref)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("DefinitionName() = (%q, %t), expected (%q, %t)", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	minLength, maxValue := 1, 10
+	itemType := "string"
+	metadata := &Metadata{}
+
+	parameter := Parameter{
+		Name:          "names",
+		Type:          "array",
+		Items:         &Items{Type: &itemType},
+		Nullable:      true,
+		AllowedValues: []any{"a", "b"},
+		MinLength:     &minLength,
+		Metadata:      metadata,
+	}
+	expected := &Constraints{
+		Type:          "array",
+		Items:         &Items{Type: &itemType},
+		Nullable:      true,
+		AllowedValues: []any{"a", "b"},
+		MinLength:     &minLength,
+		Metadata:      metadata,
+	}
+	if got := parameter.Constraints(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Parameter.Constraints() = %+v, expected %+v", got, expected)
+	}
+
+	dataType := UserDefinedDataType{Name: "count", Type: "int", MaxValue: &maxValue}
+	if got := dataType.Constraints(); !reflect.DeepEqual(got, &Constraints{Type: "int", MaxValue: &maxValue}) {
+		t.Errorf("UserDefinedDataType.Constraints() = %+v", got)
+	}
+
+	property := UserDefinedDataTypeProperty{Name: "tier", Type: "#/definitions/tier", Nullable: true}
+	if got := property.Constraints(); !reflect.DeepEqual(got, &Constraints{Type: "#/definitions/tier", Nullable: true}) {
+		t.Errorf("UserDefinedDataTypeProperty.Constraints() = %+v", got)
+	}
+}
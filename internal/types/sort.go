@@ -1,32 +1,126 @@
 package types
 
-import "sort"
+import (
+	"errors"
+	"sort"
+	"strings"
+)
 
-// Sort sorts the template's parameters, user defined data types, variables,
+// SortOrder is an enum that represents the orders of the declarations of a template in the generated Markdown file.
+type SortOrder string
+
+const (
+	AlphaSort         SortOrder = "alpha"          // AlphaSort orders the declarations by name (the default)
+	SourceSort        SortOrder = "source"         // SourceSort orders the declarations as they are declared in the Bicep file
+	RequiredFirstSort SortOrder = "required-first" // RequiredFirstSort orders the required parameters first, then by name
+)
+
+// ParseSortOrderFromString converts a string to its corresponding SortOrder enum value.
+func ParseSortOrderFromString(str string) (SortOrder, error) {
+	switch strings.ToLower(str) {
+	case "", "alpha":
+		return AlphaSort, nil
+	case "source":
+		return SourceSort, nil
+	case "required-first":
+		return RequiredFirstSort, nil
+	default:
+		return "", errors.New("invalid sort order: \"" + str + "\"")
+	}
+}
+
+// String returns the string representation of a SortOrder.
+func (s SortOrder) String() string {
+	return string(s)
+}
+
+// Sort sorts the template's modules, resources, parameters, user defined data types, variables,
 // outputs and user defined functions by name.
 func (t *Template) Sort() {
-	sort.Slice(t.Parameters, func(i, j int) bool {
-		return t.Parameters[i].Name < t.Parameters[j].Name
-	})
+	t.SortBy(AlphaSort)
+}
+
+// SortBy sorts the template's modules, resources, parameters and user defined data types (and their properties),
+// variables, outputs and user defined functions in the given order.
+//
+// With SourceSort, the declarations are sorted by their line in the Bicep file;
+// the ones whose line is unknown (e.g. the types imported from another file) follow, by name.
+// With RequiredFirstSort, the required parameters precede the optional ones, and both are sorted by name;
+// the other declarations are sorted by name.
+func (t *Template) SortBy(order SortOrder) {
+	less := func(lines func(int) int, names func(int) string) func(i, j int) bool {
+		if order != SourceSort {
+			return func(i, j int) bool { return names(i) < names(j) }
+		}
+		return func(i, j int) bool {
+			li, lj := lines(i), lines(j)
+			switch {
+			case li == 0 && lj == 0:
+				return names(i) < names(j)
+			case li == 0 || lj == 0:
+				return lj == 0
+			default:
+				return li < lj
+			}
+		}
+	}
+
+	sort.SliceStable(t.Modules, less(
+		func(i int) int { return t.Modules[i].Line },
+		func(i int) string { return t.Modules[i].SymbolicName },
+	))
+
+	sort.SliceStable(t.Resources, less(
+		func(i int) int { return t.Resources[i].Line },
+		func(i int) string { return t.Resources[i].SymbolicName },
+	))
+
+	parameters := less(
+		func(i int) int { return t.Parameters[i].Line },
+		func(i int) string { return t.Parameters[i].Name },
+	)
+	if order == RequiredFirstSort {
+		sorted := parameters
+		parameters = func(i, j int) bool {
+			if ri, rj := t.Parameters[i].IsRequired(), t.Parameters[j].IsRequired(); ri != rj {
+				return ri
+			}
+			return sorted(i, j)
+		}
+	}
+	sort.SliceStable(t.Parameters, parameters)
+	for i := range t.Parameters {
+		properties := t.Parameters[i].Properties
+		sort.SliceStable(properties, less(
+			func(j int) int { return properties[j].Line },
+			func(j int) string { return properties[j].Name },
+		))
+	}
 
-	sort.Slice(t.UserDefinedDataTypes, func(i, j int) bool {
-		return t.UserDefinedDataTypes[i].Name < t.UserDefinedDataTypes[j].Name
-	})
+	sort.SliceStable(t.UserDefinedDataTypes, less(
+		func(i int) int { return t.UserDefinedDataTypes[i].Line },
+		func(i int) string { return t.UserDefinedDataTypes[i].Name },
+	))
 	for i := range t.UserDefinedDataTypes {
-		sort.Slice(t.UserDefinedDataTypes[i].Properties, func(j, k int) bool {
-			return t.UserDefinedDataTypes[i].Properties[j].Name < t.UserDefinedDataTypes[i].Properties[k].Name
-		})
+		properties := t.UserDefinedDataTypes[i].Properties
+		sort.SliceStable(properties, less(
+			func(j int) int { return properties[j].Line },
+			func(j int) string { return properties[j].Name },
+		))
 	}
 
-	sort.Slice(t.Variables, func(i, j int) bool {
-		return t.Variables[i].Name < t.Variables[j].Name
-	})
+	sort.SliceStable(t.Variables, less(
+		func(i int) int { return t.Variables[i].Line },
+		func(i int) string { return t.Variables[i].Name },
+	))
 
-	sort.Slice(t.Outputs, func(i, j int) bool {
-		return t.Outputs[i].Name < t.Outputs[j].Name
-	})
+	sort.SliceStable(t.Outputs, less(
+		func(i int) int { return t.Outputs[i].Line },
+		func(i int) string { return t.Outputs[i].Name },
+	))
 
-	sort.Slice(t.UserDefinedFunctions, func(i, j int) bool {
-		return t.UserDefinedFunctions[i].Name < t.UserDefinedFunctions[j].Name
-	})
+	sort.SliceStable(t.UserDefinedFunctions, less(
+		func(i int) int { return t.UserDefinedFunctions[i].Line },
+		func(i int) string { return t.UserDefinedFunctions[i].Name },
+	))
 }
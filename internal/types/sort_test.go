@@ -0,0 +1,99 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortOrderFromString(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected SortOrder
+		wantErr  bool
+	}{
+		{name: "empty", str: "", expected: AlphaSort},
+		{name: "alpha", str: "alpha", expected: AlphaSort},
+		{name: "source", str: "Source", expected: SourceSort},
+		{name: "required_first", str: "required-first", expected: RequiredFirstSort},
+		{name: "invalid", str: "random", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSortOrderFromString(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSortOrderFromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseSortOrderFromString() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplate_SortBy(t *testing.T) {
+	newTemplate := func() *Template {
+		return &Template{
+			Parameters: []Parameter{
+				{Name: "location", Line: 3, DefaultValue: "westeurope"},
+				{Name: "name", Line: 1},
+				{Name: "imported"},
+				{Name: "tags", Line: 2, Nullable: true},
+				{Name: "sku", Line: 4},
+			},
+			UserDefinedDataTypes: []UserDefinedDataType{
+				{Name: "config", Line: 5, Properties: []UserDefinedDataTypeProperty{
+					{Name: "retention", Line: 7},
+					{Name: "tier", Line: 6},
+				}},
+			},
+			Outputs: []Output{{Name: "id", Line: 9}, {Name: "name", Line: 8}},
+		}
+	}
+	names := func(template *Template) []string {
+		var names []string
+		for _, parameter := range template.Parameters {
+			names = append(names, parameter.Name)
+		}
+		names = append(names, "|")
+		for _, property := range template.UserDefinedDataTypes[0].Properties {
+			names = append(names, property.Name)
+		}
+		names = append(names, "|")
+		for _, output := range template.Outputs {
+			names = append(names, output.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		order    SortOrder
+		expected []string
+	}{
+		{
+			name:     "alpha",
+			order:    AlphaSort,
+			expected: []string{"imported", "location", "name", "sku", "tags", "|", "retention", "tier", "|", "id", "name"},
+		},
+		{
+			name:     "source",
+			order:    SourceSort,
+			expected: []string{"name", "tags", "location", "sku", "imported", "|", "tier", "retention", "|", "name", "id"},
+		},
+		{
+			name:     "required_first",
+			order:    RequiredFirstSort,
+			expected: []string{"imported", "name", "sku", "location", "tags", "|", "retention", "tier", "|", "id", "name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := newTemplate()
+			template.SortBy(tt.order)
+			if got := names(template); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Template.SortBy() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
 )
 
@@ -19,10 +21,92 @@ import (
 // or a metadata item (metadata description = '...').
 //
 // The export flag can be set using the @export() annotation or the metadata item (metadata __bicep_export! = true).
+//
+// The version and the generator are only set in the metadata of the template itself: the version is a metadata item
+// (metadata version = '...') that records the published version of the module, and the generator records the compiler
+// that produced the ARM template.
+//
+// Custom contains every other user-defined entry (e.g. metadata owner = '...'), including the version, in declaration order.
+// For a declaration, it contains the entries of its @metadata({...}) decorator (e.g. example, deprecated, or category).
+// The entries emitted by the compiler (keys starting with '_' or ending with '!') are not included.
 type Metadata struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
-	Export      *bool   `json:"__bicep_export!,omitempty"`
+	Name        *string        `json:"name"`
+	Description *string        `json:"description"`
+	Export      *bool          `json:"__bicep_export!,omitempty"`
+	Version     *string        `json:"version,omitempty"`
+	Generator   *Generator     `json:"_generator,omitempty"`
+	Custom      []MetadataItem `json:"-"`
+}
+
+// MetadataItem is a user-defined metadata entry, with a key and a value of any JSON type.
+type MetadataItem struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// GetCustom returns the value of the custom metadata entry with the given key, and whether it exists.
+func (m *Metadata) GetCustom(key string) (any, bool) {
+	if m == nil {
+		return nil, false
+	}
+	for _, item := range m.Custom {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Tags returns the tags of the custom metadata entry "tags" (e.g. metadata tags = ['network', 'core']),
+// which is either an array of strings or a comma-separated string. Empty tags are ignored.
+func (m *Metadata) Tags() []string {
+	value, _ := m.GetCustom("tags")
+	var tags []string
+	switch v := value.(type) {
+	case string:
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if tag, ok := item.(string); ok && strings.TrimSpace(tag) != "" {
+				tags = append(tags, strings.TrimSpace(tag))
+			}
+		}
+	}
+	return tags
+}
+
+// Deprecated reports whether a declaration is deprecated by the custom metadata entry "deprecated",
+// which is either a flag (e.g. @metadata({ deprecated: true })) or the reason (e.g. @metadata({ deprecated: 'Use sku instead.' })),
+// and returns the reason, if any.
+func (m *Metadata) Deprecated() (string, bool) {
+	value, _ := m.GetCustom("deprecated")
+	switch v := value.(type) {
+	case bool:
+		return "", v
+	case string:
+		return strings.TrimSpace(v), true
+	default:
+		return "", false
+	}
+}
+
+// Category returns the category of the custom metadata entry "category" (e.g. @metadata({ category: 'Networking' })),
+// or an empty string if it is not set or not a string.
+func (m *Metadata) Category() string {
+	value, _ := m.GetCustom("category")
+	category, _ := value.(string)
+	return strings.TrimSpace(category)
+}
+
+// Generator is a struct that contains the information about the compiler that produced an ARM template.
+// It has a name (e.g. "bicep") and a version (e.g. "0.38.33.27573").
+type Generator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
 }
 
 // Items represents the array item type information.
@@ -44,11 +128,65 @@ type Items struct {
 // module network './modules/network/main.bicep'
 //
 // In the example above, the symbolic name is "network" and the source is "./modules/network/main.bicep".
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type Module struct {
 	SymbolicName string
 	Source       string
 	Condition    string
 	Description  string
+	Line         int
+}
+
+// Import is a struct that contains the information about a compile-time import statement.
+// An import has a source, and either a list of imported symbols or a wildcard alias.
+// The source is the path to the exporting Bicep file or the registry reference of the exporting module.
+//
+// Examples:
+// import {storageSku, buildName as name} from 'shared.bicep'
+// import * as shared from 'br/modules:shared:1.0.0'
+//
+// In the first example, the source is "shared.bicep" and the symbols are storageSku and buildName (imported as name).
+// In the second example, the wildcard alias is "shared"; its symbols are the ones exported by the source, if known.
+type Import struct {
+	Source   string
+	Wildcard string
+	Symbols  []ImportedSymbol
+}
+
+// IsLocal reports whether the source of the import is a local Bicep file, as opposed to a registry reference.
+func (i *Import) IsLocal() bool {
+	return !strings.HasPrefix(i.Source, "br:") && !strings.HasPrefix(i.Source, "br/") && !strings.HasPrefix(i.Source, "ts:") && !strings.HasPrefix(i.Source, "ts/")
+}
+
+// ImportedSymbol is a struct that contains the information about a symbol brought in by an import.
+// A symbol has the name under which it is exported, an optional alias under which it is imported,
+// and the kind of declaration it is (user-defined data type, user-defined function, or variable), if known.
+type ImportedSymbol struct {
+	Name  string
+	Alias string
+	Kind  SymbolKind
+}
+
+// SymbolKind is an enum that represents the kinds of declarations that can be exported and imported.
+type SymbolKind string
+
+const (
+	TypeSymbol     SymbolKind = "uddt"
+	FunctionSymbol SymbolKind = "udf"
+	VariableSymbol SymbolKind = "variable"
+)
+
+// Extension is a struct that contains the information about an extension declaration.
+// An extension has a name (an identifier or a registry reference), an optional alias, and whether it has a configuration.
+//
+// Example:
+// extension 'br:mcr.microsoft.com/bicep/extensions/microsoftgraph/v1.0:0.1.8-preview' as graph
+//
+// In the example above, the name is the registry reference and the alias is "graph".
+type Extension struct {
+	Name         string
+	Alias        string
+	Configurable bool
 }
 
 // Resource is a struct that contains the information about a resource.
@@ -60,14 +198,43 @@ type Module struct {
 // The condition is the Bicep expression of a conditional deployment (resource ... = if (condition) {...}).
 // RetryOn holds the arguments of the @retryOn decorator (e.g. "['ServerError'], 3").
 // OnlyIfNotExists indicates whether the resource is annotated with @onlyIfNotExists().
+// Existing indicates whether the resource is a reference to an existing resource (resource ... existing = {...}).
 // The description is an optional description of the resource.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type Resource struct {
 	SymbolicName    string
 	Type            string
 	Condition       string
 	RetryOn         string
 	OnlyIfNotExists bool
+	Existing        bool
 	Description     string
+	Line            int
+}
+
+// Provider returns the resource provider namespace of the resource type (e.g. "Microsoft.Storage").
+// It returns an empty string for types without a namespace (e.g. nested child resources).
+func (r *Resource) Provider() string {
+	namespace, _, found := strings.Cut(r.Type, "/")
+	if !found || !strings.Contains(namespace, ".") {
+		return ""
+	}
+	return namespace
+}
+
+// TargetScope is an enum that represents the deployment scope of a template (targetScope = '...').
+// The scope can be either "resourceGroup", "subscription", "managementGroup", or "tenant".
+type TargetScope string
+
+const (
+	ResourceGroupScope   TargetScope = "resourceGroup"   // ResourceGroupScope is the default scope of a Bicep file
+	SubscriptionScope    TargetScope = "subscription"    // SubscriptionScope deploys to a subscription
+	ManagementGroupScope TargetScope = "managementGroup" // ManagementGroupScope deploys to a management group
+	TenantScope          TargetScope = "tenant"          // TenantScope deploys to the tenant
+)
+
+func (ts TargetScope) String() string {
+	return string(ts)
 }
 
 // ParameterStatus is an enum that represents the status of a parameter.
@@ -87,20 +254,25 @@ func (ps ParameterStatus) String() string {
 // A parameter has a name, type, an optional default value, items (for array types), nullable flag,
 // optional constraints (allowed values, minLength, maxLength, minValue, maxValue),
 // a secure flag (derived from the ARM type "securestring"/"secureObject"),
+// the properties of an inline object type (e.g. param config { tier: string }) and its sealed flag,
 // and an optional metadata part.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type Parameter struct {
-	Name          string    `json:"-"`
-	Type          string    `json:"-"`
-	Secure        bool      `json:"-"`
-	DefaultValue  any       `json:"defaultValue"`
-	Items         *Items    `json:"items"`
-	Nullable      bool      `json:"nullable"`
-	AllowedValues []any     `json:"allowedValues,omitempty"`
-	MinLength     *int      `json:"minLength,omitempty"`
-	MaxLength     *int      `json:"maxLength,omitempty"`
-	MinValue      *int      `json:"minValue,omitempty"`
-	MaxValue      *int      `json:"maxValue,omitempty"`
-	Metadata      *Metadata `json:"metadata"`
+	Name          string                        `json:"-"`
+	Type          string                        `json:"-"`
+	Secure        bool                          `json:"-"`
+	DefaultValue  any                           `json:"defaultValue"`
+	Items         *Items                        `json:"items"`
+	Properties    []UserDefinedDataTypeProperty `json:"-"`
+	Sealed        bool                          `json:"-"`
+	Nullable      bool                          `json:"nullable"`
+	AllowedValues []any                         `json:"allowedValues,omitempty"`
+	MinLength     *int                          `json:"minLength,omitempty"`
+	MaxLength     *int                          `json:"maxLength,omitempty"`
+	MinValue      *int                          `json:"minValue,omitempty"`
+	MaxValue      *int                          `json:"maxValue,omitempty"`
+	Metadata      *Metadata                     `json:"metadata"`
+	Line          int                           `json:"-"`
 }
 
 // IsRequired checks if the parameter is required.
@@ -138,22 +310,25 @@ func (p *Parameter) GetDescription() string {
 
 // UserDefinedDataType (UDDT) is a struct that contains the information about a user defined data type.
 // A user defined data type has a name, type, items (for array types), properties, nullable flag,
-// optional constraints (minLength, maxLength, minValue, maxValue), export flag,
+// optional constraints (allowed values, minLength, maxLength, minValue, maxValue), export flag,
 // a sealed flag (derived from "additionalProperties": false in the ARM definition),
 // and an optional metadata part.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type UserDefinedDataType struct {
-	Name       string                        `json:"-"`
-	Type       string                        `json:"-"`
-	Sealed     bool                          `json:"-"`
-	Items      *Items                        `json:"items"`
-	Properties []UserDefinedDataTypeProperty `json:"-"`
-	Nullable   bool                          `json:"nullable"`
-	MinLength  *int                          `json:"minLength,omitempty"`
-	MaxLength  *int                          `json:"maxLength,omitempty"`
-	MinValue   *int                          `json:"minValue,omitempty"`
-	MaxValue   *int                          `json:"maxValue,omitempty"`
-	Exportable bool                          `json:"-"`
-	Metadata   *Metadata                     `json:"metadata"`
+	Name          string                        `json:"-"`
+	Type          string                        `json:"-"`
+	Sealed        bool                          `json:"-"`
+	Items         *Items                        `json:"items"`
+	Properties    []UserDefinedDataTypeProperty `json:"-"`
+	Nullable      bool                          `json:"nullable"`
+	AllowedValues []any                         `json:"allowedValues,omitempty"`
+	MinLength     *int                          `json:"minLength,omitempty"`
+	MaxLength     *int                          `json:"maxLength,omitempty"`
+	MinValue      *int                          `json:"minValue,omitempty"`
+	MaxValue      *int                          `json:"maxValue,omitempty"`
+	Exportable    bool                          `json:"-"`
+	Metadata      *Metadata                     `json:"metadata"`
+	Line          int                           `json:"-"`
 }
 
 // IsExportable returns true if the user-defined data type is marked as exportable.
@@ -165,6 +340,7 @@ func (u *UserDefinedDataType) IsExportable() bool {
 // A property has a name, type, an optional default value, items (for array types), nullable flag,
 // optional constraints (allowed values, minLength, maxLength, minValue, maxValue)
 // and an optional metadata part.
+// Line is the line of the property in the Bicep file (starting at 1), or 0 if it is unknown.
 type UserDefinedDataTypeProperty struct {
 	Name          string    `json:"-"`
 	Type          string    `json:"-"`
@@ -176,17 +352,20 @@ type UserDefinedDataTypeProperty struct {
 	MinValue      *int      `json:"minValue,omitempty"`
 	MaxValue      *int      `json:"maxValue,omitempty"`
 	Metadata      *Metadata `json:"metadata"`
+	Line          int       `json:"-"`
 }
 
 // UserDefinedFunction (UDF) is a struct that contains the information about a user defined function.
 // A user defined function has a name, a list of parameters, an output, an export flag,
 // and an optional metadata part.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type UserDefinedFunction struct {
 	Name       string      `json:"-"`
 	Parameters []Parameter `json:"parameters"`
 	Output     Output      `json:"output"`
 	Exportable bool        `json:"-"`
 	Metadata   *Metadata   `json:"metadata"`
+	Line       int         `json:"-"`
 }
 
 // IsExportable returns true if the user-defined function is marked as exportable.
@@ -198,15 +377,20 @@ func (u *UserDefinedFunction) IsExportable() bool {
 // A variable has a name, a value, an export flag, and an optional description.
 //
 // The name is the name of the variable.
-// The value is the value of the variable.
+// The value is the value of the variable; for a variable compiled to a copy loop (a for-expression),
+// it is the input of each item and the loop count is the number of items (e.g. "[length(variables('names'))]").
+// The loop count is nil for other variables.
 // The export flag is derived from the template-level metadata item "__bicep_exported_variables!",
 // which lists the variables annotated with @export().
 // The description is an optional description of the variable.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type Variable struct {
 	Name        string `json:"-"`
 	Value       any    `json:"-"`
+	LoopCount   any    `json:"-"`
 	Exportable  bool   `json:"-"`
 	Description string `json:"-"`
+	Line        int    `json:"-"`
 }
 
 // Output is a struct that contains the information about an output.
@@ -214,6 +398,7 @@ type Variable struct {
 // optional constraints (minLength, maxLength, minValue, maxValue),
 // a secure flag (derived from the ARM type "securestring"/"secureObject"),
 // and an optional metadata part.
+// Line is the line of the declaration in the Bicep file (starting at 1), or 0 if it is unknown.
 type Output struct {
 	Name      string    `json:"-"`
 	Type      string    `json:"-"`
@@ -225,14 +410,63 @@ type Output struct {
 	MinValue  *int      `json:"minValue,omitempty"`
 	MaxValue  *int      `json:"maxValue,omitempty"`
 	Metadata  *Metadata `json:"metadata"`
+	Line      int       `json:"-"`
+}
+
+// DiagnosticSeverity is an enum that represents the severity of a Bicep compiler diagnostic.
+// The severity can be either "Error", "Warning", or "Info".
+type DiagnosticSeverity string
+
+const (
+	ErrorSeverity   DiagnosticSeverity = "Error"   // ErrorSeverity indicates that the compilation failed
+	WarningSeverity DiagnosticSeverity = "Warning" // WarningSeverity indicates a warning (e.g. a linter rule violation)
+	InfoSeverity    DiagnosticSeverity = "Info"    // InfoSeverity indicates an informational message
+)
+
+func (ds DiagnosticSeverity) String() string {
+	return string(ds)
+}
+
+// Diagnostic is a struct that contains the information about a Bicep compiler diagnostic.
+// A diagnostic has a file, a range (1-based line and column of its start and, when known, its end),
+// a severity, a code (e.g. "BCP018" or the linter rule name "no-unused-params"),
+// a message, and an optional link to the documentation of the code.
+//
+// Example:
+// /path/main.bicep(3,7) : Warning no-unused-params: Parameter "location" is declared but never used. [https://aka.ms/bicep/linter/no-unused-params]
+type Diagnostic struct {
+	File      string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Severity  DiagnosticSeverity
+	Code      string
+	Message   string
+	Link      string
+}
+
+// String returns the diagnostic in the format used by the Bicep CLI, without the documentation link.
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s(%d,%d) : %s %s: %s", d.File, d.Line, d.Column, d.Severity, d.Code, d.Message)
 }
 
 // Template is a struct that contains the information about a Bicep template.
 //
-// A template has a list of: modules, resources, parameters, user defined data types,
-// user defined functions, variables, outputs and an optional metadata part.
+// A template has a list of: imports, extensions, modules, resources, parameters, user defined data types,
+// user defined functions, variables, outputs, the compiler diagnostics (warnings)
+// reported while building it, and an optional metadata part.
+//
+// The target scope is derived from the $schema of the ARM template; it is empty if the schema is unknown.
+// The compiler version is the version of the compiler that built it (e.g. "Bicep CLI 0.38.33"), if known;
+// it is only rendered in the footer of the documentation.
+// The module reference is the reference of the published module in a registry (e.g. "br/modules:network/vnet:1.0.0"),
+// and the module path is its relative local path (e.g. "./network/vnet/main.bicep"); both are empty unless a registry is configured.
 type Template struct {
 	FileName             string                `json:"-"`
+	TargetScope          TargetScope           `json:"-"`
+	Imports              []Import              `json:"-"`
+	Extensions           []Extension           `json:"-"`
 	Modules              []Module              `json:"-"`
 	Resources            []Resource            `json:"-"`
 	Parameters           []Parameter           `json:"-"`
@@ -240,22 +474,57 @@ type Template struct {
 	UserDefinedFunctions []UserDefinedFunction `json:"-"`
 	Variables            []Variable            `json:"-"`
 	Outputs              []Output              `json:"-"`
+	Diagnostics          []Diagnostic          `json:"-"`
+	CompilerVersion      string                `json:"-"`
+	ModuleReference      string                `json:"-"`
+	ModulePath           string                `json:"-"`
 	Metadata             *Metadata             `json:"metadata"`
 }
 
+// ModuleName returns the name of the module of a template: the name item of its metadata,
+// or otherwise the name of the directory of its Bicep file (e.g. "vnet" for "network/vnet/main.bicep"),
+// or the name of the file itself if it has no directory.
+func (t *Template) ModuleName() string {
+	if t.Metadata != nil && t.Metadata.Name != nil && *t.Metadata.Name != "" {
+		return *t.Metadata.Name
+	}
+	dir := filepath.Base(filepath.Dir(t.FileName))
+	if dir == "." || dir == string(filepath.Separator) {
+		return filepath.Base(t.FileName)
+	}
+	return dir
+}
+
+// Compiler returns the compiler that built a template (e.g. "Bicep CLI 0.38.3"): its CompilerVersion,
+// or otherwise the generator recorded in the ARM template metadata, or "" if it is unknown.
+func (t *Template) Compiler() string {
+	if t.CompilerVersion != "" {
+		return t.CompilerVersion
+	}
+	if t.Metadata != nil && t.Metadata.Generator != nil && t.Metadata.Generator.Version != "" {
+		return "Bicep CLI " + t.Metadata.Generator.Version
+	}
+	return ""
+}
+
 // Section is an enum that represents the different sections of the generated Markdown file.
 type Section string
 
 const (
 	DescriptionSection          Section = "description"
+	MetadataSection             Section = "metadata"
 	UsageSection                Section = "usage"
+	ImportsSection              Section = "imports"
 	ModulesSection              Section = "modules"
 	ResourcesSection            Section = "resources"
+	ProvidersSection            Section = "providers"
 	ParametersSection           Section = "parameters"
 	UserDefinedDataTypesSection Section = "uddts"
 	UserDefinedFunctionsSection Section = "udfs"
 	VariablesSection            Section = "variables"
 	OutputsSection              Section = "outputs"
+	DiagnosticsSection          Section = "diagnostics"
+	TableOfContentsSection      Section = "toc"
 )
 
 // ParseSectionFromString converts a string to its corresponding Section enum value.
@@ -263,12 +532,18 @@ func ParseSectionFromString(str string) (Section, error) {
 	switch strings.ToLower(str) {
 	case "description":
 		return DescriptionSection, nil
+	case "metadata":
+		return MetadataSection, nil
 	case "usage":
 		return UsageSection, nil
+	case "imports":
+		return ImportsSection, nil
 	case "modules":
 		return ModulesSection, nil
 	case "resources":
 		return ResourcesSection, nil
+	case "providers":
+		return ProvidersSection, nil
 	case "parameters":
 		return ParametersSection, nil
 	case "uddts":
@@ -279,6 +554,10 @@ func ParseSectionFromString(str string) (Section, error) {
 		return VariablesSection, nil
 	case "outputs":
 		return OutputsSection, nil
+	case "diagnostics":
+		return DiagnosticsSection, nil
+	case "toc":
+		return TableOfContentsSection, nil
 	default:
 		return "", errors.New("invalid section: \"" + str + "\"")
 	}
@@ -288,3 +567,32 @@ func ParseSectionFromString(str string) (Section, error) {
 func (s Section) String() string {
 	return string(s)
 }
+
+// Decorator is an enum that represents the groups of decorators that can be shown in the tables of the generated Markdown file.
+type Decorator string
+
+const (
+	AllowedDecorator    Decorator = "allowed"    // AllowedDecorator is @allowed
+	LengthDecorator     Decorator = "length"     // LengthDecorator is @minLength and @maxLength
+	ValueDecorator      Decorator = "value"      // ValueDecorator is @minValue and @maxValue
+	SealedDecorator     Decorator = "sealed"     // SealedDecorator is @sealed
+	ExportableDecorator Decorator = "exportable" // ExportableDecorator is @export
+)
+
+// AllDecorators contains every group of decorators.
+var AllDecorators = []Decorator{AllowedDecorator, LengthDecorator, ValueDecorator, SealedDecorator, ExportableDecorator}
+
+// ParseDecoratorFromString converts a string to its corresponding Decorator enum value.
+func ParseDecoratorFromString(str string) (Decorator, error) {
+	for _, decorator := range AllDecorators {
+		if strings.EqualFold(str, string(decorator)) {
+			return decorator, nil
+		}
+	}
+	return "", errors.New("invalid decorator: \"" + str + "\"")
+}
+
+// String returns the string representation of a Decorator.
+func (d Decorator) String() string {
+	return string(d)
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetadata_Tags(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		expected []string
+	}{
+		{name: "nil_metadata", metadata: nil, expected: nil},
+		{name: "no_tags", metadata: &Metadata{}, expected: nil},
+		{
+			name:     "array",
+			metadata: &Metadata{Custom: []MetadataItem{{Key: "tags", Value: []any{"network", " core ", "", 42.0}}}},
+			expected: []string{"network", "core"},
+		},
+		{
+			name:     "comma_separated",
+			metadata: &Metadata{Custom: []MetadataItem{{Key: "tags", Value: "network, core,"}}},
+			expected: []string{"network", "core"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metadata.Tags(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Metadata.Tags() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetadata_Category(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		expected string
+	}{
+		{name: "nil_metadata", metadata: nil, expected: ""},
+		{name: "no_category", metadata: &Metadata{}, expected: ""},
+		{name: "string", metadata: &Metadata{Custom: []MetadataItem{{Key: "category", Value: " Networking "}}}, expected: "Networking"},
+		{name: "not_string", metadata: &Metadata{Custom: []MetadataItem{{Key: "category", Value: 42.0}}}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metadata.Category(); got != tt.expected {
+				t.Errorf("Metadata.Category() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetadata_Deprecated(t *testing.T) {
+	tests := []struct {
+		name       string
+		metadata   *Metadata
+		reason     string
+		deprecated bool
+	}{
+		{name: "nil_metadata", metadata: nil},
+		{name: "no_deprecated", metadata: &Metadata{}},
+		{name: "flag", metadata: &Metadata{Custom: []MetadataItem{{Key: "deprecated", Value: true}}}, deprecated: true},
+		{name: "false", metadata: &Metadata{Custom: []MetadataItem{{Key: "deprecated", Value: false}}}},
+		{
+			name:       "reason",
+			metadata:   &Metadata{Custom: []MetadataItem{{Key: "deprecated", Value: " Use sku instead. "}}},
+			reason:     "Use sku instead.",
+			deprecated: true,
+		},
+		{name: "not_flag_or_reason", metadata: &Metadata{Custom: []MetadataItem{{Key: "deprecated", Value: 1.0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, deprecated := tt.metadata.Deprecated()
+			if reason != tt.reason || deprecated != tt.deprecated {
+				t.Errorf("Metadata.Deprecated() = (%q, %v), expected (%q, %v)", reason, deprecated, tt.reason, tt.deprecated)
+			}
+		})
+	}
+}
+
+func TestTemplate_ModuleName(t *testing.T) {
+	name := "Virtual Network"
+	tests := []struct {
+		name     string
+		template *Template
+		expected string
+	}{
+		{name: "metadata_name", template: &Template{FileName: "network/vnet/main.bicep", Metadata: &Metadata{Name: &name}}, expected: "Virtual Network"},
+		{name: "directory", template: &Template{FileName: "network/vnet/main.bicep"}, expected: "vnet"},
+		{name: "file", template: &Template{FileName: "main.bicep"}, expected: "main.bicep"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.template.ModuleName(); got != tt.expected {
+				t.Errorf("Template.ModuleName() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplate_Compiler(t *testing.T) {
+	generator := &Metadata{Generator: &Generator{Name: "bicep", Version: "0.38.33.27573"}}
+	tests := []struct {
+		name     string
+		template *Template
+		expected string
+	}{
+		{name: "compiler_version", template: &Template{CompilerVersion: "Bicep CLI 0.38.3", Metadata: generator}, expected: "Bicep CLI 0.38.3"},
+		{name: "generator", template: &Template{Metadata: generator}, expected: "Bicep CLI 0.38.33.27573"},
+		{name: "unknown", template: &Template{}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.template.Compiler(); got != tt.expected {
+				t.Errorf("Template.Compiler() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -44,12 +44,68 @@ func unmarshalTypeOrRef(data []byte) (string, error) {
 	return "any", nil
 }
 
+// UnmarshalJSON unmarshals a JSON object into a Metadata.
+// The entries other than the known fields and the ones emitted by the compiler
+// are collected in Custom, in the order in which they appear.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type Alias Metadata
+	if err := json.Unmarshal(data, (*Alias)(m)); err != nil {
+		return err
+	}
+
+	m.Custom = nil
+	iter := jsoniter.ParseBytes(json, data)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		if !isCustomMetadataKey(key) {
+			iter.Skip()
+			return true
+		}
+		m.Custom = append(m.Custom, MetadataItem{Key: key, Value: iter.Read()})
+		return true
+	})
+	return iter.Error
+}
+
+// MarshalJSON marshals a Metadata into a JSON object, with the custom entries alongside the known fields.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	type Alias Metadata
+	data, err := json.Marshal(Alias(m))
+	if err != nil || len(m.Custom) == 0 {
+		return data, err
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for _, item := range m.Custom {
+		object[item.Key] = item.Value
+	}
+	return json.Marshal(object)
+}
+
+// isCustomMetadataKey reports whether a metadata key is a user-defined entry,
+// as opposed to the name and description, and the entries emitted by the compiler
+// (e.g. "_generator" or "__bicep_export!").
+func isCustomMetadataKey(key string) bool {
+	switch {
+	case key == "name", key == "description":
+		return false
+	case strings.HasPrefix(key, "_"), strings.HasSuffix(key, "!"):
+		return false
+	default:
+		return true
+	}
+}
+
 // UnmarshalJSON unmarshals a JSON object into a Parameter.
 // The type field can be either a type or a $ref.
 // Secure is derived from the ARM type: "securestring" and "secureObject" map to Secure=true.
+// The name field is only present in the parameters of user-defined functions, which are a list instead of an object.
 func (p *Parameter) UnmarshalJSON(data []byte) error {
 	type Alias Parameter
 	aux := &struct {
+		Name string `json:"name"`
 		*Alias
 	}{
 		Alias: (*Alias)(p),
@@ -57,6 +113,9 @@ func (p *Parameter) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	if aux.Name != "" {
+		p.Name = aux.Name
+	}
 
 	tr, err := unmarshalTypeOrRef(data)
 	if err != nil {
@@ -67,7 +126,32 @@ func (p *Parameter) UnmarshalJSON(data []byte) error {
 	lowerType := strings.ToLower(p.Type)
 	p.Secure = lowerType == secureStringType || lowerType == secureObjectType
 
-	return nil
+	p.Properties, p.Sealed, err = unmarshalObjectProperties(data)
+	return err
+}
+
+// unmarshalObjectProperties unmarshals the properties of an object type, and whether it is sealed,
+// which is derived from "additionalProperties": false.
+func unmarshalObjectProperties(data []byte) ([]UserDefinedDataTypeProperty, bool, error) {
+	var aux struct {
+		Properties           map[string]UserDefinedDataTypeProperty `json:"properties"`
+		AdditionalProperties any                                    `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return nil, false, err
+	}
+
+	sealed := false
+	if boolVal, ok := aux.AdditionalProperties.(bool); ok {
+		sealed = !boolVal
+	}
+
+	var properties []UserDefinedDataTypeProperty
+	for name, property := range aux.Properties {
+		property.Name = name
+		properties = append(properties, property)
+	}
+	return properties, sealed, nil
 }
 
 // UnmarshalJSON unmarshals a JSON object into an Output.
@@ -103,7 +187,6 @@ func (o *Output) UnmarshalJSON(data []byte) error {
 func (u *UserDefinedDataType) UnmarshalJSON(data []byte) error {
 	type Alias UserDefinedDataType
 	aux := &struct {
-		Properties map[string]UserDefinedDataTypeProperty `json:"properties"`
 		*Alias
 	}{
 		Alias: (*Alias)(u),
@@ -118,24 +201,8 @@ func (u *UserDefinedDataType) UnmarshalJSON(data []byte) error {
 	}
 	u.Type = tr
 
-	// Derive Sealed: additionalProperties=false means no extra properties are allowed.
-	// Parse separately to avoid struct field alignment issues with the composite aux struct.
-	var sealedCheck struct {
-		AdditionalProperties any `json:"additionalProperties"`
-	}
-	if err := json.Unmarshal(data, &sealedCheck); err != nil {
-		return err
-	}
-	if boolVal, ok := sealedCheck.AdditionalProperties.(bool); ok {
-		u.Sealed = !boolVal
-	}
-
-	for name, property := range aux.Properties {
-		property.Name = name
-		u.Properties = append(u.Properties, property)
-	}
-
-	return nil
+	u.Properties, u.Sealed, err = unmarshalObjectProperties(data)
+	return err
 }
 
 // UnmarshalJSON unmarshals a JSON object into a UserDefinedDataTypeProperty.
@@ -183,8 +250,29 @@ func unmarshalExportedVariables(data []byte) (map[string]string, error) {
 	return exported, nil
 }
 
+// targetScopeFromSchema returns the target scope corresponding to the $schema of an ARM template,
+// e.g. ".../subscriptionDeploymentTemplate.json#" for SubscriptionScope.
+// It returns an empty TargetScope if the schema is unknown.
+func targetScopeFromSchema(schema string) TargetScope {
+	schema = strings.ToLower(schema)
+	switch {
+	case strings.Contains(schema, "/subscriptiondeploymenttemplate.json"):
+		return SubscriptionScope
+	case strings.Contains(schema, "/managementgroupdeploymenttemplate.json"):
+		return ManagementGroupScope
+	case strings.Contains(schema, "/tenantdeploymenttemplate.json"):
+		return TenantScope
+	case strings.Contains(schema, "/deploymenttemplate.json"):
+		return ResourceGroupScope
+	default:
+		return ""
+	}
+}
+
 // UnmarshalJSON unmarshals a JSON object into a Template.
 //
+// The target scope is derived from the $schema of the template.
+//
 // The parameters, data types, variables, outputs, and functions are unmarshalled
 // into slices of Parameter, UserDefinedDataType, Variable, Output, and
 // UserDefinedFunction, respectively.
@@ -199,12 +287,13 @@ func (t *Template) UnmarshalJSON(data []byte) error { //nolint:gocyclo // Comple
 	// copyOperation represents a copy operation in the variables section of a Bicep file
 	type copyOperation struct {
 		Name  string `json:"name"`
-		Count string `json:"count"`
+		Count any    `json:"count"`
 		Input any    `json:"input"`
 	}
 
 	type Alias Template
 	aux := &struct {
+		Schema     string                         `json:"$schema"`
 		Parameters map[string]Parameter           `json:"parameters"`
 		DataTypes  map[string]UserDefinedDataType `json:"definitions"`
 		Variables  map[string]any                 `json:"variables"`
@@ -217,6 +306,7 @@ func (t *Template) UnmarshalJSON(data []byte) error { //nolint:gocyclo // Comple
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	t.TargetScope = targetScopeFromSchema(aux.Schema)
 
 	// Process variables section
 	if aux.Variables != nil {
@@ -229,8 +319,9 @@ func (t *Template) UnmarshalJSON(data []byte) error { //nolint:gocyclo // Comple
 					// Process each copy operation
 					for _, copyOp := range copyOps {
 						variable := Variable{
-							Name:  copyOp.Name,
-							Value: copyOp.Input,
+							Name:      copyOp.Name,
+							Value:     copyOp.Input,
+							LoopCount: copyOp.Count,
 						}
 						t.Variables = append(t.Variables, variable)
 					}
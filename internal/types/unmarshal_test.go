@@ -1,6 +1,7 @@
 package types
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -215,6 +216,16 @@ func TestUserDefinedDataType_UnmarshalJSON_Sealed(t *testing.T) {
 	}
 }
 
+func TestUserDefinedDataType_UnmarshalJSON_AllowedValues(t *testing.T) {
+	var uddt UserDefinedDataType
+	if err := uddt.UnmarshalJSON([]byte(`{"type": "string", "allowedValues": ["dev", "prod"]}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if expected := []any{"dev", "prod"}; !reflect.DeepEqual(uddt.Constraints().AllowedValues, expected) {
+		t.Errorf("AllowedValues: got %v, want %v", uddt.Constraints().AllowedValues, expected)
+	}
+}
+
 func TestTemplate_UnmarshalJSON_ExportedVariables(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -294,3 +305,108 @@ func TestTemplate_UnmarshalJSON_ExportedVariablesError(t *testing.T) {
 		t.Error("UnmarshalJSON() error = nil, want non-nil for malformed exported variables metadata")
 	}
 }
+
+func TestTemplate_UnmarshalJSON_TargetScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected TargetScope
+	}{
+		{
+			name:     "resource_group",
+			schema:   "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+			expected: ResourceGroupScope,
+		},
+		{
+			name:     "subscription",
+			schema:   "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+			expected: SubscriptionScope,
+		},
+		{
+			name:     "management_group",
+			schema:   "https://schema.management.azure.com/schemas/2019-08-01/managementGroupDeploymentTemplate.json#",
+			expected: ManagementGroupScope,
+		},
+		{
+			name:     "tenant",
+			schema:   "https://schema.management.azure.com/schemas/2019-08-01/tenantDeploymentTemplate.json#",
+			expected: TenantScope,
+		},
+		{
+			name:     "unknown",
+			schema:   "",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var template Template
+			if err := template.UnmarshalJSON([]byte(`{"$schema": "` + tt.schema + `"}`)); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if template.TargetScope != tt.expected {
+				t.Errorf("TargetScope: got %q, want %q", template.TargetScope, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetadata_UnmarshalJSON(t *testing.T) {
+	input := []byte(`{
+		"_EXPERIMENTAL_WARNING": "experimental",
+		"_generator": {"name": "bicep", "version": "0.38.33.27573"},
+		"name": "Storage",
+		"description": "Creates a storage account.",
+		"owner": "platform-team",
+		"version": "1.2.0",
+		"__bicep_exported_variables!": [],
+		"tags": ["storage", "core"],
+		"tier": 2
+	}`)
+
+	var metadata Metadata
+	if err := json.Unmarshal(input, &metadata); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if metadata.Name == nil || *metadata.Name != "Storage" || metadata.Version == nil || *metadata.Version != "1.2.0" {
+		t.Errorf("UnmarshalJSON() known fields = %+v", metadata)
+	}
+	expected := []MetadataItem{
+		{Key: "owner", Value: "platform-team"},
+		{Key: "version", Value: "1.2.0"},
+		{Key: "tags", Value: []any{"storage", "core"}},
+		{Key: "tier", Value: float64(2)},
+	}
+	if !reflect.DeepEqual(metadata.Custom, expected) {
+		t.Errorf("UnmarshalJSON() Custom = %#v, want %#v", metadata.Custom, expected)
+	}
+
+	value, ok := metadata.GetCustom("owner")
+	if !ok || value != "platform-team" {
+		t.Errorf("GetCustom(owner) = %v, %v", value, ok)
+	}
+	if _, ok := metadata.GetCustom("name"); ok {
+		t.Error("GetCustom(name) = true, want false for a known field")
+	}
+}
+
+func TestMetadata_MarshalJSON(t *testing.T) {
+	name := "Storage"
+	metadata := Metadata{
+		Name:   &name,
+		Custom: []MetadataItem{{Key: "owner", Value: "platform-team"}},
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{"name": "Storage", "description": nil, "owner": "platform-team"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("MarshalJSON() = %s, want %v", data, expected)
+	}
+}
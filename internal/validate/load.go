@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/christosgalano/bicep-docs/internal/bicep"
+)
+
+// ParamsFile is a parameters file: the Bicep file it references (only a .bicepparam file does, by its using statement,
+// relative to the directory of the parameters file), and its values.
+type ParamsFile struct {
+	Using  string
+	Values []Value
+}
+
+// LoadParamsFile reads a parameters file: a .bicepparam file, or an ARM parameters file (JSON) otherwise.
+func LoadParamsFile(file string) (*ParamsFile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(file) == ".bicepparam" {
+		return ParseBicepParams(string(content))
+	}
+	values, err := ParseARMParameters(content)
+	if err != nil {
+		return nil, err
+	}
+	return &ParamsFile{Values: values}, nil
+}
+
+// ParseBicepParams parses the param statements of a .bicepparam file.
+// The values that are not literals are expressions (see bicep.ParseParamsFile).
+func ParseBicepParams(content string) (*ParamsFile, error) {
+	parsed, err := bicep.ParseParamsFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters file: %w", err)
+	}
+	file := &ParamsFile{Using: parsed.Using, Values: make([]Value, len(parsed.Params))}
+	for i, param := range parsed.Params {
+		file.Values[i] = Value{Name: param.Name, Value: param.Value, Expression: param.Expression}
+	}
+	return file, nil
+}
+
+// ParseARMParameters parses the parameters of an ARM parameters file:
+//
+//	{"$schema": "...", "contentVersion": "1.0.0.0", "parameters": {"location": {"value": "westeurope"}}}
+//
+// A parameter with a Key Vault reference instead of a value is an expression. The values are sorted by name.
+func ParseARMParameters(content []byte) ([]Value, error) {
+	var file struct {
+		Parameters map[string]struct {
+			Value     json.RawMessage `json:"value"`
+			Reference json.RawMessage `json:"reference"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid parameters file: %w", err)
+	}
+
+	values := make([]Value, 0, len(file.Parameters))
+	for name, parameter := range file.Parameters {
+		value := Value{Name: name}
+		switch {
+		case parameter.Reference != nil:
+			value.Expression = true
+		case parameter.Value != nil:
+			if err := json.NewDecoder(bytes.NewReader(parameter.Value)).Decode(&value.Value); err != nil {
+				return nil, fmt.Errorf("invalid value of parameter %s: %w", name, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid parameters file: parameter %s has neither a value nor a reference", name)
+		}
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values, nil
+}
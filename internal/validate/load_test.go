@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseARMParameters(t *testing.T) {
+	content := `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "sku": {"value": "Standard_LRS"},
+    "retention": {"value": null},
+    "secret": {"reference": {"keyVault": {"id": "/subscriptions/.../vaults/kv"}, "secretName": "secret"}},
+    "zones": {"value": ["1", 2]}
+  }
+}`
+	expected := []Value{
+		{Name: "retention"},
+		{Name: "secret", Expression: true},
+		{Name: "sku", Value: "Standard_LRS"},
+		{Name: "zones", Value: []any{"1", float64(2)}},
+	}
+	got, err := ParseARMParameters([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseARMParameters() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseARMParameters() = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestParseARMParameters_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "invalid_json", content: `{"parameters":`, expected: "invalid parameters file"},
+		{name: "no_value", content: `{"parameters": {"sku": {}}}`, expected: "parameter sku has neither a value nor a reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseARMParameters([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ParseARMParameters() error = %v, expected to contain %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseBicepParams(t *testing.T) {
+	content := "using './main.bicep'\n\nparam sku = 'Standard_LRS'\nparam secret = readEnvironmentVariable('SECRET')\n"
+	expected := &ParamsFile{
+		Using: "./main.bicep",
+		Values: []Value{
+			{Name: "sku", Value: "Standard_LRS"},
+			{Name: "secret", Expression: true},
+		},
+	}
+	got, err := ParseBicepParams(content)
+	if err != nil {
+		t.Fatalf("ParseBicepParams() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseBicepParams() = %+v, expected %+v", got, expected)
+	}
+}
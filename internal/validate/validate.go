@@ -0,0 +1,350 @@
+/*
+Package validate checks the values of a parameters file (an ARM parameters.json file or a .bicepparam file)
+against the parameters of a module, without deploying it: the required parameters, the parameters the module
+does not declare, the types of the values, their allowed values, and their length and value constraints.
+*/
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+// IssueKind is the kind of an issue of a parameters file.
+type IssueKind string
+
+const (
+	MissingParameter IssueKind = "missing"        // MissingParameter is a required parameter (or property) without a value
+	UnknownParameter IssueKind = "unknown"        // UnknownParameter is a parameter (or property of a sealed type) that is not declared
+	TypeMismatch     IssueKind = "type"           // TypeMismatch is a value of another type than the declared one
+	NotAllowed       IssueKind = "allowed-values" // NotAllowed is a value that is not one of the allowed values
+	LengthViolation  IssueKind = "length"         // LengthViolation is a string or an array outside the length constraints
+	ValueViolation   IssueKind = "value"          // ValueViolation is an integer outside the value constraints
+)
+
+// Value is the value of a parameter in a parameters file. The value is decoded as from JSON.
+// An expression (e.g. readEnvironmentVariable('TOKEN') in a .bicepparam file, or a Key Vault reference
+// in a parameters.json file) is only known at deployment time, so it is not checked.
+type Value struct {
+	Name       string
+	Value      any
+	Expression bool
+}
+
+// Issue is an issue of a parameters file. The parameter is the path of the value within the parameters
+// (e.g. "config.tier" for a property, or "zones[1]" for an item of an array).
+type Issue struct {
+	Parameter string    `json:"parameter"`
+	Kind      IssueKind `json:"kind"`
+	Message   string    `json:"message"`
+}
+
+// String returns the issue as "parameter: message".
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Parameter, i.Message)
+}
+
+// Result is the result of the validation of a parameters file: its issues, in the order of the parameters,
+// and the parameters whose values are expressions, which are not checked.
+type Result struct {
+	Issues    []Issue  `json:"issues"`
+	Unchecked []string `json:"unchecked"`
+}
+
+// Valid reports whether the parameters file has no issues.
+func (r *Result) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// validator checks values, resolving the user-defined data types of a template by name.
+type validator struct {
+	dataTypes []types.UserDefinedDataType
+	result    *Result
+}
+
+// Validate checks the values of a parameters file against the parameters of a template.
+//
+// Every required parameter (without a default value and not nullable) must have a value, and every value must
+// be of a declared parameter. A value must be of the type of its parameter (null only for a nullable parameter),
+// one of its allowed values (every item, for an array), and within its length and value constraints.
+// The values of user-defined data types (including unions of literals, e.g. type env = 'dev' | 'prod') and of
+// the inline object types of parameters are checked recursively: their required properties, the properties
+// of sealed types, and the constraints of their properties and items.
+func Validate(template *types.Template, values []Value) *Result {
+	v := &validator{dataTypes: template.UserDefinedDataTypes, result: &Result{Issues: []Issue{}, Unchecked: []string{}}}
+
+	byName := make(map[string]*Value, len(values))
+	for i := range values {
+		byName[values[i].Name] = &values[i]
+	}
+
+	for i := range template.Parameters {
+		parameter := &template.Parameters[i]
+		value, ok := byName[parameter.Name]
+		switch {
+		case !ok:
+			if parameter.IsRequired() {
+				v.add(parameter.Name, MissingParameter, "missing required parameter")
+			}
+		case value.Expression:
+			v.result.Unchecked = append(v.result.Unchecked, parameter.Name)
+		default:
+			v.check(parameter.Name, parameter.Constraints(), value.Value)
+			// The inline object type of a parameter (e.g. param config { tier: string }) is checked as a data type
+			if object, ok := value.Value.(map[string]any); ok {
+				v.checkProperties(parameter.Name, "the sealed type of "+parameter.Name, parameter.Properties, parameter.Sealed, object)
+			}
+		}
+	}
+
+	for i := range values {
+		if !declared(template.Parameters, values[i].Name) {
+			v.add(values[i].Name, UnknownParameter, "unknown parameter, not declared by the module")
+		}
+	}
+
+	return v.result
+}
+
+// declared reports whether a parameter is declared.
+func declared(parameters []types.Parameter, name string) bool {
+	for i := range parameters {
+		if parameters[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// add records an issue.
+func (v *validator) add(path string, kind IssueKind, format string, args ...any) {
+	v.result.Issues = append(v.result.Issues, Issue{Parameter: path, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// check checks a value against a type and its constraints.
+func (v *validator) check(path string, c *types.Constraints, value any) {
+	if value == nil {
+		if !c.Nullable && !strings.EqualFold(c.Type, "any") {
+			v.add(path, TypeMismatch, "expected %s, got null", typeName(c.Type))
+		}
+		return
+	}
+
+	if name, ok := types.DefinitionName(c.Type); ok {
+		v.checkDataType(path, name, value)
+		// The constraints of the parameter apply in addition to the ones of the data type
+		v.checkConstraints(path, c, value)
+		return
+	}
+
+	if !matchesType(c.Type, value) {
+		v.add(path, TypeMismatch, "expected %s, got %s", typeName(c.Type), valueTypeName(value))
+		return
+	}
+	v.checkConstraints(path, c, value)
+
+	if items, ok := value.([]any); ok && c.Items != nil {
+		item := &types.Constraints{Type: "any", Nullable: true}
+		if c.Items.Ref != nil {
+			item.Type, item.Nullable = *c.Items.Ref, false
+		} else if c.Items.Type != nil {
+			item.Type, item.Nullable = *c.Items.Type, false
+		}
+		for i, value := range items {
+			v.check(fmt.Sprintf("%s[%d]", path, i), item, value)
+		}
+	}
+}
+
+// checkConstraints checks a value of the right type against the allowed values and the length and value constraints.
+func (v *validator) checkConstraints(path string, c *types.Constraints, value any) {
+	if len(c.AllowedValues) > 0 {
+		if items, ok := value.([]any); ok {
+			for i, item := range items {
+				if !allowed(c.AllowedValues, item) {
+					v.add(fmt.Sprintf("%s[%d]", path, i), NotAllowed, "%s is not one of the allowed values %s", formatValue(item), formatValue(c.AllowedValues))
+				}
+			}
+		} else if !allowed(c.AllowedValues, value) {
+			v.add(path, NotAllowed, "%s is not one of the allowed values %s", formatValue(value), formatValue(c.AllowedValues))
+		}
+	}
+
+	length := -1
+	switch value := value.(type) {
+	case string:
+		length = utf8.RuneCountInString(value)
+	case []any:
+		length = len(value)
+	case float64:
+		if c.MinValue != nil && value < float64(*c.MinValue) {
+			v.add(path, ValueViolation, "%s is less than the minimum value %d", formatValue(value), *c.MinValue)
+		}
+		if c.MaxValue != nil && value > float64(*c.MaxValue) {
+			v.add(path, ValueViolation, "%s is greater than the maximum value %d", formatValue(value), *c.MaxValue)
+		}
+	}
+	if length >= 0 {
+		if c.MinLength != nil && length < *c.MinLength {
+			v.add(path, LengthViolation, "length %d is less than the minimum length %d", length, *c.MinLength)
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			v.add(path, LengthViolation, "length %d is greater than the maximum length %d", length, *c.MaxLength)
+		}
+	}
+}
+
+// checkDataType checks a value against a user-defined data type. A data type that is not in the template
+// (e.g. one imported from another file that the compiler did not inline) is not checked.
+func (v *validator) checkDataType(path, name string, value any) {
+	var dataType *types.UserDefinedDataType
+	for i := range v.dataTypes {
+		if v.dataTypes[i].Name == name {
+			dataType = &v.dataTypes[i]
+			break
+		}
+	}
+	if dataType == nil {
+		return
+	}
+
+	v.check(path, dataType.Constraints(), value)
+
+	if object, ok := value.(map[string]any); ok {
+		v.checkProperties(path, "the sealed type "+dataType.Name, dataType.Properties, dataType.Sealed, object)
+	}
+}
+
+// checkProperties checks the properties of an object against the properties of its object type: its required properties,
+// the constraints of its properties, and, if the type (described by sealedType for a message) is sealed, its undeclared properties.
+// An object type without properties is not checked.
+func (v *validator) checkProperties(path, sealedType string, properties []types.UserDefinedDataTypeProperty, sealed bool, object map[string]any) {
+	if len(properties) == 0 {
+		return
+	}
+	for i := range properties {
+		property := &properties[i]
+		propertyValue, ok := object[property.Name]
+		if !ok {
+			if !property.Nullable {
+				v.add(path+"."+property.Name, MissingParameter, "missing required property")
+			}
+			continue
+		}
+		v.check(path+"."+property.Name, property.Constraints(), propertyValue)
+	}
+	if sealed {
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !hasProperty(properties, key) {
+				v.add(path+"."+key, UnknownParameter, "unknown property, not declared by %s", sealedType)
+			}
+		}
+	}
+}
+
+// hasProperty reports whether a property is declared.
+func hasProperty(properties []types.UserDefinedDataTypeProperty, name string) bool {
+	for i := range properties {
+		if properties[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesType reports whether a value (decoded as from JSON) is of an ARM template type.
+// The 'any' type and unknown types match every value.
+func matchesType(armType string, value any) bool {
+	switch strings.ToLower(armType) {
+	case "string", "securestring":
+		_, ok := value.(string)
+		return ok
+	case "int":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "object", "secureobject":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// typeName returns the name of an ARM template type for a message, e.g. "a string".
+func typeName(armType string) string {
+	switch strings.ToLower(armType) {
+	case "string", "securestring":
+		return "a string"
+	case "int":
+		return "an integer"
+	case "bool":
+		return "a boolean"
+	case "object", "secureobject":
+		return "an object"
+	case "array":
+		return "an array"
+	default:
+		if name, ok := types.DefinitionName(armType); ok {
+			return "a value of type " + name
+		}
+		return armType
+	}
+}
+
+// valueTypeName returns the name of the type of a value decoded as from JSON, for a message.
+func valueTypeName(value any) string {
+	switch value := value.(type) {
+	case string:
+		return "a string"
+	case float64:
+		if value == math.Trunc(value) {
+			return "an integer"
+		}
+		return "a number"
+	case bool:
+		return "a boolean"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// allowed reports whether a value is one of the allowed values.
+func allowed(allowedValues []any, value any) bool {
+	for _, allowedValue := range allowedValues {
+		if reflect.DeepEqual(allowedValue, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue returns a value as JSON for a message.
+func formatValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
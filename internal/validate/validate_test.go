@@ -0,0 +1,156 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christosgalano/bicep-docs/internal/types"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var template = &types.Template{
+	Parameters: []types.Parameter{
+		{Name: "name", Type: "string", MinLength: intPtr(3), MaxLength: intPtr(24)},
+		{Name: "location", Type: "string", DefaultValue: "[resourceGroup().location]"},
+		{Name: "sku", Type: "string", DefaultValue: "Standard_LRS", AllowedValues: []any{"Standard_LRS", "Premium_LRS"}},
+		{Name: "retention", Type: "int", Nullable: true, MinValue: intPtr(1), MaxValue: intPtr(365)},
+		{Name: "zones", Type: "array", DefaultValue: []any{}, AllowedValues: []any{"1", "2", "3"}},
+		{Name: "config", Type: "#/definitions/config", Nullable: true},
+		{Name: "rules", Type: "array", DefaultValue: []any{}, Items: &types.Items{Ref: strPtr("#/definitions/rule")}},
+		{Name: "secret", Type: "securestring", DefaultValue: ""},
+		{Name: "env", Type: "#/definitions/env", DefaultValue: "dev"},
+		{
+			Name:     "settings",
+			Type:     "object",
+			Nullable: true,
+			Sealed:   true,
+			Properties: []types.UserDefinedDataTypeProperty{
+				{Name: "tier", Type: "string"},
+				{Name: "size", Type: "int", MinValue: intPtr(1)},
+			},
+		},
+	},
+	UserDefinedDataTypes: []types.UserDefinedDataType{
+		{
+			Name:   "config",
+			Type:   "object",
+			Sealed: true,
+			Properties: []types.UserDefinedDataTypeProperty{
+				{Name: "tier", Type: "string", AllowedValues: []any{"Hot", "Cool"}, Nullable: true},
+				{Name: "enabled", Type: "bool"},
+			},
+		},
+		{Name: "rule", Type: "string", MinLength: intPtr(1)},
+		{Name: "env", Type: "string", AllowedValues: []any{"dev", "prod"}},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name              string
+		values            []Value
+		expectedIssues    []Issue
+		expectedUnchecked []string
+	}{
+		{
+			name: "valid",
+			values: []Value{
+				{Name: "name", Value: "stdata"},
+				{Name: "sku", Value: "Premium_LRS"},
+				{Name: "retention", Value: nil},
+				{Name: "zones", Value: []any{"1", "3"}},
+				{Name: "config", Value: map[string]any{"enabled": true}},
+				{Name: "rules", Value: []any{"a", "b"}},
+				{Name: "secret", Expression: true},
+				{Name: "env", Value: "prod"},
+				{Name: "settings", Value: map[string]any{"tier": "premium", "size": float64(2)}},
+			},
+			expectedIssues:    []Issue{},
+			expectedUnchecked: []string{"secret"},
+		},
+		{
+			name: "missing_and_unknown",
+			values: []Value{
+				{Name: "location", Value: "westeurope"},
+				{Name: "size", Value: float64(3)},
+			},
+			expectedIssues: []Issue{
+				{Parameter: "name", Kind: MissingParameter, Message: "missing required parameter"},
+				{Parameter: "size", Kind: UnknownParameter, Message: "unknown parameter, not declared by the module"},
+			},
+			expectedUnchecked: []string{},
+		},
+		{
+			name: "constraints",
+			values: []Value{
+				{Name: "name", Value: "st"},
+				{Name: "location", Value: float64(1)},
+				{Name: "sku", Value: "Basic"},
+				{Name: "retention", Value: float64(400)},
+				{Name: "zones", Value: []any{"1", "4"}},
+				{Name: "secret", Value: nil},
+			},
+			expectedIssues: []Issue{
+				{Parameter: "name", Kind: LengthViolation, Message: "length 2 is less than the minimum length 3"},
+				{Parameter: "location", Kind: TypeMismatch, Message: "expected a string, got an integer"},
+				{Parameter: "sku", Kind: NotAllowed, Message: `"Basic" is not one of the allowed values ["Standard_LRS","Premium_LRS"]`},
+				{Parameter: "retention", Kind: ValueViolation, Message: "400 is greater than the maximum value 365"},
+				{Parameter: "zones[1]", Kind: NotAllowed, Message: `"4" is not one of the allowed values ["1","2","3"]`},
+				{Parameter: "secret", Kind: TypeMismatch, Message: "expected a string, got null"},
+			},
+			expectedUnchecked: []string{},
+		},
+		{
+			name: "data_types",
+			values: []Value{
+				{Name: "name", Value: "stdata"},
+				{Name: "config", Value: map[string]any{"tier": "Archive", "replicas": float64(2)}},
+				{Name: "rules", Value: []any{"a", "", true}},
+			},
+			expectedIssues: []Issue{
+				{Parameter: "config.tier", Kind: NotAllowed, Message: `"Archive" is not one of the allowed values ["Hot","Cool"]`},
+				{Parameter: "config.enabled", Kind: MissingParameter, Message: "missing required property"},
+				{Parameter: "config.replicas", Kind: UnknownParameter, Message: "unknown property, not declared by the sealed type config"},
+				{Parameter: "rules[1]", Kind: LengthViolation, Message: "length 0 is less than the minimum length 1"},
+				{Parameter: "rules[2]", Kind: TypeMismatch, Message: "expected a string, got a boolean"},
+			},
+			expectedUnchecked: []string{},
+		},
+		{
+			name: "union_and_inline_object",
+			values: []Value{
+				{Name: "name", Value: "stdata"},
+				{Name: "env", Value: "staging"},
+				{Name: "settings", Value: map[string]any{"size": float64(0), "zone": "1"}},
+			},
+			expectedIssues: []Issue{
+				{Parameter: "env", Kind: NotAllowed, Message: `"staging" is not one of the allowed values ["dev","prod"]`},
+				{Parameter: "settings.tier", Kind: MissingParameter, Message: "missing required property"},
+				{Parameter: "settings.size", Kind: ValueViolation, Message: "0 is less than the minimum value 1"},
+				{Parameter: "settings.zone", Kind: UnknownParameter, Message: "unknown property, not declared by the sealed type of settings"},
+			},
+			expectedUnchecked: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validate(template, tt.values)
+			if !reflect.DeepEqual(got.Issues, tt.expectedIssues) {
+				t.Errorf("Validate() issues = %v, expected %v", got.Issues, tt.expectedIssues)
+			}
+			if !reflect.DeepEqual(got.Unchecked, tt.expectedUnchecked) {
+				t.Errorf("Validate() unchecked = %v, expected %v", got.Unchecked, tt.expectedUnchecked)
+			}
+			if got.Valid() != (len(tt.expectedIssues) == 0) {
+				t.Errorf("Valid() = %v, expected %v", got.Valid(), len(tt.expectedIssues) == 0)
+			}
+		})
+	}
+}